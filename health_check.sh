@@ -140,6 +140,28 @@ else
 fi
 echo ""
 
+# ------------------------------------------------------------------------------
+# 6. Check running bot readiness (/readyz on the metrics listener)
+# ------------------------------------------------------------------------------
+echo -e "${CYAN}[CHECK 6] Bot Readiness...${NC}"
+READY_URL="${READY_URL:-http://127.0.0.1:9090/readyz}"
+if ! command -v curl &> /dev/null; then
+    echo -e "${YELLOW}⚠️  curl not installed, skipping readiness probe${NC}"
+else
+    READY_CODE=$(curl -s -o /tmp/bot_readyz.json -w '%{http_code}' --max-time 5 "$READY_URL")
+    if [ "$READY_CODE" = "200" ]; then
+        echo -e "${GREEN}✓ Bot is ready (${READY_URL})${NC}"
+    elif [ "$READY_CODE" = "000" ]; then
+        echo -e "${YELLOW}⚠️  Bot is not running or metrics listener unreachable (${READY_URL})${NC}"
+    else
+        echo -e "${RED}❌ Bot reports not ready (HTTP ${READY_CODE}):${NC}"
+        cat /tmp/bot_readyz.json
+        echo ""
+        ISSUES=$((ISSUES + 1))
+    fi
+fi
+echo ""
+
 # ------------------------------------------------------------------------------
 # Summary
 # ------------------------------------------------------------------------------
@@ -28,6 +28,7 @@ type Bot struct {
 	Redis             *redis.Client
 	Service           *services.GiveawayService
 	EconomyService    *services.EconomyService
+	InviteService     *services.InviteService
 	EconomyEvents     *EconomyEvents
 	ShopCommands      *shop.ShopCommand
 	AdminShopCommands *shop.AdminShopCommand
@@ -164,6 +165,7 @@ func New(token string, db *database.Database, rdb *redis.Client) (*Bot, error) {
 		Redis:             rdb,
 		Service:           svc,
 		EconomyService:    economySvc,
+		InviteService:     services.NewInviteService(s, db, economySvc),
 		EconomyEvents:     economyEvents,
 		ShopCommands:      shop.NewShopCommand(db, economySvc),
 		AdminShopCommands: shop.NewAdminShopCommand(db),
@@ -182,6 +184,9 @@ func New(token string, db *database.Database, rdb *redis.Client) (*Bot, error) {
 	s.AddHandler(b.UnifiedMessageCreate)         // Consolidated message handler
 	s.AddHandler(b.UnifiedVoiceStateUpdate)      // Consolidated voice handler
 	s.AddHandler(b.GuildCreate)                  // Handler for guild creation (command registration)
+	s.AddHandler(b.GuildMemberAdd)               // Invite tracking
+	s.AddHandler(b.GuildMemberRemove)            // Invite tracking
+	s.AddHandler(b.InviteCreate)                 // Invite tracking
 
 	return b, nil
 }
@@ -254,8 +259,23 @@ func (b *Bot) Start() error {
 		log.Printf("Failed to sync giveaway queue: %v", err)
 	}
 
+	if err := b.Service.SyncClaimQueue(); err != nil {
+		log.Printf("Failed to sync claim queue: %v", err)
+	}
+
+	if err := b.Service.SyncStartQueue(); err != nil {
+		log.Printf("Failed to sync start queue: %v", err)
+	}
+
+	if err := b.Service.SyncPrizeRoleQueue(); err != nil {
+		log.Printf("Failed to sync prize role queue: %v", err)
+	}
+
 	// Start giveaway ticker
 	go b.GiveawayTicker()
+	go b.ClaimTicker()
+	go b.StartTicker()
+	go b.PrizeRoleTicker()
 
 	// Start message count flusher
 	go b.MessageCountFlusher()
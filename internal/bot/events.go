@@ -1,14 +1,17 @@
 package bot
 
 import (
-	"bytes"
 	"context"
+	"database/sql"
 	"discord-giveaway-bot/internal/commands"
 	"discord-giveaway-bot/internal/commands/antinuke"
 	"discord-giveaway-bot/internal/commands/economy"
 	"discord-giveaway-bot/internal/commands/framework"
 	"discord-giveaway-bot/internal/commands/voice"
+	"discord-giveaway-bot/internal/models"
+	"discord-giveaway-bot/internal/services"
 	"discord-giveaway-bot/internal/utils"
+	"errors"
 	"fmt"
 	"log"
 	"strconv"
@@ -55,6 +58,13 @@ func (b *Bot) GuildCreate(s *discordgo.Session, g *discordgo.GuildCreate) {
 	} else {
 		log.Printf("Registered giveaway commands for guild %s", g.Name)
 	}
+
+	// Seed the invite cache so the first join can be attributed
+	go func() {
+		if err := b.InviteService.LoadGuild(g.ID); err != nil {
+			log.Printf("Failed to load invites for guild %s: %v", g.ID, err)
+		}
+	}()
 }
 
 func (b *Bot) InteractionCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
@@ -76,6 +86,34 @@ func (b *Bot) InteractionCreate(s *discordgo.Session, i *discordgo.InteractionCr
 			b.HandleGList(i)
 		case "gcancel":
 			b.HandleGCancel(i)
+		case "gbuy":
+			b.HandleGBuy(i)
+		case "gverify":
+			b.HandleGVerify(i)
+		case "gedit":
+			b.HandleGEdit(i)
+		case "gpause":
+			b.HandleGPause(i)
+		case "gresume":
+			b.HandleGResume(i)
+		case "gscheduled":
+			b.HandleGScheduled(i)
+		case "grecurring":
+			b.HandleGRecurring(i)
+		case "gtemplate":
+			b.HandleGTemplate(i)
+		case "gwins":
+			b.HandleGWins(i)
+		case "glimits":
+			b.HandleGLimits(i)
+		case "gparticipants":
+			b.HandleGParticipants(i)
+		case "ginfo":
+			b.HandleGInfo(i)
+		case "ghistory":
+			b.HandleGHistory(i)
+		case "gstats":
+			b.HandleGStats(i)
 		// Economy Commands
 		case "daily":
 			economy.DailyHandler(s, i, b.EconomyService)
@@ -88,7 +126,7 @@ func (b *Bot) InteractionCreate(s *discordgo.Session, i *discordgo.InteractionCr
 		case "leaderboard":
 			economy.LeaderboardHandler(s, i, b.EconomyService)
 		case "invites":
-			economy.InvitesHandler(s, i, b.EconomyService)
+			economy.InvitesHandler(s, i, b.InviteService)
 		case "coinflip":
 			economy.CoinflipHandler(s, i, b.EconomyService)
 		case "economy":
@@ -200,6 +238,16 @@ func (b *Bot) InteractionCreate(s *discordgo.Session, i *discordgo.InteractionCr
 					},
 				},
 			})
+		} else if strings.HasPrefix(customID, "enter_giveaway_") {
+			b.HandleGiveawayEnterButton(i)
+		} else if strings.HasPrefix(customID, "leave_giveaway_") {
+			b.HandleGiveawayLeaveButton(i)
+		} else if strings.HasPrefix(customID, "claim_prize_") {
+			b.HandleClaimPrizeButton(i)
+		} else if strings.HasPrefix(customID, "gparticipants_") {
+			b.HandleGParticipantsPage(i)
+		} else if strings.HasPrefix(customID, "ghistory_") {
+			b.HandleGHistoryPage(i)
 		} else if customID == "select_allowed_channels" {
 			economy.HandleChannelSelect(s, i, b.EconomyService)
 		} else if strings.HasPrefix(customID, "give_") {
@@ -210,6 +258,8 @@ func (b *Bot) InteractionCreate(s *discordgo.Session, i *discordgo.InteractionCr
 			b.AdminShopCommands.HandleEditButton(s, i)
 		} else if strings.HasPrefix(customID, "shop_role_select_") {
 			b.AdminShopCommands.HandleEditRoleSelect(s, i)
+		} else if strings.HasPrefix(customID, "antinuke_import_") {
+			antinuke.HandleImportButton(s, i, b.DB)
 		} else if customID == "help_category_select" {
 			commands.HandleHelpSelect(s, i)
 			// } else if strings.HasPrefix(customID, "whitelist_add_select_") {
@@ -259,17 +309,28 @@ func (b *Bot) InteractionCreate(s *discordgo.Session, i *discordgo.InteractionCr
 					return
 				}
 
-				// Add participant
-				b.DB.AddParticipant(giveawayID, userID)
-				b.Service.UpdateGiveawayMessage(g)
-
+				// Captcha passed: charge the fee (if any) and add the participant.
 				// We do NOT add reaction back because we never removed it.
-				// Just confirm success.
+				res := b.Service.CompleteEntry(g, g.GuildID, userID)
+				content := res.Message
+				switch res.Status {
+				case services.EntryAdded:
+					content = fmt.Sprintf("✅ Captcha verified! You've successfully entered the giveaway for **%s**!", g.Prize)
+					if res.Fee > 0 {
+						content += "\n" + res.Message
+					}
+				case services.EntryAlreadyEntered, services.EntryInProgress:
+				default:
+					if g.EntryMode != models.EntryModeButton {
+						s.MessageReactionRemove(g.ChannelID, g.MessageID, utils.GiveawayEmojiAPIName(g.Emoji), userID)
+					}
+				}
 
 				s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 					Type: discordgo.InteractionResponseChannelMessageWithSource,
 					Data: &discordgo.InteractionResponseData{
-						Content: fmt.Sprintf(" Captcha verified! You've successfully entered the giveaway for **%s**!", g.Prize),
+						Content: content,
+						Flags:   discordgo.MessageFlagsEphemeral,
 					},
 				})
 			} else {
@@ -277,13 +338,14 @@ func (b *Bot) InteractionCreate(s *discordgo.Session, i *discordgo.InteractionCr
 					Type: discordgo.InteractionResponseChannelMessageWithSource,
 					Data: &discordgo.InteractionResponseData{
 						Content: "❌ Invalid captcha code. Entry declined.",
+						Flags:   discordgo.MessageFlagsEphemeral,
 					},
 				})
 
 				// Remove reaction from original message on failure
 				g, err := b.DB.GetGiveawayByID(giveawayID)
-				if err == nil && g != nil {
-					s.MessageReactionRemove(g.ChannelID, g.MessageID, "🎉", userID)
+				if err == nil && g != nil && g.EntryMode != models.EntryModeButton {
+					s.MessageReactionRemove(g.ChannelID, g.MessageID, utils.GiveawayEmojiAPIName(g.Emoji), userID)
 				}
 			}
 		}
@@ -291,315 +353,77 @@ func (b *Bot) InteractionCreate(s *discordgo.Session, i *discordgo.InteractionCr
 		switch i.ApplicationCommandData().Name {
 		case "edit-item":
 			b.AdminShopCommands.HandleAutocomplete(s, i)
+		case "gcreate", "gtemplate", "grecurring":
+			commands.HandleTemplateAutocomplete(s, i, b.Service)
 		}
 	}
 }
 
 func (b *Bot) MessageReactionAdd(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
-	if r.UserID == s.State.User.ID {
+	if r.UserID == s.State.User.ID || !b.Service.IsReactionGiveaway(r.MessageID) {
 		return
 	}
 
-	if r.Emoji.Name != "🎉" {
-		return
-	}
-
-	log.Printf("Reaction added by %s on message %s", r.UserID, r.MessageID)
-
-	g, err := b.DB.GetGiveaway(r.MessageID)
+	// Look the giveaway up first: the entry emoji is per-giveaway (custom emoji are matched by ID)
+	g, err := b.DB.GetGiveawayFast(context.Background(), r.MessageID)
 	if err != nil {
-		log.Printf("Error getting giveaway: %v", err)
-		return
-	}
-	if g == nil {
-		log.Printf("Giveaway not found for message %s", r.MessageID)
-		return
-	}
-	if g.Ended {
-		log.Printf("Giveaway %d ended", g.ID)
-		return
-	}
-
-	log.Printf("Processing entry for giveaway %d (Fee: %d)", g.ID, g.EntryFee)
-
-	// Check if already a participant (using fast prepared statement with context)
-	ctx := context.Background()
-	isParticipant, _ := b.DB.IsParticipantFast(ctx, g.ID, r.UserID)
-	if isParticipant {
-		log.Printf("User %s is already a participant", r.UserID)
-		return
-	}
-
-	// Check requirements
-	res, err := utils.CheckAllRequirements(s, b.DB, r.GuildID, r.UserID, g)
-	if err != nil {
-		log.Printf("Error checking requirements: %v", err)
-		return
-	}
-
-	if !res.Passed {
-		log.Printf("User %s failed requirements: %s", r.UserID, res.Reason)
-		s.MessageReactionRemove(r.ChannelID, r.MessageID, r.Emoji.Name, r.UserID)
-		dm, err := s.UserChannelCreate(r.UserID)
-		if err == nil {
-			s.ChannelMessageSend(dm.ID, fmt.Sprintf("❌ You cannot enter the giveaway for **%s**: %s", g.Prize, res.Reason))
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Error getting giveaway: %v", err)
 		}
 		return
 	}
-
-	// Captcha handling
-	if g.CaptchaRequirement {
-		// Do NOT remove reaction yet.
-
-		captcha, err := utils.GenerateCaptcha()
-		if err != nil {
-			log.Printf("Error generating captcha: %v", err)
-			return
-		}
-
-		b.DB.CreateCaptchaSession(r.UserID, g.ID, captcha.Code)
-
-		// Create DM channel
-		dm, err := s.UserChannelCreate(r.UserID)
-		if err != nil {
-			log.Printf("Failed to create DM channel: %v", err)
-			// If we can't DM, we must remove reaction and maybe try to tell them?
-			s.MessageReactionRemove(r.ChannelID, r.MessageID, r.Emoji.Name, r.UserID)
-			return
-		}
-
-		btn := discordgo.Button{
-			Label:    "Enter Captcha Code",
-			Style:    discordgo.PrimaryButton,
-			CustomID: fmt.Sprintf("captcha_%d_%s", g.ID, r.UserID),
-			Emoji:    &discordgo.ComponentEmoji{Name: "✍️"},
-		}
-
-		embed := &discordgo.MessageEmbed{
-			Title:       "Captcha Verification",
-			Description: fmt.Sprintf("Please solve the captcha to enter the giveaway for **%s**.\nYou have **1 minute**.", g.Prize),
-			Color:       0x0000FF,
-			Image: &discordgo.MessageEmbedImage{
-				URL: "attachment://captcha.png",
-			},
-		}
-
-		msg := &discordgo.MessageSend{
-			Embeds: []*discordgo.MessageEmbed{embed},
-			Files: []*discordgo.File{
-				{
-					Name:   "captcha.png",
-					Reader: bytes.NewReader(captcha.Image),
-				},
-			},
-			Components: []discordgo.MessageComponent{
-				discordgo.ActionsRow{
-					Components: []discordgo.MessageComponent{btn},
-				},
-			},
-		}
-
-		m, err := s.ChannelMessageSendComplex(dm.ID, msg)
-		if err != nil {
-			log.Printf("Failed to send captcha DM: %v", err)
-			// Remove reaction if we can't send DM
-			s.MessageReactionRemove(r.ChannelID, r.MessageID, r.Emoji.Name, r.UserID)
-			return
-		}
-
-		// 1 minute timeout handler
-		go func() {
-			time.Sleep(1 * time.Minute)
-
-			isPart, _ := b.DB.IsParticipant(g.ID, r.UserID)
-			if !isPart {
-				// Timeout!
-				s.ChannelMessageDelete(dm.ID, m.ID)
-				embed := &discordgo.MessageEmbed{
-					Description: fmt.Sprintf("❌ Time expired! Entry declined for **%s**.", g.Prize),
-					Color:       0xFF0000,
-				}
-				s.ChannelMessageSendEmbed(dm.ID, embed)
-
-				// Remove reaction
-				s.MessageReactionRemove(r.ChannelID, r.MessageID, "🎉", r.UserID)
-			}
-		}()
-
+	if g == nil || g.EntryMode == models.EntryModeButton || !utils.GiveawayEmojiMatches(g.Emoji, r.Emoji) {
 		return
 	}
 
-	// Entry Fee Handling
-	if g.EntryFee > 0 {
-		log.Printf("Checking entry fee for user %s (Balance check)", r.UserID)
-		// Use Service to get balance (checks cache)
-		balance, err := b.EconomyService.GetUserBalance(r.GuildID, r.UserID)
-		if err != nil {
-			log.Printf("Error getting economy user: %v", err)
-			return
-		}
-
-		if balance < int64(g.EntryFee) {
-			log.Printf("User %s has insufficient funds: %d < %d", r.UserID, balance, g.EntryFee)
-			s.MessageReactionRemove(r.ChannelID, r.MessageID, r.Emoji.Name, r.UserID)
+	log.Printf("Reaction added by %s on message %s", r.UserID, r.MessageID)
 
-			config, err := b.EconomyService.GetConfig(r.GuildID)
-			emoji := "<:Cash:1443554334670327848>"
-			if err == nil {
-				emoji = config.CurrencyEmoji
-			}
+	// API name of the reaction so custom emoji can be removed too
+	reaction := utils.GiveawayEmojiAPIName(g.Emoji)
 
-			dm, err := s.UserChannelCreate(r.UserID)
-			if err == nil {
-				embed := &discordgo.MessageEmbed{
-					Description: fmt.Sprintf("❌ You need **%d** %s to enter this giveaway. You have **%d**.", g.EntryFee, emoji, balance),
-					Color:       0xFF0000,
-				}
-				s.ChannelMessageSendEmbed(dm.ID, embed)
-			}
-			return
-		}
-
-		// Deduct fee using Service (updates cache)
-		log.Printf("Deducting %d coins from user %s", g.EntryFee, r.UserID)
-		err = b.EconomyService.RemoveCoins(r.GuildID, r.UserID, int64(g.EntryFee))
-		if err != nil {
-			log.Printf("Failed to deduct fee: %v", err)
-			return
+	res := b.Service.Enter(g, r.GuildID, r.UserID)
+	switch res.Status {
+	case services.EntryAdded:
+		log.Printf("Successfully added participant %s to giveaway %d", r.UserID, g.ID)
+		// Only paid entries get a receipt; free reaction entries stay silent
+		if res.Fee > 0 {
+			b.dmEmbed(r.UserID, res.Message, 0x00FF00)
 		}
 
-		// Notify user of deduction
-		dm, err := s.UserChannelCreate(r.UserID)
-		if err == nil {
-			config, err := b.EconomyService.GetConfig(r.GuildID)
-			emoji := "<:Cash:1443554334670327848>"
-			if err == nil {
-				emoji = config.CurrencyEmoji
-			}
-
-			embed := &discordgo.MessageEmbed{
-				Description: fmt.Sprintf("✅ **%d** %s have been deducted for entering the giveaway **%s**.", g.EntryFee, emoji, g.Prize),
-				Color:       0x00FF00,
-			}
-			s.ChannelMessageSendEmbed(dm.ID, embed)
-		}
-	}
+	case services.EntryAlreadyEntered, services.EntryInProgress, services.EntryClosed:
+		return
 
-	// Add participant (using fast prepared statement with context)
-	log.Printf("Adding participant %s to giveaway %d", r.UserID, g.ID)
-	ctx = context.Background()
-	err = b.DB.AddParticipantFast(ctx, g.ID, r.UserID)
-	if err != nil {
-		log.Printf("Failed to add participant: %v", err)
-	} else {
-		log.Printf("Successfully added participant %s", r.UserID)
+	case services.EntryCaptchaRequired:
+		b.sendCaptchaDM(g, r.UserID, res, func() {
+			s.MessageReactionRemove(r.ChannelID, r.MessageID, reaction, r.UserID)
+		})
 
-		// Assign Role Handling
-		if g.AssignRole != "" {
-			err := s.GuildMemberRoleAdd(r.GuildID, r.UserID, g.AssignRole)
-			if err != nil {
-				log.Printf("Failed to assign role %s to user %s: %v", g.AssignRole, r.UserID, err)
-			}
-		}
+	default:
+		log.Printf("User %s could not enter giveaway %d: %s", r.UserID, g.ID, res.Message)
+		s.MessageReactionRemove(r.ChannelID, r.MessageID, reaction, r.UserID)
+		b.dmEmbed(r.UserID, res.Message, 0xFF0000)
 	}
-	b.Service.UpdateGiveawayMessage(g)
 }
 
 func (b *Bot) MessageReactionRemove(s *discordgo.Session, r *discordgo.MessageReactionRemove) {
-	if r.UserID == s.State.User.ID {
+	if r.UserID == s.State.User.ID || !b.Service.IsReactionGiveaway(r.MessageID) {
 		return
 	}
 
-	if r.Emoji.Name != "🎉" {
+	g, err := b.DB.GetGiveawayFast(context.Background(), r.MessageID)
+	if err != nil || g == nil || g.Ended || g.EntryMode == models.EntryModeButton {
 		return
 	}
-
-	g, err := b.DB.GetGiveaway(r.MessageID)
-	if err != nil || g == nil || g.Ended {
+	if !utils.GiveawayEmojiMatches(g.Emoji, r.Emoji) {
 		return
 	}
 
-	// Check if user was a participant (using fast prepared statement with context)
-	ctx := context.Background()
-	isParticipant, err := b.DB.IsParticipantFast(ctx, g.ID, r.UserID)
-	if err != nil {
-		log.Printf("Error checking participant status: %v", err)
-		return
-	}
-	if !isParticipant {
-		return // User was not a participant, so no refund needed
-	}
-
-	// Remove participant (using fast prepared statement with context)
-	ctx = context.Background()
-	err = b.DB.RemoveParticipantFast(ctx, g.ID, r.UserID)
-	if err != nil {
-		log.Printf("Error removing participant: %v", err)
-		return
-	}
-
-	// Remove Assigned Role
-	if g.AssignRole != "" {
-		err := s.GuildMemberRoleRemove(r.GuildID, r.UserID, g.AssignRole)
-		if err != nil {
-			log.Printf("Failed to remove role %s from user %s: %v", g.AssignRole, r.UserID, err)
-		}
-	}
-
-	// Refund Fee Handling
-	if g.EntryFee > 0 {
-		refundCount, err := b.DB.GetRefundCount(g.ID, r.UserID)
-		if err != nil {
-			log.Printf("Error getting refund count: %v", err)
-		}
-
-		if refundCount < 3 {
-			user, err := b.DB.GetEconomyUser(r.GuildID, r.UserID)
-			if err == nil {
-				// 80% Refund Logic
-				refundAmount := int64(float64(g.EntryFee) * 0.8)
-
-				user.Balance += refundAmount
-				user.TotalSpent -= refundAmount // Revert spend (partially?) Or maybe just add to balance?
-				// Usually TotalSpent tracks actual spend. If we refund 80%, they effectively spent 20%.
-				// So we should decrease TotalSpent by the refundAmount too?
-				// Or maybe we shouldn't touch TotalSpent?
-				// Let's decrease TotalSpent by refundAmount so it reflects net spend.
-				user.TotalSpent -= refundAmount
-
-				b.DB.UpdateEconomyUser(user)
-				b.DB.IncrementRefundCount(g.ID, r.UserID)
-
-				dm, err := s.UserChannelCreate(r.UserID)
-				if err == nil {
-					config, err := b.EconomyService.GetConfig(r.GuildID)
-					emoji := "<:Cash:1443554334670327848>"
-					if err == nil {
-						emoji = config.CurrencyEmoji
-					}
-
-					msg := fmt.Sprintf("✅ You left the giveaway for **%s**. **%d** %s (80%%) have been refunded. (%d/3 refunds used)", g.Prize, refundAmount, emoji, refundCount+1)
-					if refundCount+1 == 3 {
-						msg += "\n⚠️ **Warning:** This was your last refund. No refund will be provided next time."
-					}
-					embed := &discordgo.MessageEmbed{
-						Description: msg,
-						Color:       0x00FF00,
-					}
-					s.ChannelMessageSendEmbed(dm.ID, embed)
-				}
-			}
-		} else {
-			dm, err := s.UserChannelCreate(r.UserID)
-			if err == nil {
-				embed := &discordgo.MessageEmbed{
-					Description: fmt.Sprintf("⚠️ You left the giveaway for **%s**, but you have exceeded the refund limit (3/3). No coins refunded.", g.Prize),
-					Color:       0xFFA500, // Orange
-				}
-				s.ChannelMessageSendEmbed(dm.ID, embed)
-			}
+	res := b.Service.Leave(g, r.GuildID, r.UserID)
+	if res.Status == services.LeaveRemoved && res.Message != "" {
+		color := 0x00FF00
+		if res.Fee == 0 {
+			color = 0xFFA500 // Orange: no refund
 		}
+		b.dmEmbed(r.UserID, res.Message, color)
 	}
-
-	b.Service.UpdateGiveawayMessage(g)
 }
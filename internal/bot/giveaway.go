@@ -2,7 +2,9 @@ package bot
 
 import (
 	"discord-giveaway-bot/internal/models"
+	"discord-giveaway-bot/internal/services"
 	"log"
+	"strconv"
 	"sync"
 	"time"
 )
@@ -47,3 +49,86 @@ func (b *Bot) GiveawayTicker() {
 		wg.Wait()
 	}
 }
+
+// ClaimTicker expires winners whose claim deadline has passed and rerolls their prize
+func (b *Bot) ClaimTicker() {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		members, err := b.Redis.GetDueClaims(models.Now())
+		if err != nil {
+			log.Printf("Error fetching due claims: %v", err)
+			continue
+		}
+
+		for _, member := range members {
+			// Remove from queue first to prevent double processing
+			if err := b.Redis.ZRem("giveaways:claims", member); err != nil {
+				log.Printf("Error removing claim %s from queue: %v", member, err)
+			}
+
+			giveawayID, userID, ok := services.ParseClaimMember(member)
+			if !ok {
+				continue
+			}
+			b.Service.ExpireClaim(giveawayID, userID)
+		}
+	}
+}
+
+// PrizeRoleTicker takes back temporary prize roles once they run out
+func (b *Bot) PrizeRoleTicker() {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		members, err := b.Redis.GetDuePrizeRoles(models.Now())
+		if err != nil {
+			log.Printf("Error fetching expired prize roles: %v", err)
+			continue
+		}
+
+		for _, member := range members {
+			// Remove from queue first to prevent double processing
+			if err := b.Redis.ZRem("prizes:roles", member); err != nil {
+				log.Printf("Error removing prize role %s from queue: %v", member, err)
+			}
+
+			deliveryID, err := strconv.ParseInt(member, 10, 64)
+			if err != nil {
+				continue
+			}
+			b.Service.ExpirePrizeRole(deliveryID)
+		}
+	}
+}
+
+// StartTicker posts scheduled giveaways once their start time arrives
+func (b *Bot) StartTicker() {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		members, err := b.Redis.GetDueStarts(models.Now())
+		if err != nil {
+			log.Printf("Error fetching due scheduled giveaways: %v", err)
+			continue
+		}
+
+		for _, member := range members {
+			// Remove from queue first to prevent double processing
+			if err := b.Redis.ZRem("giveaways:starting", member); err != nil {
+				log.Printf("Error removing scheduled giveaway %s from queue: %v", member, err)
+			}
+
+			giveawayID, ok := services.ParseStartMember(member)
+			if !ok {
+				continue
+			}
+			if err := b.Service.StartScheduledGiveaway(giveawayID); err != nil {
+				log.Printf("Error starting scheduled giveaway %d: %v", giveawayID, err)
+			}
+		}
+	}
+}
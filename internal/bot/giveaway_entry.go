@@ -0,0 +1,254 @@
+package bot
+
+import (
+	"bytes"
+	"discord-giveaway-bot/internal/models"
+	"discord-giveaway-bot/internal/services"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Button-mode giveaway entry. All feedback is ephemeral; the entry logic itself
+// lives in services.GiveawayService (shared with reaction entry).
+
+// HandleGiveawayEnterButton handles the persistent "Enter" button
+func (b *Bot) HandleGiveawayEnterButton(i *discordgo.InteractionCreate) {
+	s := b.Session
+	giveawayID, err := strconv.ParseInt(strings.TrimPrefix(i.MessageComponentData().CustomID, "enter_giveaway_"), 10, 64)
+	if err != nil || i.Member == nil {
+		return
+	}
+
+	// Requirement checks hit Discord + Postgres; defer so we never miss the 3s window
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral},
+	})
+
+	g, err := b.DB.GetGiveawayByID(giveawayID)
+	if err != nil || g == nil {
+		b.followupEphemeral(i, "❌ Giveaway not found.")
+		return
+	}
+
+	userID := i.Member.User.ID
+	res := b.Service.Enter(g, i.GuildID, userID)
+
+	if res.Status == services.EntryCaptchaRequired {
+		_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Embeds:     []*discordgo.MessageEmbed{captchaEmbed(res.Message)},
+			Files:      []*discordgo.File{{Name: "captcha.png", Reader: bytes.NewReader(res.Captcha.Image)}},
+			Components: captchaComponents(g.ID, userID),
+			Flags:      discordgo.MessageFlagsEphemeral,
+		})
+		if err != nil {
+			log.Printf("Failed to send captcha followup: %v", err)
+		}
+		return
+	}
+
+	b.followupEphemeral(i, res.Message)
+}
+
+// HandleGiveawayLeaveButton handles "Leave" (asks for confirmation) and the confirmation itself
+func (b *Bot) HandleGiveawayLeaveButton(i *discordgo.InteractionCreate) {
+	customID := i.MessageComponentData().CustomID
+	if i.Member == nil {
+		return
+	}
+	userID := i.Member.User.ID
+
+	if strings.HasPrefix(customID, "leave_giveaway_confirm_") {
+		giveawayID, err := strconv.ParseInt(strings.TrimPrefix(customID, "leave_giveaway_confirm_"), 10, 64)
+		if err != nil {
+			return
+		}
+		g, err := b.DB.GetGiveawayByID(giveawayID)
+		if err != nil || g == nil {
+			b.updateEphemeral(i, "❌ Giveaway not found.")
+			return
+		}
+
+		res := b.Service.Leave(g, i.GuildID, userID)
+		content := res.Message
+		if res.Status == services.LeaveRemoved && content == "" {
+			content = fmt.Sprintf("👋 You left the giveaway for **%s**.", g.Prize)
+		}
+		b.updateEphemeral(i, content)
+		return
+	}
+
+	giveawayID, err := strconv.ParseInt(strings.TrimPrefix(customID, "leave_giveaway_"), 10, 64)
+	if err != nil {
+		return
+	}
+	g, err := b.DB.GetGiveawayByID(giveawayID)
+	if err != nil || g == nil || g.Ended {
+		b.respondEphemeral(i, "❌ This giveaway has already ended.", nil)
+		return
+	}
+
+	participant, _ := b.DB.GetParticipant(g.ID, userID)
+	if participant == nil {
+		b.respondEphemeral(i, "You're not entered in this giveaway.", nil)
+		return
+	}
+
+	content := fmt.Sprintf("Are you sure you want to leave the giveaway for **%s**?", g.Prize)
+	if spent := g.EntryFee + participant.BoughtEntries*g.Bonus.EntryPrice; spent > 0 {
+		refunds, _ := b.DB.GetRefundCount(g.ID, userID)
+		if refunds < 3 {
+			content += fmt.Sprintf("\n80%% of the **%d** coins you spent will be refunded (%d/3 refunds used).", spent, refunds)
+		} else {
+			content += "\n⚠️ You have used all 3 refunds, your entry fee will **not** be refunded."
+		}
+	}
+
+	b.respondEphemeral(i, content, []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Leave Giveaway",
+					Style:    discordgo.DangerButton,
+					CustomID: fmt.Sprintf("leave_giveaway_confirm_%d", g.ID),
+				},
+			},
+		},
+	})
+}
+
+// HandleClaimPrizeButton handles "Claim Prize" from the winner announcement or the winner's DM
+func (b *Bot) HandleClaimPrizeButton(i *discordgo.InteractionCreate) {
+	giveawayID, err := strconv.ParseInt(strings.TrimPrefix(i.MessageComponentData().CustomID, "claim_prize_"), 10, 64)
+	if err != nil {
+		return
+	}
+
+	user := i.User
+	if i.Member != nil {
+		user = i.Member.User
+	}
+	if user == nil {
+		return
+	}
+
+	content, err := b.Service.ClaimPrize(giveawayID, user.ID)
+	if err != nil {
+		log.Printf("Failed to claim prize for %s: %v", user.ID, err)
+		content = "❌ Couldn't claim the prize right now. Please try again."
+	}
+	b.respondEphemeral(i, content, nil)
+}
+
+// sendCaptchaDM DMs the captcha for reaction entries; onFail runs if the DM
+// can't be delivered or the captcha times out (used to remove the reaction)
+func (b *Bot) sendCaptchaDM(g *models.Giveaway, userID string, res *services.EntryResult, onFail func()) {
+	s := b.Session
+
+	dm, err := s.UserChannelCreate(userID)
+	if err != nil {
+		log.Printf("Failed to create DM channel: %v", err)
+		onFail()
+		return
+	}
+
+	m, err := s.ChannelMessageSendComplex(dm.ID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{captchaEmbed(res.Message)},
+		Files:      []*discordgo.File{{Name: "captcha.png", Reader: bytes.NewReader(res.Captcha.Image)}},
+		Components: captchaComponents(g.ID, userID),
+	})
+	if err != nil {
+		log.Printf("Failed to send captcha DM: %v", err)
+		onFail()
+		return
+	}
+
+	// 1 minute timeout handler
+	go func() {
+		time.Sleep(1 * time.Minute)
+
+		isPart, _ := b.DB.IsParticipant(g.ID, userID)
+		if !isPart {
+			// Timeout!
+			s.ChannelMessageDelete(dm.ID, m.ID)
+			s.ChannelMessageSendEmbed(dm.ID, &discordgo.MessageEmbed{
+				Description: fmt.Sprintf("❌ Time expired! Entry declined for **%s**.", g.Prize),
+				Color:       0xFF0000,
+			})
+			onFail()
+		}
+	}()
+}
+
+func captchaEmbed(description string) *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Title:       "Captcha Verification",
+		Description: description,
+		Color:       0x0000FF,
+		Image: &discordgo.MessageEmbedImage{
+			URL: "attachment://captcha.png",
+		},
+	}
+}
+
+func captchaComponents(giveawayID int64, userID string) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Enter Captcha Code",
+					Style:    discordgo.PrimaryButton,
+					CustomID: fmt.Sprintf("captcha_%d_%s", giveawayID, userID),
+					Emoji:    &discordgo.ComponentEmoji{Name: "✍️"},
+				},
+			},
+		},
+	}
+}
+
+func (b *Bot) dmEmbed(userID, description string, color int) {
+	if description == "" {
+		return
+	}
+	dm, err := b.Session.UserChannelCreate(userID)
+	if err != nil {
+		return
+	}
+	b.Session.ChannelMessageSendEmbed(dm.ID, &discordgo.MessageEmbed{
+		Description: description,
+		Color:       color,
+	})
+}
+
+func (b *Bot) respondEphemeral(i *discordgo.InteractionCreate, content string, components []discordgo.MessageComponent) {
+	b.Session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content:    content,
+			Components: components,
+			Flags:      discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+func (b *Bot) updateEphemeral(i *discordgo.InteractionCreate, content string) {
+	b.Session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    content,
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+}
+
+func (b *Bot) followupEphemeral(i *discordgo.InteractionCreate, content string) {
+	b.Session.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Content: content,
+		Flags:   discordgo.MessageFlagsEphemeral,
+	})
+}
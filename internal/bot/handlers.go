@@ -25,3 +25,67 @@ func (b *Bot) HandleGList(i *discordgo.InteractionCreate) {
 func (b *Bot) HandleGCancel(i *discordgo.InteractionCreate) {
 	commands.HandleGCancel(b.Session, i, b.Service)
 }
+
+func (b *Bot) HandleGBuy(i *discordgo.InteractionCreate) {
+	commands.HandleGBuy(b.Session, i, b.Service)
+}
+
+func (b *Bot) HandleGVerify(i *discordgo.InteractionCreate) {
+	commands.HandleGVerify(b.Session, i, b.Service)
+}
+
+func (b *Bot) HandleGEdit(i *discordgo.InteractionCreate) {
+	commands.HandleGEdit(b.Session, i, b.Service)
+}
+
+func (b *Bot) HandleGPause(i *discordgo.InteractionCreate) {
+	commands.HandleGPause(b.Session, i, b.Service)
+}
+
+func (b *Bot) HandleGResume(i *discordgo.InteractionCreate) {
+	commands.HandleGResume(b.Session, i, b.Service)
+}
+
+func (b *Bot) HandleGScheduled(i *discordgo.InteractionCreate) {
+	commands.HandleGScheduled(b.Session, i, b.Service)
+}
+
+func (b *Bot) HandleGRecurring(i *discordgo.InteractionCreate) {
+	commands.HandleGRecurring(b.Session, i, b.Service)
+}
+
+func (b *Bot) HandleGTemplate(i *discordgo.InteractionCreate) {
+	commands.HandleGTemplate(b.Session, i, b.Service)
+}
+
+func (b *Bot) HandleGWins(i *discordgo.InteractionCreate) {
+	commands.HandleGWins(b.Session, i, b.Service)
+}
+
+func (b *Bot) HandleGLimits(i *discordgo.InteractionCreate) {
+	commands.HandleGLimits(b.Session, i, b.Service)
+}
+
+func (b *Bot) HandleGParticipants(i *discordgo.InteractionCreate) {
+	commands.HandleGParticipants(b.Session, i, b.Service)
+}
+
+func (b *Bot) HandleGParticipantsPage(i *discordgo.InteractionCreate) {
+	commands.HandleGParticipantsPage(b.Session, i, b.Service)
+}
+
+func (b *Bot) HandleGInfo(i *discordgo.InteractionCreate) {
+	commands.HandleGInfo(b.Session, i, b.Service)
+}
+
+func (b *Bot) HandleGHistory(i *discordgo.InteractionCreate) {
+	commands.HandleGHistory(b.Session, i, b.Service)
+}
+
+func (b *Bot) HandleGHistoryPage(i *discordgo.InteractionCreate) {
+	commands.HandleGHistoryPage(b.Session, i, b.Service)
+}
+
+func (b *Bot) HandleGStats(i *discordgo.InteractionCreate) {
+	commands.HandleGStats(b.Session, i, b.Service)
+}
@@ -0,0 +1,131 @@
+package bot
+
+import (
+	"discord-giveaway-bot/internal/engine/acl"
+	"discord-giveaway-bot/internal/engine/cde"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+const (
+	healthCheckTimeout   = 2 * time.Second
+	heartbeatStaleAfter  = 2 * time.Minute
+	queueSaturationLimit = 0.9 // ACL queue considered saturated above 90%
+)
+
+// HealthCheck is a single named check in a /healthz or /readyz response
+type HealthCheck struct {
+	OK      bool   `json:"ok"`
+	Detail  string `json:"detail,omitempty"`
+	Latency string `json:"latency,omitempty"`
+}
+
+// HealthReport is the JSON body served by /healthz and /readyz
+type HealthReport struct {
+	Status string                 `json:"status"`
+	Uptime string                 `json:"uptime"`
+	Checks map[string]HealthCheck `json:"checks"`
+}
+
+// gatewayCheck reports whether the websocket is up and heartbeats are being acked
+func (b *Bot) gatewayCheck() HealthCheck {
+	b.Session.RLock()
+	ready := b.Session.DataReady
+	lastAck := b.Session.LastHeartbeatAck
+	b.Session.RUnlock()
+
+	latency := b.Session.HeartbeatLatency()
+
+	if !ready {
+		return HealthCheck{OK: false, Detail: "gateway disconnected"}
+	}
+	if !lastAck.IsZero() && time.Since(lastAck) > heartbeatStaleAfter {
+		return HealthCheck{OK: false, Detail: "heartbeat not acknowledged for " + time.Since(lastAck).Round(time.Second).String()}
+	}
+	return HealthCheck{OK: true, Detail: "connected", Latency: latency.String()}
+}
+
+// pingCheck runs fn with a timeout so a hung backend can't hang the probe
+func pingCheck(fn func() error) HealthCheck {
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- fn() }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return HealthCheck{OK: false, Detail: err.Error(), Latency: time.Since(start).String()}
+		}
+		return HealthCheck{OK: true, Latency: time.Since(start).String()}
+	case <-time.After(healthCheckTimeout):
+		return HealthCheck{OK: false, Detail: "timed out"}
+	}
+}
+
+// aclQueueCheck fails when either punishment queue is close to full
+func aclQueueCheck() HealthCheck {
+	punish, punishCap, fastBan, fastBanCap := acl.QueueDepths()
+	punishRatio := float64(punish) / float64(punishCap)
+	fastBanRatio := float64(fastBan) / float64(fastBanCap)
+
+	check := HealthCheck{OK: true}
+	check.Detail = fmt.Sprintf("punish %d/%d, fast-ban %d/%d", punish, punishCap, fastBan, fastBanCap)
+	if punishRatio > queueSaturationLimit || fastBanRatio > queueSaturationLimit {
+		check.OK = false
+		check.Detail = "saturated: " + check.Detail
+	}
+	return check
+}
+
+func configsCheck() HealthCheck {
+	loaded, count := cde.ConfigsLoaded()
+	if !loaded {
+		return HealthCheck{OK: false, Detail: "guild configs not loaded yet"}
+	}
+	return HealthCheck{OK: true, Detail: fmt.Sprintf("%d guild(s) loaded", count)}
+}
+
+func (b *Bot) writeHealth(w http.ResponseWriter, checks map[string]HealthCheck) {
+	report := HealthReport{
+		Status: "ok",
+		Uptime: time.Since(b.StartTime).Round(time.Second).String(),
+		Checks: checks,
+	}
+
+	code := http.StatusOK
+	for _, c := range checks {
+		if !c.OK {
+			report.Status = "unavailable"
+			code = http.StatusServiceUnavailable
+			break
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(report)
+}
+
+// handleHealthz is the liveness probe: fails only when the process should be restarted
+// (dead gateway session or a wedged ACL)
+func (b *Bot) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	b.writeHealth(w, map[string]HealthCheck{
+		"gateway":   b.gatewayCheck(),
+		"acl_queue": aclQueueCheck(),
+	})
+}
+
+// handleReadyz is the readiness probe: everything the bot needs to serve traffic
+func (b *Bot) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	b.writeHealth(w, map[string]HealthCheck{
+		"gateway":       b.gatewayCheck(),
+		"postgres":      pingCheck(b.DB.Ping),
+		"redis":         pingCheck(b.Redis.Ping),
+		"acl_queue":     aclQueueCheck(),
+		"guild_configs": configsCheck(),
+	})
+}
@@ -0,0 +1,23 @@
+package bot
+
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
+func (b *Bot) GuildMemberAdd(s *discordgo.Session, m *discordgo.GuildMemberAdd) {
+	if m.User == nil {
+		return
+	}
+	go b.InviteService.MemberJoined(m.GuildID, m.User)
+}
+
+func (b *Bot) GuildMemberRemove(s *discordgo.Session, m *discordgo.GuildMemberRemove) {
+	if m.User == nil || m.User.Bot {
+		return
+	}
+	go b.InviteService.MemberLeft(m.GuildID, m.User.ID)
+}
+
+func (b *Bot) InviteCreate(s *discordgo.Session, e *discordgo.InviteCreate) {
+	b.InviteService.InviteCreated(e)
+}
@@ -0,0 +1,117 @@
+package bot
+
+import (
+	"discord-giveaway-bot/internal/engine/acl"
+	"discord-giveaway-bot/internal/engine/fdl"
+	"discord-giveaway-bot/internal/engine/ring"
+	"discord-giveaway-bot/internal/metrics"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var registerMetricsOnce sync.Once
+
+// registerMetrics wires pull-based gauges/counters into the /metrics registry
+func (b *Bot) registerMetrics(eventRing *ring.RingBuffer) {
+	registerMetricsOnce.Do(func() {
+		// AntiNuke event pipeline
+		metrics.CounterFunc("antinuke_events_ingested_total", "Audit log events ingested from the gateway.", func() float64 {
+			return float64(fdl.TotalEvents.GetTotal())
+		})
+		metrics.CounterFunc("antinuke_events_processed_total", "Events processed by the attribution engine.", func() float64 {
+			return float64(fdl.EventsProcessed.GetTotal())
+		})
+		metrics.CounterFunc("antinuke_events_dropped_total", "Events dropped because the ring buffer was full.", func() float64 {
+			return float64(fdl.EventsDropped.GetTotal())
+		})
+		metrics.CounterFunc("antinuke_events_detected_total", "Events that triggered a detection.", func() float64 {
+			return float64(fdl.EventsDetected.GetTotal())
+		})
+		if eventRing != nil {
+			metrics.GaugeFunc("antinuke_ring_depth", "Events waiting in the ring buffer.", func() float64 {
+				return float64(eventRing.Len())
+			})
+		}
+		metrics.GaugeFunc("antinuke_punish_queue_depth", "Tasks waiting in the standard punishment queue.", func() float64 {
+			depth, _, _, _ := acl.QueueDepths()
+			return float64(depth)
+		})
+		metrics.GaugeFunc("antinuke_fastban_queue_depth", "Tasks waiting in the fast-ban queue.", func() float64 {
+			_, _, depth, _ := acl.QueueDepths()
+			return float64(depth)
+		})
+
+		// Giveaways
+		metrics.GaugeFunc("giveaway_ending_queue_size", "Giveaways scheduled in the Redis ending queue.", func() float64 {
+			n, err := b.Redis.GetEndingQueueSize()
+			if err != nil {
+				return -1
+			}
+			return float64(n)
+		})
+
+		// Gateway
+		metrics.GaugeFunc("discord_gateway_heartbeat_latency_seconds", "Last gateway heartbeat round-trip.", func() float64 {
+			return b.Session.HeartbeatLatency().Seconds()
+		})
+
+		// Postgres pool
+		metrics.GaugeFunc("db_pool_open_connections", "Open Postgres connections.", func() float64 {
+			return float64(b.DB.Stats().OpenConnections)
+		})
+		metrics.GaugeFunc("db_pool_in_use_connections", "Postgres connections currently in use.", func() float64 {
+			return float64(b.DB.Stats().InUse)
+		})
+		metrics.GaugeFunc("db_pool_idle_connections", "Idle Postgres connections.", func() float64 {
+			return float64(b.DB.Stats().Idle)
+		})
+		metrics.CounterFunc("db_pool_wait_count_total", "Total times a caller waited for a Postgres connection.", func() float64 {
+			return float64(b.DB.Stats().WaitCount)
+		})
+		metrics.CounterFunc("db_pool_wait_seconds_total", "Total time spent waiting for a Postgres connection.", func() float64 {
+			return b.DB.Stats().WaitDuration.Seconds()
+		})
+
+		// Redis pool
+		metrics.GaugeFunc("redis_pool_total_connections", "Total Redis connections in the pool.", func() float64 {
+			return float64(b.Redis.PoolStats().TotalConns)
+		})
+		metrics.GaugeFunc("redis_pool_idle_connections", "Idle Redis connections in the pool.", func() float64 {
+			return float64(b.Redis.PoolStats().IdleConns)
+		})
+		metrics.CounterFunc("redis_pool_hits_total", "Times a free Redis connection was found in the pool.", func() float64 {
+			return float64(b.Redis.PoolStats().Hits)
+		})
+		metrics.CounterFunc("redis_pool_misses_total", "Times a free Redis connection was not found in the pool.", func() float64 {
+			return float64(b.Redis.PoolStats().Misses)
+		})
+		metrics.CounterFunc("redis_pool_timeouts_total", "Times a wait for a Redis connection timed out.", func() float64 {
+			return float64(b.Redis.PoolStats().Timeouts)
+		})
+	})
+}
+
+// StartMetricsServer exposes Prometheus metrics and the /healthz and /readyz probes on addr (e.g. "127.0.0.1:9090")
+func (b *Bot) StartMetricsServer(addr string, eventRing *ring.RingBuffer) {
+	b.registerMetrics(eventRing)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	mux.HandleFunc("/healthz", b.handleHealthz)
+	mux.HandleFunc("/readyz", b.handleReadyz)
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		log.Printf("📈 Metrics server listening on %s (/metrics, /healthz, /readyz)", addr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("❌ Metrics server stopped: %v", err)
+		}
+	}()
+}
@@ -1,6 +1,7 @@
 package bot
 
 import (
+	"discord-giveaway-bot/internal/metrics"
 	"fmt"
 	"log"
 	"net/http"
@@ -44,6 +45,7 @@ func NewPerformanceMonitor() *PerformanceMonitor {
 func (pm *PerformanceMonitor) TrackCommand(duration time.Duration) {
 	pm.commandCount.Add(1)
 	pm.commandLatency.Store(duration.Nanoseconds())
+	metrics.CommandLatency.ObserveDuration(duration)
 }
 
 // TrackEvent records event processing time
@@ -56,6 +58,7 @@ func (pm *PerformanceMonitor) TrackEvent(duration time.Duration) {
 func (pm *PerformanceMonitor) TrackREST(duration time.Duration) {
 	pm.restCallCount.Add(1)
 	pm.restLatency.Store(duration.Nanoseconds())
+	metrics.RESTLatency.ObserveDuration(duration)
 }
 
 // TrackDetection records AntiNuke detection time
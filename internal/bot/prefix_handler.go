@@ -54,7 +54,7 @@ func (b *Bot) HandlePrefixCommand(m *discordgo.MessageCreate) {
 	case "leaderboard", "lb":
 		economy.LeaderboardCmd(ctx, b.EconomyService)
 	case "invites":
-		economy.InvitesCmd(ctx, b.EconomyService)
+		economy.InvitesCmd(ctx, b.InviteService)
 	case "coinflip", "cf":
 		economy.CoinflipCmd(ctx, b.EconomyService)
 	case "give":
@@ -75,6 +75,34 @@ func (b *Bot) HandlePrefixCommand(m *discordgo.MessageCreate) {
 		commands.GListCmd(ctx, b.Service)
 	case "gcancel":
 		commands.GCancelCmd(ctx, b.Service)
+	case "gbuy":
+		commands.GBuyCmd(ctx, b.Service)
+	case "gverify":
+		commands.GVerifyCmd(ctx, b.Service)
+	case "gedit":
+		commands.GEditCmd(ctx, b.Service)
+	case "gpause":
+		commands.GPauseCmd(ctx, b.Service)
+	case "gresume":
+		commands.GResumeCmd(ctx, b.Service)
+	case "gscheduled":
+		commands.GScheduledCmd(ctx, b.Service)
+	case "grecurring":
+		commands.GRecurringCmd(ctx, b.Service)
+	case "gtemplate":
+		commands.GTemplateCmd(ctx, b.Service)
+	case "gwins":
+		commands.GWinsCmd(ctx, b.Service)
+	case "glimits":
+		commands.GLimitsCmd(ctx, b.Service)
+	case "gparticipants":
+		commands.GParticipantsCmd(ctx, b.Service)
+	case "ginfo":
+		commands.GInfoCmd(ctx, b.Service)
+	case "ghistory":
+		commands.GHistoryCmd(ctx, b.Service)
+	case "gstats":
+		commands.GStatsCmd(ctx, b.Service)
 
 	// Voice
 	case "wv":
@@ -1,6 +1,7 @@
 package bot
 
 import (
+	"discord-giveaway-bot/internal/models"
 	"log"
 	"time"
 
@@ -16,7 +17,8 @@ func (b *Bot) MessageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
 	// Track user stats
 	if m.GuildID != "" {
 		// Use Redis for high-performance counting
-		err := b.Redis.IncrementMessageCountHash(m.GuildID, m.Author.ID)
+		bucket := models.ActivityBucket(m.Timestamp.UnixMilli())
+		err := b.Redis.IncrementMessageCountHash(m.GuildID, m.Author.ID, bucket)
 		if err != nil {
 			log.Printf("Error incrementing message count in Redis: %v", err)
 		}
@@ -68,6 +70,11 @@ func (b *Bot) VoiceStateUpdate(s *discordgo.Session, v *discordgo.VoiceStateUpda
 			if err != nil {
 				log.Printf("Error adding voice minutes: %v", err)
 			}
+			for bucket, bucketMinutes := range splitVoiceMinutes(joinTime, time.Now()) {
+				if err := b.DB.AddActivity(guildID, userID, bucket, 0, bucketMinutes); err != nil {
+					log.Printf("Error adding voice activity: %v", err)
+				}
+			}
 		}
 	}
 
@@ -82,11 +89,45 @@ func (b *Bot) VoiceStateUpdate(s *discordgo.Session, v *discordgo.VoiceStateUpda
 	}
 }
 
+// splitVoiceMinutes spreads a voice session over the activity buckets it
+// covers. Minutes are counted from the session start, so the buckets add up
+// to the same whole minutes as the lifetime counter.
+func splitVoiceMinutes(from, to time.Time) map[int64]int {
+	elapsed := func(t time.Time) int { return int(t.Sub(from).Minutes()) }
+
+	buckets := make(map[int64]int)
+	start := from
+	for start.Before(to) {
+		bucket := models.ActivityBucket(start.UnixMilli())
+		end := time.UnixMilli(bucket + models.ActivityBucketMs)
+		if end.After(to) {
+			end = to
+		}
+		if minutes := elapsed(end) - elapsed(start); minutes > 0 {
+			buckets[bucket] += minutes
+		}
+		start = end
+	}
+	return buckets
+}
+
 func (b *Bot) MessageCountFlusher() {
 	ticker := time.NewTicker(5 * time.Minute)
 	defer ticker.Stop()
 
+	var lastPrune time.Time
 	for range ticker.C {
+		// Counts queued before buckets were tracked land in the current one
+		now := models.Now()
+
+		if time.Since(lastPrune) > 24*time.Hour {
+			lastPrune = time.Now()
+			cutoff := time.Now().AddDate(0, 0, -models.ActivityRetentionDays).UnixMilli()
+			if _, err := b.DB.PruneActivity(cutoff); err != nil {
+				log.Printf("Error pruning activity buckets: %v", err)
+			}
+		}
+
 		for _, g := range b.Session.State.Guilds {
 			counts, err := b.Redis.GetAndClearGuildMessageCounts(g.ID)
 			if err != nil {
@@ -102,11 +143,21 @@ func (b *Bot) MessageCountFlusher() {
 			// Ideally we should have a BatchIncrementMessageCount in DB
 			// For now, loop (still better than per-message)
 			// Or better: use a transaction or prepared statement
-			for userID, count := range counts {
+			for userID, buckets := range counts {
 				// We need to add 'count', not just increment by 1
 				// But DB.IncrementMessageCount increments by 1.
 				// I need to add AddMessageCount to DB.
-				if err := b.DB.AddMessageCount(g.ID, userID, int(count)); err != nil {
+				var total int
+				for bucket, count := range buckets {
+					total += int(count)
+					if bucket == 0 {
+						bucket = now
+					}
+					if err := b.DB.AddActivity(g.ID, userID, bucket, int(count), 0); err != nil {
+						log.Printf("Error flushing message activity for user %s: %v", userID, err)
+					}
+				}
+				if err := b.DB.AddMessageCount(g.ID, userID, total); err != nil {
 					log.Printf("Error flushing message count for user %s: %v", userID, err)
 				}
 			}
@@ -2,8 +2,11 @@ package antinuke
 
 import (
 	"discord-giveaway-bot/internal/database"
+	"discord-giveaway-bot/internal/engine/acl"
 	"discord-giveaway-bot/internal/utils"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -52,12 +55,89 @@ func HandleAntiNuke(s *discordgo.Session, i *discordgo.InteractionCreate, db *da
 			Color:       0x00FF00,
 		}
 
+		fallback := "Not set"
+		if config.FallbackChannel != "" {
+			fallback = fmt.Sprintf("<#%s>", config.FallbackChannel)
+		}
+		webhook := "Not set"
+		if config.AlertWebhookURL != "" {
+			webhook = "Configured"
+		}
+		embed.Description += fmt.Sprintf("\n**Owner DMs:** %v\n**Fallback Channel:** %s\n**Alert Webhook:** %s", config.NotifyOwner, fallback, webhook)
+
 		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 			Type: discordgo.InteractionResponseChannelMessageWithSource,
 			Data: &discordgo.InteractionResponseData{
 				Embeds: []*discordgo.MessageEmbed{embed},
 			},
 		})
+
+	case "export":
+		handleExport(s, i, db)
+
+	case "import":
+		handleImport(s, i, db, options[0])
+
+	case "preset":
+		handlePreset(s, i, db, options[0])
+
+	case "webhooks":
+		handleWebhookAllowlist(s, i, db, options[0])
+
+	case "notify":
+		handleNotify(s, i, db, options[0])
+
+	case "owners":
+		handleExtraOwners(s, i, db, options[0])
+	}
+}
+
+// handleWebhookAllowlist handles /antinuke webhooks allow|remove|list
+func handleWebhookAllowlist(s *discordgo.Session, i *discordgo.InteractionCreate, db *database.Database, group *discordgo.ApplicationCommandInteractionDataOption) {
+	if len(group.Options) == 0 {
+		return
+	}
+	sub := group.Options[0]
+
+	switch sub.Name {
+	case "allow":
+		appID := strings.TrimSpace(sub.Options[0].StringValue())
+		if _, err := strconv.ParseUint(appID, 10, 64); err != nil {
+			utils.SendError(s, i, "Invalid application ID.")
+			return
+		}
+		if err := db.AddWebhookAllowEntry(i.GuildID, appID, i.Member.User.ID); err != nil {
+			utils.SendError(s, i, "Failed to update webhook allowlist: "+err.Error())
+			return
+		}
+		acl.AllowWebhookApp(i.GuildID, appID)
+		utils.SendSuccess(s, i, fmt.Sprintf("✅ Webhooks owned by application `%s` will no longer be removed.", appID))
+
+	case "remove":
+		appID := strings.TrimSpace(sub.Options[0].StringValue())
+		if err := db.RemoveWebhookAllowEntry(i.GuildID, appID); err != nil {
+			utils.SendError(s, i, "Failed to update webhook allowlist")
+			return
+		}
+		acl.DisallowWebhookApp(i.GuildID, appID)
+		utils.SendSuccess(s, i, fmt.Sprintf("✅ Removed application `%s` from the webhook allowlist.", appID))
+
+	case "list":
+		entries, err := db.GetWebhookAllowEntries(i.GuildID)
+		if err != nil {
+			utils.SendError(s, i, "Failed to fetch webhook allowlist")
+			return
+		}
+		if len(entries) == 0 {
+			utils.SendSuccess(s, i, "🪝 No integrations are allowlisted. Webhooks from punished users are always removed.")
+			return
+		}
+
+		var sb strings.Builder
+		for _, e := range entries {
+			sb.WriteString(fmt.Sprintf("• `%s` (added by <@%s>)\n", e.ApplicationID, e.AddedBy))
+		}
+		utils.SendSuccess(s, i, "🪝 **Webhook Allowlist**\n"+sb.String())
 	}
 }
 
@@ -0,0 +1,152 @@
+package antinuke
+
+import (
+	"discord-giveaway-bot/internal/database"
+	"discord-giveaway-bot/internal/engine/acl"
+	"discord-giveaway-bot/internal/utils"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// handleNotify handles /antinuke notify owner|fallback|webhook
+func handleNotify(s *discordgo.Session, i *discordgo.InteractionCreate, db *database.Database, group *discordgo.ApplicationCommandInteractionDataOption) {
+	if len(group.Options) == 0 {
+		return
+	}
+	sub := group.Options[0]
+
+	switch sub.Name {
+	case "owner":
+		enabled := sub.Options[0].BoolValue()
+		if err := db.SetOwnerNotifications(i.GuildID, enabled); err != nil {
+			utils.SendError(s, i, "Failed to update owner notifications: "+err.Error())
+			return
+		}
+		syncNotifyConfig(db, i.GuildID)
+		if enabled {
+			utils.SendSuccess(s, i, "✅ The server owner and extra owners will be DM'd on every punishment.")
+		} else {
+			utils.SendSuccess(s, i, "⚠️ Owner DMs **disabled**. Extra owners will still be notified.")
+		}
+
+	case "fallback":
+		channelID := ""
+		if len(sub.Options) > 0 {
+			channelID = sub.Options[0].ChannelValue(s).ID
+		}
+		if err := db.SetAntiNukeFallbackChannel(i.GuildID, channelID); err != nil {
+			utils.SendError(s, i, "Failed to set fallback channel")
+			return
+		}
+		syncNotifyConfig(db, i.GuildID)
+		if channelID == "" {
+			utils.SendSuccess(s, i, "✅ Fallback log channel cleared.")
+			return
+		}
+		utils.SendSuccess(s, i, fmt.Sprintf("✅ If the log channel is deleted, security logs will go to <#%s>", channelID))
+
+	case "webhook":
+		webhookURL := ""
+		if len(sub.Options) > 0 {
+			webhookURL = strings.TrimSpace(sub.Options[0].StringValue())
+		}
+		if webhookURL != "" {
+			if err := acl.ValidateAlertWebhook(webhookURL); err != nil {
+				utils.SendError(s, i, "Invalid webhook: "+err.Error())
+				return
+			}
+		}
+		if err := db.SetAntiNukeAlertWebhook(i.GuildID, webhookURL); err != nil {
+			utils.SendError(s, i, "Failed to set alert webhook")
+			return
+		}
+		syncNotifyConfig(db, i.GuildID)
+		if webhookURL == "" {
+			utils.SendSuccess(s, i, "✅ Alert webhook cleared.")
+			return
+		}
+		utils.SendSuccess(s, i, "✅ Security logs will be posted to the webhook if no log channel is reachable.")
+	}
+}
+
+// handleExtraOwners handles /antinuke owners add|remove|list
+func handleExtraOwners(s *discordgo.Session, i *discordgo.InteractionCreate, db *database.Database, group *discordgo.ApplicationCommandInteractionDataOption) {
+	if len(group.Options) == 0 {
+		return
+	}
+	sub := group.Options[0]
+
+	// Only the real owner may change who else receives owner alerts
+	if sub.Name != "list" {
+		guild, err := s.State.Guild(i.GuildID)
+		if err != nil {
+			guild, err = s.Guild(i.GuildID)
+		}
+		if err != nil || guild.OwnerID != i.Member.User.ID {
+			utils.SendError(s, i, "Only the server owner can manage extra owners.")
+			return
+		}
+	}
+
+	switch sub.Name {
+	case "add":
+		user := sub.Options[0].UserValue(s)
+		if user.Bot {
+			utils.SendError(s, i, "Bots can't receive owner alerts.")
+			return
+		}
+		if err := db.AddExtraOwner(i.GuildID, user.ID, i.Member.User.ID); err != nil {
+			utils.SendError(s, i, "Failed to add extra owner: "+err.Error())
+			return
+		}
+		syncNotifyConfig(db, i.GuildID)
+		utils.SendSuccess(s, i, fmt.Sprintf("✅ <@%s> will now be DM'd on every punishment.", user.ID))
+
+	case "remove":
+		user := sub.Options[0].UserValue(s)
+		if err := db.RemoveExtraOwner(i.GuildID, user.ID); err != nil {
+			utils.SendError(s, i, "Failed to remove extra owner")
+			return
+		}
+		syncNotifyConfig(db, i.GuildID)
+		utils.SendSuccess(s, i, fmt.Sprintf("✅ <@%s> will no longer receive owner alerts.", user.ID))
+
+	case "list":
+		owners, err := db.GetExtraOwners(i.GuildID)
+		if err != nil {
+			utils.SendError(s, i, "Failed to fetch extra owners")
+			return
+		}
+		if len(owners) == 0 {
+			utils.SendSuccess(s, i, "👑 No extra owners configured. Only the server owner is notified.")
+			return
+		}
+
+		var sb strings.Builder
+		for _, o := range owners {
+			sb.WriteString(fmt.Sprintf("• <@%s> (added by <@%s>)\n", o.UserID, o.AddedBy))
+		}
+		utils.SendSuccess(s, i, "👑 **Extra Owners**\n"+sb.String())
+	}
+}
+
+// syncNotifyConfig pushes the stored escalation settings into the ACL notifier
+func syncNotifyConfig(db *database.Database, guildID string) {
+	config, err := db.GetAntiNukeConfig(guildID)
+	if err != nil {
+		return
+	}
+	owners, _ := db.GetExtraOwners(guildID)
+
+	cfg := acl.NotifyConfig{
+		NotifyOwner:     config.NotifyOwner,
+		FallbackChannel: config.FallbackChannel,
+		WebhookURL:      config.AlertWebhookURL,
+	}
+	for _, o := range owners {
+		cfg.ExtraOwners = append(cfg.ExtraOwners, o.UserID)
+	}
+	acl.SetGuildNotifyConfig(guildID, cfg)
+}
@@ -0,0 +1,639 @@
+package antinuke
+
+import (
+	"bytes"
+	"discord-giveaway-bot/internal/database"
+	"discord-giveaway-bot/internal/engine/acl"
+	"discord-giveaway-bot/internal/engine/cde"
+	"discord-giveaway-bot/internal/models"
+	"discord-giveaway-bot/internal/utils"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/goccy/go-json"
+)
+
+// ConfigTemplate is the portable JSON form of a guild's antinuke setup.
+// Nil sections are left untouched on import (presets only carry Actions).
+type ConfigTemplate struct {
+	Version          int                 `json:"version"`
+	Name             string              `json:"name,omitempty"`
+	ExportedAt       int64               `json:"exported_at,omitempty"`
+	Config           *TemplateConfig     `json:"config,omitempty"`
+	Actions          []TemplateAction    `json:"actions"`
+	Whitelist        []TemplateWhitelist `json:"whitelist"`
+	WebhookAllowlist []string            `json:"webhook_allowlist"`
+}
+
+// TemplateConfig mirrors antinuke_config
+type TemplateConfig struct {
+	Enabled     bool   `json:"enabled"`
+	PanicMode   bool   `json:"panic_mode"`
+	LogsChannel string `json:"logs_channel,omitempty"`
+}
+
+// TemplateAction mirrors a row of antinuke_actions
+type TemplateAction struct {
+	ActionType    string `json:"action_type"`
+	Enabled       bool   `json:"enabled"`
+	LimitCount    int    `json:"limit_count"`
+	WindowSeconds int    `json:"window_seconds"`
+	Punishment    string `json:"punishment"`
+}
+
+// TemplateWhitelist mirrors a row of antinuke_whitelist
+type TemplateWhitelist struct {
+	TargetID   string `json:"target_id"`
+	TargetType string `json:"target_type"`
+}
+
+const (
+	templateVersion  = 1
+	maxTemplateSize  = 256 * 1024
+	pendingImportTTL = 5 * time.Minute
+	importConfirmID  = "antinuke_import_confirm"
+	importCancelID   = "antinuke_import_cancel"
+)
+
+// pendingImport is a parsed template waiting for the admin to press Confirm
+type pendingImport struct {
+	Template  *ConfigTemplate
+	ExpiresAt time.Time
+}
+
+// guildID:userID -> *pendingImport
+var pendingImports sync.Map
+
+// Presets are built-in action templates
+var Presets = map[string]func() *ConfigTemplate{
+	"strict": func() *ConfigTemplate {
+		return presetTemplate("strict", func(actionType string) TemplateAction {
+			return TemplateAction{ActionType: actionType, Enabled: true, LimitCount: 1, WindowSeconds: 30, Punishment: models.PunishmentBan}
+		})
+	},
+	"balanced": func() *ConfigTemplate {
+		return presetTemplate("balanced", func(actionType string) TemplateAction {
+			switch actionType {
+			case models.ActionCreateRoles, models.ActionCreateChannels, models.ActionCreateWebhooks:
+				return TemplateAction{ActionType: actionType, Enabled: true, LimitCount: 5, WindowSeconds: 10, Punishment: models.PunishmentQuarantine}
+			default:
+				return TemplateAction{ActionType: actionType, Enabled: true, LimitCount: 3, WindowSeconds: 10, Punishment: models.PunishmentBan}
+			}
+		})
+	},
+	"lenient": func() *ConfigTemplate {
+		return presetTemplate("lenient", func(actionType string) TemplateAction {
+			switch actionType {
+			case models.ActionCreateRoles, models.ActionCreateChannels, models.ActionCreateWebhooks, models.ActionDeleteEmojis:
+				return TemplateAction{ActionType: actionType, Enabled: true, LimitCount: 10, WindowSeconds: 10, Punishment: models.PunishmentKick}
+			default:
+				return TemplateAction{ActionType: actionType, Enabled: true, LimitCount: 5, WindowSeconds: 10, Punishment: models.PunishmentQuarantine}
+			}
+		})
+	},
+}
+
+func presetTemplate(name string, build func(actionType string) TemplateAction) *ConfigTemplate {
+	t := &ConfigTemplate{Version: templateVersion, Name: name}
+	for _, at := range models.GetAllActionTypes() {
+		t.Actions = append(t.Actions, build(at))
+	}
+	return t
+}
+
+// buildTemplate snapshots a guild's current configuration
+func buildTemplate(db *database.Database, guildID string) (*ConfigTemplate, error) {
+	config, err := db.GetAntiNukeConfig(guildID)
+	if err != nil {
+		return nil, err
+	}
+	actions, err := db.GetAllActionConfigs(guildID)
+	if err != nil {
+		return nil, err
+	}
+	whitelist, err := db.GetWhitelistEntries(guildID)
+	if err != nil {
+		return nil, err
+	}
+	allowlist, err := db.GetWebhookAllowEntries(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &ConfigTemplate{
+		Version:    templateVersion,
+		ExportedAt: time.Now().Unix(),
+		Config: &TemplateConfig{
+			Enabled:     config.Enabled,
+			PanicMode:   config.PanicMode,
+			LogsChannel: config.LogsChannel,
+		},
+		Actions:          []TemplateAction{},
+		Whitelist:        []TemplateWhitelist{},
+		WebhookAllowlist: []string{},
+	}
+	for _, a := range actions {
+		t.Actions = append(t.Actions, TemplateAction{
+			ActionType:    a.ActionType,
+			Enabled:       a.Enabled,
+			LimitCount:    a.LimitCount,
+			WindowSeconds: a.WindowSeconds,
+			Punishment:    a.Punishment,
+		})
+	}
+	for _, w := range whitelist {
+		t.Whitelist = append(t.Whitelist, TemplateWhitelist{TargetID: w.TargetID, TargetType: w.TargetType})
+	}
+	for _, w := range allowlist {
+		t.WebhookAllowlist = append(t.WebhookAllowlist, w.ApplicationID)
+	}
+	return t, nil
+}
+
+// validateTemplate rejects unknown actions, punishments and nonsense limits
+func validateTemplate(t *ConfigTemplate) error {
+	if t.Version != templateVersion {
+		return fmt.Errorf("unsupported template version %d", t.Version)
+	}
+
+	known := make(map[string]bool)
+	for _, at := range models.GetAllActionTypes() {
+		known[at] = true
+	}
+
+	seen := make(map[string]bool)
+	for _, a := range t.Actions {
+		if !known[a.ActionType] {
+			return fmt.Errorf("unknown action type `%s`", a.ActionType)
+		}
+		if seen[a.ActionType] {
+			return fmt.Errorf("duplicate action type `%s`", a.ActionType)
+		}
+		seen[a.ActionType] = true
+
+		switch a.Punishment {
+		case models.PunishmentBan, models.PunishmentKick, models.PunishmentTimeout, models.PunishmentQuarantine:
+		default:
+			return fmt.Errorf("invalid punishment `%s` for `%s`", a.Punishment, a.ActionType)
+		}
+		if a.LimitCount < 1 || a.WindowSeconds < 1 {
+			return fmt.Errorf("limit and window must be at least 1 for `%s`", a.ActionType)
+		}
+	}
+
+	for _, w := range t.Whitelist {
+		if _, err := strconv.ParseUint(w.TargetID, 10, 64); err != nil {
+			return fmt.Errorf("invalid whitelist ID `%s`", w.TargetID)
+		}
+		if w.TargetType != "user" && w.TargetType != "role" {
+			return fmt.Errorf("invalid whitelist type `%s`", w.TargetType)
+		}
+	}
+	for _, id := range t.WebhookAllowlist {
+		if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+			return fmt.Errorf("invalid webhook allowlist ID `%s`", id)
+		}
+	}
+	if t.Config != nil && t.Config.LogsChannel != "" {
+		if _, err := strconv.ParseUint(t.Config.LogsChannel, 10, 64); err != nil {
+			return fmt.Errorf("invalid logs channel `%s`", t.Config.LogsChannel)
+		}
+	}
+	return nil
+}
+
+// dropMissingTargets removes the logs channel and whitelisted roles that don't
+// exist in the guild, so a template from another server can't point at them.
+// Returns a line for each one it skipped.
+func dropMissingTargets(s *discordgo.Session, guildID string, t *ConfigTemplate) []string {
+	var skipped []string
+
+	if t.Config != nil && t.Config.LogsChannel != "" {
+		channel, err := s.State.Channel(t.Config.LogsChannel)
+		if err != nil {
+			channel, err = s.Channel(t.Config.LogsChannel)
+		}
+		if err != nil || channel.GuildID != guildID {
+			skipped = append(skipped, fmt.Sprintf("⏭️ **Skipped Log Channel:** `%s` isn't in this server", t.Config.LogsChannel))
+			t.Config.LogsChannel = ""
+		}
+	}
+
+	if len(t.Whitelist) > 0 {
+		var roles []*discordgo.Role
+		if guild, err := s.State.Guild(guildID); err == nil {
+			roles = guild.Roles
+		} else if roles, err = s.GuildRoles(guildID); err != nil {
+			// Without the role list nothing can be checked, so keep the roles out
+			roles = nil
+		}
+		exists := make(map[string]bool, len(roles))
+		for _, r := range roles {
+			exists[r.ID] = true
+		}
+
+		kept := t.Whitelist[:0]
+		for _, w := range t.Whitelist {
+			if w.TargetType == "role" && !exists[w.TargetID] {
+				skipped = append(skipped, fmt.Sprintf("⏭️ **Skipped Whitelist Role:** `%s` isn't in this server", w.TargetID))
+				continue
+			}
+			kept = append(kept, w)
+		}
+		t.Whitelist = kept
+	}
+
+	return skipped
+}
+
+func formatAction(a TemplateAction) string {
+	if !a.Enabled {
+		return "disabled"
+	}
+	return fmt.Sprintf("%d / %s → %s", a.LimitCount, models.FormatWindowTime(a.WindowSeconds), a.Punishment)
+}
+
+// diffTemplate describes what applying incoming on top of current would change
+func diffTemplate(current, incoming *ConfigTemplate) []string {
+	var lines []string
+
+	if incoming.Config != nil {
+		if current.Config.Enabled != incoming.Config.Enabled {
+			lines = append(lines, fmt.Sprintf("**Enabled:** %v → %v", current.Config.Enabled, incoming.Config.Enabled))
+		}
+		if current.Config.PanicMode != incoming.Config.PanicMode {
+			lines = append(lines, fmt.Sprintf("**Panic Mode:** %v → %v", current.Config.PanicMode, incoming.Config.PanicMode))
+		}
+		if incoming.Config.LogsChannel != "" && current.Config.LogsChannel != incoming.Config.LogsChannel {
+			lines = append(lines, fmt.Sprintf("**Log Channel:** <#%s> → <#%s>", current.Config.LogsChannel, incoming.Config.LogsChannel))
+		}
+	}
+
+	if incoming.Actions != nil {
+		cur := make(map[string]TemplateAction)
+		for _, a := range current.Actions {
+			cur[a.ActionType] = a
+		}
+		inc := make(map[string]bool)
+		for _, a := range incoming.Actions {
+			inc[a.ActionType] = true
+			old, ok := cur[a.ActionType]
+			name := models.GetActionDisplayName(a.ActionType)
+			if !ok {
+				lines = append(lines, fmt.Sprintf("➕ **%s:** %s", name, formatAction(a)))
+			} else if old != a {
+				lines = append(lines, fmt.Sprintf("✏️ **%s:** %s → %s", name, formatAction(old), formatAction(a)))
+			}
+		}
+		var removed []string
+		for at := range cur {
+			if !inc[at] {
+				removed = append(removed, at)
+			}
+		}
+		sort.Strings(removed)
+		for _, at := range removed {
+			lines = append(lines, fmt.Sprintf("➖ **%s:** disabled", models.GetActionDisplayName(at)))
+		}
+	}
+
+	if incoming.Whitelist != nil {
+		cur := make(map[string]bool)
+		for _, w := range current.Whitelist {
+			cur[w.TargetID] = true
+		}
+		inc := make(map[string]bool)
+		for _, w := range incoming.Whitelist {
+			inc[w.TargetID] = true
+			if !cur[w.TargetID] {
+				lines = append(lines, fmt.Sprintf("➕ **Whitelist:** %s", mentionTarget(w)))
+			}
+		}
+		for _, w := range current.Whitelist {
+			if !inc[w.TargetID] {
+				lines = append(lines, fmt.Sprintf("➖ **Whitelist:** %s", mentionTarget(w)))
+			}
+		}
+	}
+
+	if incoming.WebhookAllowlist != nil {
+		cur := make(map[string]bool)
+		for _, id := range current.WebhookAllowlist {
+			cur[id] = true
+		}
+		inc := make(map[string]bool)
+		for _, id := range incoming.WebhookAllowlist {
+			inc[id] = true
+			if !cur[id] {
+				lines = append(lines, fmt.Sprintf("➕ **Webhook Allowlist:** `%s`", id))
+			}
+		}
+		for _, id := range current.WebhookAllowlist {
+			if !inc[id] {
+				lines = append(lines, fmt.Sprintf("➖ **Webhook Allowlist:** `%s`", id))
+			}
+		}
+	}
+
+	return lines
+}
+
+func mentionTarget(w TemplateWhitelist) string {
+	if w.TargetType == "role" {
+		return fmt.Sprintf("<@&%s>", w.TargetID)
+	}
+	return fmt.Sprintf("<@%s>", w.TargetID)
+}
+
+// applyTemplate writes a template into the database in one transaction and
+// refreshes the engine caches once it's committed. Targets deleted since the
+// preview are dropped again.
+func applyTemplate(s *discordgo.Session, db *database.Database, guildID, userID string, t *ConfigTemplate) error {
+	dropMissingTargets(s, guildID, t)
+
+	current, err := buildTemplate(db, guildID)
+	if err != nil {
+		return err
+	}
+
+	imp := &models.AntiNukeImport{}
+	if t.Config != nil {
+		imp.Config = &models.AntiNukeConfig{
+			Enabled:     t.Config.Enabled,
+			PanicMode:   t.Config.PanicMode,
+			LogsChannel: t.Config.LogsChannel,
+		}
+	}
+
+	if t.Actions != nil {
+		inc := make(map[string]bool)
+		for _, a := range t.Actions {
+			inc[a.ActionType] = true
+			imp.Actions = append(imp.Actions, &models.ActionConfig{
+				ActionType:    a.ActionType,
+				Enabled:       a.Enabled,
+				LimitCount:    a.LimitCount,
+				WindowSeconds: a.WindowSeconds,
+				Punishment:    a.Punishment,
+			})
+		}
+		for _, a := range current.Actions {
+			if !inc[a.ActionType] {
+				imp.DisableActions = append(imp.DisableActions, a.ActionType)
+			}
+		}
+	}
+
+	if t.Whitelist != nil {
+		inc := make(map[string]bool)
+		for _, w := range t.Whitelist {
+			inc[w.TargetID] = true
+			imp.Whitelist = append(imp.Whitelist, &models.WhitelistEntry{TargetID: w.TargetID, TargetType: w.TargetType})
+		}
+		for _, w := range current.Whitelist {
+			if !inc[w.TargetID] {
+				imp.RemoveWhitelist = append(imp.RemoveWhitelist, w.TargetID)
+			}
+		}
+	}
+
+	if t.WebhookAllowlist != nil {
+		inc := make(map[string]bool)
+		for _, id := range t.WebhookAllowlist {
+			inc[id] = true
+			imp.Webhooks = append(imp.Webhooks, id)
+		}
+		for _, id := range current.WebhookAllowlist {
+			if !inc[id] {
+				imp.RemoveWebhooks = append(imp.RemoveWebhooks, id)
+			}
+		}
+	}
+
+	if err := db.ApplyAntiNukeImport(guildID, userID, imp); err != nil {
+		return err
+	}
+
+	if t.Config != nil && t.Config.LogsChannel != "" {
+		acl.SetGuildLogChannel(guildID, t.Config.LogsChannel)
+	}
+	if t.WebhookAllowlist != nil {
+		acl.SetWebhookAllowlist(guildID, t.WebhookAllowlist)
+	}
+	gid, _ := strconv.ParseUint(guildID, 10, 64)
+	if err := cde.LoadGuildConfig(gid); err != nil {
+		log.Printf("[CDE] Failed to reload config for guild %s after import: %v", guildID, err)
+	}
+	return nil
+}
+
+// handleExport handles /antinuke export
+func handleExport(s *discordgo.Session, i *discordgo.InteractionCreate, db *database.Database) {
+	t, err := buildTemplate(db, i.GuildID)
+	if err != nil {
+		utils.SendError(s, i, "Failed to export config: "+err.Error())
+		return
+	}
+
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		utils.SendError(s, i, "Failed to encode config")
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("📦 Exported **%d** action rule(s), **%d** whitelist entr(ies) and **%d** webhook integration(s).",
+				len(t.Actions), len(t.Whitelist), len(t.WebhookAllowlist)),
+			Files: []*discordgo.File{
+				{
+					Name:        fmt.Sprintf("antinuke-%s.json", i.GuildID),
+					ContentType: "application/json",
+					Reader:      bytes.NewReader(data),
+				},
+			},
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// handleImport handles /antinuke import <file>
+func handleImport(s *discordgo.Session, i *discordgo.InteractionCreate, db *database.Database, sub *discordgo.ApplicationCommandInteractionDataOption) {
+	if len(sub.Options) == 0 {
+		utils.SendError(s, i, "Please attach an exported config file.")
+		return
+	}
+
+	attachmentID, _ := sub.Options[0].Value.(string)
+	resolved := i.ApplicationCommandData().Resolved
+	if resolved == nil || resolved.Attachments[attachmentID] == nil {
+		utils.SendError(s, i, "Could not read the attached file.")
+		return
+	}
+	attachment := resolved.Attachments[attachmentID]
+	if attachment.Size > maxTemplateSize {
+		utils.SendError(s, i, "Config file is too large.")
+		return
+	}
+
+	// Downloading can outlast the 3 second interaction deadline
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral},
+	})
+	followup := func(data *discordgo.InteractionResponseData) {
+		s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Embeds:     data.Embeds,
+			Components: data.Components,
+			Flags:      data.Flags,
+		})
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(attachment.URL)
+	if err != nil {
+		followup(errorResponse("Failed to download config file."))
+		return
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxTemplateSize))
+	if err != nil {
+		followup(errorResponse("Failed to download config file."))
+		return
+	}
+
+	var t ConfigTemplate
+	if err := json.Unmarshal(data, &t); err != nil {
+		followup(errorResponse("Invalid config file: " + err.Error()))
+		return
+	}
+
+	followup(previewTemplate(s, db, i.GuildID, i.Member.User.ID, &t, "Import Preview"))
+}
+
+// handlePreset handles /antinuke preset <name>
+func handlePreset(s *discordgo.Session, i *discordgo.InteractionCreate, db *database.Database, sub *discordgo.ApplicationCommandInteractionDataOption) {
+	name := sub.Options[0].StringValue()
+	build, ok := Presets[name]
+	if !ok {
+		utils.SendError(s, i, "Unknown preset.")
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: previewTemplate(s, db, i.GuildID, i.Member.User.ID, build(), "Preset: "+strings.ToUpper(name[:1])+name[1:]),
+	})
+}
+
+// previewTemplate validates a template, stores it until confirmed and builds
+// the diff preview to send back
+func previewTemplate(s *discordgo.Session, db *database.Database, guildID, userID string, t *ConfigTemplate, title string) *discordgo.InteractionResponseData {
+	if err := validateTemplate(t); err != nil {
+		return errorResponse("Invalid config: " + err.Error())
+	}
+	skipped := dropMissingTargets(s, guildID, t)
+
+	current, err := buildTemplate(db, guildID)
+	if err != nil {
+		return errorResponse("Failed to load current config: " + err.Error())
+	}
+
+	lines := diffTemplate(current, t)
+	if len(lines) == 0 {
+		description := "✅ Nothing to change — this config matches the current setup."
+		if len(skipped) > 0 {
+			description += "\n\n" + strings.Join(skipped, "\n")
+		}
+		return &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{{Description: description, Color: 0x00FF00}},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		}
+	}
+
+	description := strings.Join(append(lines, skipped...), "\n")
+	if len(description) > 3900 {
+		description = description[:3900] + "\n*...diff truncated*"
+	}
+
+	pendingImports.Store(guildID+":"+userID, &pendingImport{
+		Template:  t,
+		ExpiresAt: time.Now().Add(pendingImportTTL),
+	})
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "🛡️ " + title,
+		Description: description,
+		Color:       0xFFA500,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("%d change(s) • Expires in 5 minutes", len(lines)),
+		},
+	}
+
+	return &discordgo.InteractionResponseData{
+		Embeds: []*discordgo.MessageEmbed{embed},
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{
+				Components: []discordgo.MessageComponent{
+					discordgo.Button{Label: "Apply", Style: discordgo.SuccessButton, CustomID: importConfirmID},
+					discordgo.Button{Label: "Cancel", Style: discordgo.DangerButton, CustomID: importCancelID},
+				},
+			},
+		},
+		Flags: discordgo.MessageFlagsEphemeral,
+	}
+}
+
+// errorResponse is utils.SendError's embed, for replies that can't respond directly
+func errorResponse(message string) *discordgo.InteractionResponseData {
+	return &discordgo.InteractionResponseData{
+		Embeds: []*discordgo.MessageEmbed{{Title: "❌ Error", Description: message, Color: 0xFF0000}},
+		Flags:  discordgo.MessageFlagsEphemeral,
+	}
+}
+
+// HandleImportButton handles the Apply/Cancel buttons of an import preview
+func HandleImportButton(s *discordgo.Session, i *discordgo.InteractionCreate, db *database.Database) {
+	if i.Member == nil {
+		return
+	}
+	key := i.GuildID + ":" + i.Member.User.ID
+
+	update := func(description string, color int) {
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: &discordgo.InteractionResponseData{
+				Embeds:     []*discordgo.MessageEmbed{{Description: description, Color: color}},
+				Components: []discordgo.MessageComponent{},
+			},
+		})
+	}
+
+	val, ok := pendingImports.LoadAndDelete(key)
+	if i.MessageComponentData().CustomID == importCancelID {
+		update("❌ Import cancelled.", 0xFF0000)
+		return
+	}
+
+	if !ok || time.Now().After(val.(*pendingImport).ExpiresAt) {
+		update("⌛ This import preview has expired. Run the command again.", 0xFF0000)
+		return
+	}
+
+	if err := applyTemplate(s, db, i.GuildID, i.Member.User.ID, val.(*pendingImport).Template); err != nil {
+		log.Printf("[ANTINUKE] Import failed for guild %s: %v", i.GuildID, err)
+		update("❌ Failed to apply config: "+err.Error(), 0xFF0000)
+		return
+	}
+
+	update("✅ AntiNuke configuration applied.", 0x00FF00)
+}
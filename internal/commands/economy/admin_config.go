@@ -66,6 +66,14 @@ var Economy = &discordgo.ApplicationCommand{
 				{Type: discordgo.ApplicationCommandOptionInteger, Name: "amount", Description: "Amount", Required: true},
 			},
 		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "set-invite-fake-days",
+			Description: "Joins from accounts younger than this many days count as fake invites",
+			Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionInteger, Name: "amount", Description: "Days (0 to disable)", Required: true},
+			},
+		},
 		{
 			Type:        discordgo.ApplicationCommandOptionSubCommand,
 			Name:        "set-react-reward",
@@ -180,7 +188,7 @@ func EconomyCmd(ctx framework.Context, service *services.EconomyService) {
 
 	config, err := service.GetConfig(ctx.GetGuildID())
 	if err != nil {
-		config = &models.EconomyConfig{GuildID: ctx.GetGuildID()}
+		config = &models.EconomyConfig{GuildID: ctx.GetGuildID(), InviteFakeDays: models.DefaultInviteFakeDays}
 	}
 
 	var msg string
@@ -248,7 +256,7 @@ func EconomyCmd(ctx framework.Context, service *services.EconomyService) {
 		}
 
 	case "set-vc-reward", "set-daily", "set-weekly", "set-hourly",
-		"set-invite-reward", "set-react-reward", "set-poll-reward", "set-event-reward", "set-upvote-reward", "set-max-gamble":
+		"set-invite-reward", "set-invite-fake-days", "set-react-reward", "set-poll-reward", "set-event-reward", "set-upvote-reward", "set-max-gamble":
 
 		var amount int64
 		if slashCtx, ok := ctx.(*framework.SlashContext); ok {
@@ -282,6 +290,13 @@ func EconomyCmd(ctx framework.Context, service *services.EconomyService) {
 		case "set-invite-reward":
 			config.InviteReward = int(amount)
 			msg = fmt.Sprintf("Invite reward set to **%d**.", amount)
+		case "set-invite-fake-days":
+			if amount < 0 {
+				ctx.ReplyEphemeral(utils.EmojiCross + " Invalid amount.")
+				return
+			}
+			config.InviteFakeDays = int(amount)
+			msg = fmt.Sprintf("Joins from accounts younger than **%d** days now count as fake invites.", amount)
 		case "set-react-reward":
 			config.ReactReward = int(amount)
 			msg = fmt.Sprintf("React reward set to **%d**.", amount)
@@ -3,20 +3,102 @@ package economy
 import (
 	"discord-giveaway-bot/internal/commands/framework"
 	"discord-giveaway-bot/internal/services"
+	"discord-giveaway-bot/internal/utils"
+	"fmt"
+	"strings"
 
 	"github.com/bwmarrin/discordgo"
 )
 
 var Invites = &discordgo.ApplicationCommand{
 	Name:        "invites",
-	Description: "Check your invite count and rewards",
+	Description: "Check invite counts and the invite leaderboard",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "user",
+			Description: "Show someone's invites",
+			Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionUser, Name: "user", Description: "User to check (default: you)", Required: false},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "leaderboard",
+			Description: "Top inviters in this server",
+		},
+	},
 }
 
-func InvitesCmd(ctx framework.Context, service *services.EconomyService) {
-	ctx.ReplyEphemeral("ℹ️ Invite tracking is not fully implemented yet. Check back later!")
+func InvitesCmd(ctx framework.Context, service *services.InviteService) {
+	targetUser := ctx.GetAuthor()
+	subCommand := "user"
+
+	if slashCtx, ok := ctx.(*framework.SlashContext); ok {
+		sub := slashCtx.Interaction.ApplicationCommandData().Options[0]
+		subCommand = sub.Name
+		if len(sub.Options) > 0 {
+			targetUser = sub.Options[0].UserValue(slashCtx.Session)
+		}
+	} else if prefixCtx, ok := ctx.(*framework.PrefixContext); ok {
+		if len(prefixCtx.Args) > 0 {
+			arg := prefixCtx.Args[0]
+			if strings.EqualFold(arg, "leaderboard") || strings.EqualFold(arg, "lb") {
+				subCommand = "leaderboard"
+			} else if u, err := ctx.GetSession().User(strings.Trim(arg, "<@!>")); err == nil {
+				targetUser = u
+			}
+		}
+	}
+
+	if subCommand == "leaderboard" {
+		invitesLeaderboard(ctx, service)
+		return
+	}
+
+	stats, err := service.GetStats(ctx.GetGuildID(), targetUser.ID)
+	if err != nil {
+		ctx.ReplyEphemeral(fmt.Sprintf("%s %s", utils.EmojiCross, err.Error()))
+		return
+	}
+
+	description := fmt.Sprintf("<@%s> has **%d** invites\n\n🚪 **%d** left • ❌ **%d** fake • 🔁 **%d** rejoins",
+		targetUser.ID, stats.Regular, stats.Left, stats.Fake, stats.Rejoins)
+	if inviterID, err := service.GetInviter(ctx.GetGuildID(), targetUser.ID); err == nil && inviterID != "" {
+		description += fmt.Sprintf("\n\nInvited by <@%s>", inviterID)
+	}
+
+	ctx.ReplyEmbed(&discordgo.MessageEmbed{
+		Title:       "📨 Invites",
+		Description: description,
+		Color:       0x2f3136,
+	})
+}
+
+func invitesLeaderboard(ctx framework.Context, service *services.InviteService) {
+	leaderboard, err := service.GetLeaderboard(ctx.GetGuildID(), 10)
+	if err != nil {
+		ctx.ReplyEphemeral(fmt.Sprintf("%s %s", utils.EmojiCross, err.Error()))
+		return
+	}
+	if len(leaderboard) == 0 {
+		ctx.Reply("No tracked invites yet.")
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("**🏆 Invite Leaderboard**\n\n")
+	for idx, s := range leaderboard {
+		sb.WriteString(fmt.Sprintf("**%d.** <@%s> - **%d** invites (%d left, %d fake)\n", idx+1, s.InviterID, s.Regular, s.Left, s.Fake))
+	}
+
+	ctx.ReplyEmbed(&discordgo.MessageEmbed{
+		Description: sb.String(),
+		Color:       0x2f3136,
+	})
 }
 
-func InvitesHandler(s *discordgo.Session, i *discordgo.InteractionCreate, service *services.EconomyService) {
+func InvitesHandler(s *discordgo.Session, i *discordgo.InteractionCreate, service *services.InviteService) {
 	ctx := framework.NewSlashContext(s, i)
 	InvitesCmd(ctx, service)
 }
@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"discord-giveaway-bot/internal/commands/framework"
+	"discord-giveaway-bot/internal/services"
+	"discord-giveaway-bot/internal/utils"
+	"strconv"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var GBuy = &discordgo.ApplicationCommand{
+	Name:        "gbuy",
+	Description: "Buy extra entries for a giveaway you've entered",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "message_id",
+			Description: "Message ID of the giveaway",
+			Required:    true,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "amount",
+			Description: "Number of entries to buy (default: 1)",
+			Required:    false,
+			MinValue:    floatPtr(1),
+			MaxValue:    utils.MaxBoughtEntries,
+		},
+	},
+}
+
+func GBuyCmd(ctx framework.Context, service *services.GiveawayService) {
+	var messageID string
+	amount := 1
+
+	if slashCtx, ok := ctx.(*framework.SlashContext); ok {
+		options := slashCtx.Interaction.ApplicationCommandData().Options
+		messageID = options[0].StringValue()
+		if len(options) > 1 {
+			amount = int(options[1].IntValue())
+		}
+	} else if prefixCtx, ok := ctx.(*framework.PrefixContext); ok {
+		if len(prefixCtx.Args) < 1 {
+			ctx.Reply("Usage: `!gbuy <message_id> [amount]`")
+			return
+		}
+		messageID = prefixCtx.Args[0]
+		if len(prefixCtx.Args) > 1 {
+			n, err := strconv.Atoi(prefixCtx.Args[1])
+			if err != nil || n < 1 {
+				ctx.Reply(utils.EmojiCross + " Invalid amount.")
+				return
+			}
+			amount = n
+		}
+	}
+
+	g, err := service.DB.GetGiveaway(messageID)
+	if err != nil || g == nil || g.GuildID != ctx.GetGuildID() {
+		ctx.ReplyEphemeral(utils.EmojiCross + " Giveaway not found.")
+		return
+	}
+
+	res := service.BuyEntries(g, ctx.GetGuildID(), ctx.GetAuthor().ID, amount)
+	ctx.ReplyEphemeral(res.Message)
+}
+
+func HandleGBuy(s *discordgo.Session, i *discordgo.InteractionCreate, service *services.GiveawayService) {
+	ctx := framework.NewSlashContext(s, i)
+	GBuyCmd(ctx, service)
+}
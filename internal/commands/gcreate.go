@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"log"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
@@ -20,20 +21,27 @@ var GCreate = &discordgo.ApplicationCommand{
 		{
 			Type:        discordgo.ApplicationCommandOptionString,
 			Name:        "prize",
-			Description: "The prize to give away",
-			Required:    true,
+			Description: "The prize to give away ({date} is replaced with the start date)",
+			Required:    false,
 		},
 		{
 			Type:        discordgo.ApplicationCommandOptionInteger,
 			Name:        "winners",
 			Description: "Number of winners",
-			Required:    true,
+			Required:    false,
 		},
 		{
 			Type:        discordgo.ApplicationCommandOptionString,
 			Name:        "duration",
 			Description: "Duration (e.g. 10m, 1h, 2d)",
-			Required:    true,
+			Required:    false,
+		},
+		{
+			Type:         discordgo.ApplicationCommandOptionString,
+			Name:         "template",
+			Description:  "Saved template to start from (other options override it)",
+			Required:     false,
+			Autocomplete: true,
 		},
 		{
 			Type:        discordgo.ApplicationCommandOptionChannel,
@@ -93,9 +101,9 @@ var GCreate = &discordgo.ApplicationCommand{
 			Required:    false,
 		},
 		{
-			Type:        discordgo.ApplicationCommandOptionInteger,
+			Type:        discordgo.ApplicationCommandOptionString,
 			Name:        "required_fees",
-			Description: "Entry fee in coins",
+			Description: "Entry fee in coins: 100, 100 refund (losers refunded) or 100 pot (fees go to the winners)",
 			Required:    false,
 		},
 		{
@@ -116,6 +124,60 @@ var GCreate = &discordgo.ApplicationCommand{
 			Description: "Custom emoji for giveaway reaction (default: 🎉)",
 			Required:    false,
 		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "entry_mode",
+			Description: "How members enter (default: reaction)",
+			Required:    false,
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "Reaction", Value: models.EntryModeReaction},
+				{Name: "Buttons (Enter / Leave)", Value: models.EntryModeButton},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "bonus_roles",
+			Description: "Extra entries per role, e.g. @VIP:2 @Supporter:1",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "bonus_rules",
+			Description: "Other bonuses: booster:N tenure:DAYS:N buy:PRICE:MAX",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "claim_window",
+			Description: "Hours winners have to claim before the prize is rerolled",
+			Required:    false,
+			MinValue:    floatPtr(1),
+			MaxValue:    168,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "rewards",
+			Description: "Delivered to each winner, e.g. 500 coins, 2x VIP Pass, @Role for 7d",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "requirements",
+			Description: "Combined requirements, e.g. any:@VIP,@Booster and (messages:50@giveaway or voice:60@7d)",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "bypass_roles",
+			Description: "Roles that skip all requirements (mentions or IDs)",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "start_at",
+			Description: "Post the giveaway later: 2h, 3d, a Discord timestamp or YYYY-MM-DD HH:MM (UTC)",
+			Required:    false,
+		},
 	},
 }
 
@@ -134,120 +196,70 @@ func GCreateCmd(ctx framework.Context, service *services.GiveawayService) {
 			optionMap[opt.Name] = opt
 		}
 
-		prize := optionMap["prize"].StringValue()
-		winners := int(optionMap["winners"].IntValue())
-		durationStr := optionMap["duration"].StringValue()
-
-		duration, err := time.ParseDuration(durationStr)
-		if err != nil {
-			ctx.ReplyEphemeral("❌ Invalid duration format. Use 10m, 1h, 2d, etc.")
-			return
+		var err error
+		tmpl := models.GiveawayTemplate{Emoji: utils.EmojiGiveaway, EntryMode: models.EntryModeReaction}
+		if opt, ok := optionMap["template"]; ok {
+			saved, err := service.DB.GetTemplate(ctx.GetGuildID(), strings.ToLower(opt.StringValue()))
+			if err != nil || saved == nil {
+				ctx.ReplyEphemeral(fmt.Sprintf("❌ No template named `%s`. See `/gtemplate list`.", opt.StringValue()))
+				return
+			}
+			tmpl = saved.Template
 		}
 
-		if winners < 1 {
-			ctx.ReplyEphemeral("❌ Invalid number of winners.")
+		// Options given alongside a template override its fields
+		if err := applyTemplateOptions(&tmpl, optionMap, service, ctx.GetGuildID()); err != nil {
+			ctx.ReplyEphemeral("❌ " + err.Error())
 			return
 		}
 
-		channelID := ctx.GetChannelID()
-		if opt, ok := optionMap["channel"]; ok {
-			channelID = opt.ChannelValue(slashCtx.Session).ID
-		}
-
-		// Optional Requirements
-		var roleReq string
-		if opt, ok := optionMap["role_requirement"]; ok {
-			roleReq = opt.RoleValue(slashCtx.Session, slashCtx.GetGuildID()).ID
-		}
-
-		var inviteReq int
-		if opt, ok := optionMap["invite_requirement"]; ok {
-			inviteReq = int(opt.IntValue())
-		}
-
-		var accountAgeReq int
-		if opt, ok := optionMap["account_age"]; ok {
-			accountAgeReq = int(opt.IntValue())
-		}
-
-		var serverAgeReq int
-		if opt, ok := optionMap["server_age"]; ok {
-			serverAgeReq = int(opt.IntValue())
-		}
-
-		var captchaReq bool
-		if opt, ok := optionMap["captcha"]; ok {
-			captchaReq = opt.BoolValue()
-		}
-
-		var messageReq int
-		if opt, ok := optionMap["message_required"]; ok {
-			messageReq = int(opt.IntValue())
-		}
-
-		var voiceReq int
-		if opt, ok := optionMap["voice"]; ok {
-			voiceReq = int(opt.IntValue())
+		if tmpl.Prize == "" || tmpl.Duration <= 0 {
+			ctx.ReplyEphemeral("❌ A prize and duration are required, either directly or from a template.")
+			return
 		}
 
-		var customMessage string
-		if opt, ok := optionMap["custom_message"]; ok {
-			customMessage = opt.StringValue()
+		if tmpl.WinnersCount < 1 {
+			ctx.ReplyEphemeral("❌ Invalid number of winners.")
+			return
 		}
 
-		var entryFee int
-		if opt, ok := optionMap["required_fees"]; ok {
-			entryFee = int(opt.IntValue())
+		if err := service.CheckRewardRole(ctx.GetGuildID(), ctx.GetAuthor().ID, tmpl.Reward); err != nil {
+			ctx.ReplyEphemeral("❌ " + err.Error())
+			return
 		}
 
-		var assignRole string
-		if opt, ok := optionMap["assign_role"]; ok {
-			assignRole = opt.RoleValue(slashCtx.Session, slashCtx.GetGuildID()).ID
+		channelID := ctx.GetChannelID()
+		if opt, ok := optionMap["channel"]; ok {
+			channelID = opt.ChannelValue(slashCtx.Session).ID
 		}
 
-		var thumbnail string
-		if opt, ok := optionMap["thumbnail"]; ok {
-			thumbnail = opt.StringValue()
+		startTime := time.Now()
+		var scheduled bool
+		if opt, ok := optionMap["start_at"]; ok {
+			startTime, err = utils.ParseStartTime(opt.StringValue(), time.Now())
+			if err != nil {
+				ctx.ReplyEphemeral("❌ " + err.Error())
+				return
+			}
+			scheduled = true
 		}
 
-		var customEmoji string
-		if opt, ok := optionMap["custom_emoji"]; ok {
-			customEmoji = opt.StringValue()
-		}
-		// Default to party emoji if not specified
-		if customEmoji == "" {
-			customEmoji = "🎉"
-		}
+		g := tmpl.NewGiveaway(ctx.GetGuildID(), channelID, ctx.GetAuthor().ID, startTime.UnixMilli())
 
-		// Parse and steal emoji if needed (for custom emojis)
-		finalEmoji, err := utils.ParseAndStealEmoji(slashCtx.Session, ctx.GetGuildID(), customEmoji)
+		// Commit to the draw secret before anyone can enter
+		g.DrawSecret, g.DrawCommitment, err = services.NewDrawCommitment()
 		if err != nil {
-			log.Printf("Failed to parse/steal emoji: %v, using default", err)
-			finalEmoji = "🎉"
+			ctx.ReplyEphemeral("❌ Failed to prepare the giveaway draw. Please try again.")
+			return
 		}
 
-		endTime := time.Now().Add(duration).UnixNano() / int64(time.Millisecond)
-
-		g := &models.Giveaway{
-			ChannelID:             channelID,
-			GuildID:               ctx.GetGuildID(),
-			HostID:                ctx.GetAuthor().ID,
-			Prize:                 prize,
-			WinnersCount:          winners,
-			EndTime:               endTime,
-			CreatedAt:             models.Now(),
-			RoleRequirement:       roleReq,
-			InviteRequirement:     inviteReq,
-			AccountAgeRequirement: accountAgeReq,
-			ServerAgeRequirement:  serverAgeReq,
-			CaptchaRequirement:    captchaReq,
-			MessageRequired:       messageReq,
-			VoiceRequirement:      voiceReq,
-			CustomMessage:         customMessage,
-			EntryFee:              entryFee,
-			AssignRole:            assignRole,
-			Thumbnail:             thumbnail,
-			Emoji:                 finalEmoji, // Store the emoji
+		if scheduled {
+			if err := service.ScheduleGiveaway(g); err != nil {
+				ctx.ReplyEphemeral(fmt.Sprintf("❌ Failed to schedule giveaway: %s", err.Error()))
+				return
+			}
+			ctx.ReplyEphemeral(fmt.Sprintf("✅ Giveaway scheduled! It will be posted in <#%s> <t:%d:R> (<t:%d:f>). Use `/gscheduled` to manage it.", channelID, startTime.Unix(), startTime.Unix()))
+			return
 		}
 
 		// Send initial message
@@ -272,13 +284,16 @@ func GCreateCmd(ctx framework.Context, service *services.GiveawayService) {
 		}
 		// Invalidate cache
 		service.Redis.InvalidateActiveGiveaways(g.GuildID)
+		service.TrackReactionGiveaway(g)
 		// Add to ending queue
 		service.Redis.AddToEndingQueue(g.MessageID, g.EndTime)
 
-		// Add reaction with custom/stolen emoji
-		err = slashCtx.Session.MessageReactionAdd(channelID, msg.ID, g.Emoji)
-		if err != nil {
-			log.Printf("Failed to add reaction: %v", err)
+		// Add reaction with custom/stolen emoji (button giveaways get their components below)
+		if g.EntryMode != models.EntryModeButton {
+			err = slashCtx.Session.MessageReactionAdd(channelID, msg.ID, utils.GiveawayEmojiAPIName(g.Emoji))
+			if err != nil {
+				log.Printf("Failed to add reaction: %v", err)
+			}
 		}
 
 		// Update giveaway with real ID (if needed for embed footer or something, but usually not needed for just ID if not in footer)
@@ -290,49 +305,81 @@ func GCreateCmd(ctx framework.Context, service *services.GiveawayService) {
 
 		g.ID = id
 		newEmbed := utils.CreateGiveawayEmbed(g, 0)
-		slashCtx.Session.ChannelMessageEditEmbed(channelID, msg.ID, newEmbed)
+		// Button custom IDs carry the giveaway ID, so they can only be attached now
+		edit := discordgo.NewMessageEdit(channelID, msg.ID).SetEmbed(newEmbed)
+		components := utils.GiveawayComponents(g, 0)
+		edit.Components = &components
+		slashCtx.Session.ChannelMessageEditComplex(edit)
 
 		ctx.ReplyEphemeral("✅ Giveaway created successfully!")
 
 	} else if prefixCtx, ok := ctx.(*framework.PrefixContext); ok {
 		// Legacy prefix command support (simplified)
 		// !gcreate <duration> <winners> <prize>
-		if len(prefixCtx.Args) < 3 {
-			ctx.Reply("Usage: `!gcreate <duration> <winners> <prize>`")
+		// !gcreate <template> [duration] [winners] [prize]
+		usage := "Usage: `!gcreate <duration> <winners> <prize>` or `!gcreate <template> [duration] [winners] [prize]`"
+		args := prefixCtx.Args
+		if len(args) < 1 {
+			ctx.Reply(usage)
 			return
 		}
 
-		durationStr := prefixCtx.Args[0]
-		winnersStr := prefixCtx.Args[1]
-		prize := ""
-		for i := 2; i < len(prefixCtx.Args); i++ {
-			prize += prefixCtx.Args[i] + " "
+		tmpl := models.GiveawayTemplate{Emoji: utils.EmojiGiveaway, EntryMode: models.EntryModeReaction}
+		if _, err := utils.ParseDays(args[0]); err != nil {
+			saved, err := service.DB.GetTemplate(ctx.GetGuildID(), strings.ToLower(args[0]))
+			if err != nil || saved == nil {
+				ctx.Reply(fmt.Sprintf("❌ No template named `%s`.\n%s", args[0], usage))
+				return
+			}
+			tmpl = saved.Template
+			args = args[1:]
+		} else if len(args) < 3 {
+			ctx.Reply(usage)
+			return
 		}
-		prize = prize[:len(prize)-1]
 
-		duration, err := time.ParseDuration(durationStr)
-		if err != nil {
-			ctx.Reply("❌ Invalid duration format. Use 10m, 1h, 2d, etc.")
+		// Remaining arguments override the template in order: duration, winners, prize
+		if len(args) > 0 {
+			duration, err := utils.ParseDays(args[0])
+			if err != nil || duration <= 0 {
+				ctx.Reply("❌ Invalid duration format. Use 10m, 1h, 2d, etc.")
+				return
+			}
+			tmpl.Duration = duration.Milliseconds()
+			args = args[1:]
+		}
+		if len(args) > 0 {
+			winners, err := strconv.Atoi(args[0])
+			if err != nil || winners < 1 {
+				ctx.Reply("❌ Invalid number of winners.")
+				return
+			}
+			tmpl.WinnersCount = winners
+			args = args[1:]
+		}
+		if len(args) > 0 {
+			tmpl.Prize = strings.Join(args, " ")
+		}
+
+		if tmpl.Prize == "" || tmpl.Duration <= 0 || tmpl.WinnersCount < 1 {
+			ctx.Reply("❌ This template doesn't set a prize, duration and winners. " + usage)
 			return
 		}
 
-		winners, err := strconv.Atoi(winnersStr)
-		if err != nil || winners < 1 {
-			ctx.Reply("❌ Invalid number of winners.")
+		if err := service.CheckRewardRole(ctx.GetGuildID(), ctx.GetAuthor().ID, tmpl.Reward); err != nil {
+			ctx.Reply("❌ " + err.Error())
 			return
 		}
 
 		// Create giveaway
-		endTime := time.Now().Add(duration).UnixNano() / int64(time.Millisecond)
-
-		g := &models.Giveaway{
-			ChannelID:    ctx.GetChannelID(),
-			GuildID:      ctx.GetGuildID(),
-			HostID:       ctx.GetAuthor().ID,
-			Prize:        prize,
-			WinnersCount: winners,
-			EndTime:      endTime,
-			CreatedAt:    models.Now(),
+		g := tmpl.NewGiveaway(ctx.GetGuildID(), ctx.GetChannelID(), ctx.GetAuthor().ID, models.Now())
+
+		var err error
+		// Commit to the draw secret before anyone can enter
+		g.DrawSecret, g.DrawCommitment, err = services.NewDrawCommitment()
+		if err != nil {
+			ctx.Reply("❌ Failed to prepare the giveaway draw. Please try again.")
+			return
 		}
 
 		// Send initial message
@@ -354,19 +401,25 @@ func GCreateCmd(ctx framework.Context, service *services.GiveawayService) {
 		}
 		// Invalidate cache
 		service.Redis.InvalidateActiveGiveaways(g.GuildID)
+		service.TrackReactionGiveaway(g)
 		// Add to ending queue
 		service.Redis.AddToEndingQueue(g.MessageID, g.EndTime)
 
-		// Add reaction
-		err = ctx.GetSession().MessageReactionAdd(ctx.GetChannelID(), msg.ID, "🎉")
-		if err != nil {
-			log.Printf("Failed to add reaction: %v", err)
+		// Add reaction (button giveaways from templates get their components below)
+		if g.EntryMode != models.EntryModeButton {
+			err = ctx.GetSession().MessageReactionAdd(ctx.GetChannelID(), msg.ID, utils.GiveawayEmojiAPIName(g.Emoji))
+			if err != nil {
+				log.Printf("Failed to add reaction: %v", err)
+			}
 		}
 
 		// Update giveaway with real ID
 		g.ID = id
 		newEmbed := utils.CreateGiveawayEmbed(g, 0)
-		ctx.GetSession().ChannelMessageEditEmbed(ctx.GetChannelID(), msg.ID, newEmbed)
+		edit := discordgo.NewMessageEdit(ctx.GetChannelID(), msg.ID).SetEmbed(newEmbed)
+		components := utils.GiveawayComponents(g, 0)
+		edit.Components = &components
+		ctx.GetSession().ChannelMessageEditComplex(edit)
 	}
 }
 
@@ -0,0 +1,422 @@
+package commands
+
+import (
+	"discord-giveaway-bot/internal/commands/framework"
+	"discord-giveaway-bot/internal/models"
+	"discord-giveaway-bot/internal/services"
+	"discord-giveaway-bot/internal/utils"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var GEdit = &discordgo.ApplicationCommand{
+	Name:        "gedit",
+	Description: "Edit a running giveaway",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "message_id",
+			Description: "Message ID of the giveaway",
+			Required:    true,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "prize",
+			Description: "New prize",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "winners",
+			Description: "New number of winners",
+			Required:    false,
+			MinValue:    floatPtr(1),
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "end_time",
+			Description: "+1d / -30m to extend or shorten, or 2h to end 2 hours from now",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "custom_message",
+			Description: "New custom message (\"none\" to clear)",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "thumbnail",
+			Description: "New thumbnail URL (\"none\" to clear)",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionRole,
+			Name:        "role_requirement",
+			Description: "New required role",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionBoolean,
+			Name:        "remove_role_requirement",
+			Description: "Remove the role requirement",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "invite_requirement",
+			Description: "Minimum invites required (0 to remove)",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "account_age",
+			Description: "Minimum account age in days (0 to remove)",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "server_age",
+			Description: "Minimum days in server (0 to remove)",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "message_required",
+			Description: "Minimum messages required (0 to remove)",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "voice",
+			Description: "Minimum voice minutes required (0 to remove)",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionBoolean,
+			Name:        "captcha",
+			Description: "Require captcha verification",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "requirements",
+			Description: "New combined requirements (\"none\" to clear)",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "bypass_roles",
+			Description: "Roles that skip all requirements (\"none\" to clear)",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "win_limits",
+			Description: "Override the server's win limits: 3/30d cooldown:7d, none, or default",
+			Required:    false,
+		},
+	},
+}
+
+func GEditCmd(ctx framework.Context, service *services.GiveawayService) {
+	if ctx.GetMember().Permissions&discordgo.PermissionManageGuild == 0 {
+		ctx.ReplyEphemeral(utils.EmojiCross + " You need Manage Server permissions.")
+		return
+	}
+
+	// Collect the requested changes as name -> raw value so slash and prefix share validation
+	var messageID string
+	values := make(map[string]string)
+
+	if slashCtx, ok := ctx.(*framework.SlashContext); ok {
+		for _, opt := range slashCtx.Interaction.ApplicationCommandData().Options {
+			switch opt.Type {
+			case discordgo.ApplicationCommandOptionInteger:
+				values[opt.Name] = strconv.FormatInt(opt.IntValue(), 10)
+			case discordgo.ApplicationCommandOptionBoolean:
+				values[opt.Name] = strconv.FormatBool(opt.BoolValue())
+			case discordgo.ApplicationCommandOptionRole:
+				values[opt.Name] = opt.RoleValue(nil, "").ID
+			default:
+				values[opt.Name] = opt.StringValue()
+			}
+		}
+		messageID = values["message_id"]
+		delete(values, "message_id")
+	} else if prefixCtx, ok := ctx.(*framework.PrefixContext); ok {
+		if len(prefixCtx.Args) < 3 {
+			ctx.Reply("Usage: `!gedit <message_id> <prize|winners|end_time|custom_message|thumbnail|requirements|bypass_roles|win_limits> <value>`")
+			return
+		}
+		messageID = prefixCtx.Args[0]
+		values[strings.ToLower(prefixCtx.Args[1])] = strings.Join(prefixCtx.Args[2:], " ")
+	}
+
+	if len(values) == 0 {
+		ctx.ReplyEphemeral(utils.EmojiCross + " Nothing to change. Pick at least one option to edit.")
+		return
+	}
+
+	g, err := service.DB.GetGiveaway(messageID)
+	if err != nil || g == nil || g.GuildID != ctx.GetGuildID() {
+		ctx.ReplyEphemeral(utils.EmojiCross + " Giveaway not found.")
+		return
+	}
+	if g.Ended {
+		ctx.ReplyEphemeral(utils.EmojiCross + " This giveaway has already ended.")
+		return
+	}
+
+	changes, err := applyGiveawayEdits(g, values)
+	if err != nil {
+		ctx.ReplyEphemeral(utils.EmojiCross + " " + err.Error())
+		return
+	}
+	if len(changes) == 0 {
+		ctx.ReplyEphemeral(utils.EmojiCross + " Those values match the current giveaway, nothing changed.")
+		return
+	}
+
+	if err := service.EditGiveaway(g, ctx.GetAuthor().ID, changes); err != nil {
+		ctx.ReplyEphemeral(fmt.Sprintf("%s Failed to edit giveaway: %s", utils.EmojiCross, err.Error()))
+		return
+	}
+
+	ctx.ReplyEphemeral(utils.EmojiTick + " Giveaway updated:\n• " + strings.Join(changes, "\n• "))
+}
+
+// applyGiveawayEdits validates and applies the edits to g, returning a description of each change
+func applyGiveawayEdits(g *models.Giveaway, values map[string]string) ([]string, error) {
+	var changes []string
+	changed := func(field, from, to string) {
+		if from != to {
+			changes = append(changes, fmt.Sprintf("%s: %s → %s", field, from, to))
+		}
+	}
+	orNone := func(s string) string {
+		if s == "" {
+			return "none"
+		}
+		return s
+	}
+	clearable := func(s string) string {
+		if strings.EqualFold(s, "none") {
+			return ""
+		}
+		return s
+	}
+	intValue := func(name, value string) (int, error) {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid value for %s", name)
+		}
+		return n, nil
+	}
+	boolValue := func(name, value string) (bool, error) {
+		switch strings.ToLower(strings.TrimSpace(value)) {
+		case "yes", "on":
+			return true, nil
+		case "no", "off":
+			return false, nil
+		}
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return false, fmt.Errorf("invalid value for %s, use true or false", name)
+		}
+		return b, nil
+	}
+
+	_, roleSet := values["role_requirement"]
+
+	// Walk the options in declaration order so the change log reads consistently
+	for _, opt := range GEdit.Options[1:] {
+		name := opt.Name
+		value, ok := values[name]
+		if !ok {
+			continue
+		}
+		delete(values, name)
+
+		switch name {
+		case "prize":
+			if strings.TrimSpace(value) == "" {
+				return nil, fmt.Errorf("prize can't be empty")
+			}
+			changed("Prize", g.Prize, value)
+			g.Prize = value
+
+		case "winners":
+			n, err := intValue(name, value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid number of winners")
+			}
+			changed("Winners", strconv.Itoa(g.WinnersCount), strconv.Itoa(n))
+			g.WinnersCount = n
+
+		case "end_time":
+			if g.Paused() {
+				return nil, fmt.Errorf("the end time can't be changed while the giveaway is paused")
+			}
+			endTime, err := editedEndTime(g.EndTime, value)
+			if err != nil {
+				return nil, err
+			}
+			changed("Ends", fmt.Sprintf("<t:%d:f>", g.EndTime/1000), fmt.Sprintf("<t:%d:f>", endTime/1000))
+			g.EndTime = endTime
+
+		case "custom_message":
+			value = clearable(value)
+			changed("Custom message", orNone(g.CustomMessage), orNone(value))
+			g.CustomMessage = value
+
+		case "thumbnail":
+			value = clearable(value)
+			changed("Thumbnail", orNone(g.Thumbnail), orNone(value))
+			g.Thumbnail = value
+
+		case "role_requirement":
+			// Prefix edits pass a mention or ID; "none" clears it
+			roles, err := utils.ParseRoleList(clearable(value))
+			if err != nil || len(roles) > 1 {
+				return nil, fmt.Errorf("invalid role for role_requirement, use a role mention, ID or none")
+			}
+			role := ""
+			if len(roles) == 1 {
+				role = roles[0]
+			}
+			changed("Required role", roleOrNone(g.RoleRequirement), roleOrNone(role))
+			g.RoleRequirement = role
+
+		case "remove_role_requirement":
+			remove, err := boolValue(name, value)
+			if err != nil {
+				return nil, err
+			}
+			if remove && !roleSet {
+				changed("Required role", roleOrNone(g.RoleRequirement), "none")
+				g.RoleRequirement = ""
+			}
+
+		case "invite_requirement", "account_age", "server_age", "message_required", "voice":
+			n, err := intValue(name, value)
+			if err != nil {
+				return nil, err
+			}
+			field, target := requirementField(g, name)
+			changed(field, strconv.Itoa(*target), strconv.Itoa(n))
+			*target = n
+
+		case "captcha":
+			enabled, err := boolValue(name, value)
+			if err != nil {
+				return nil, err
+			}
+			changed("Captcha", strconv.FormatBool(g.CaptchaRequirement), strconv.FormatBool(enabled))
+			g.CaptchaRequirement = enabled
+
+		case "requirements":
+			var group *models.RequirementGroup
+			if value = clearable(value); value != "" {
+				parsed, err := utils.ParseRequirements(value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid requirements: %v", err)
+				}
+				group = parsed
+			}
+			changed("Requirements", utils.FormatRequirements(g.Requirements), utils.FormatRequirements(group))
+			g.Requirements = group
+
+		case "bypass_roles":
+			roles, err := utils.ParseRoleList(clearable(value))
+			if err != nil {
+				return nil, err
+			}
+			changed("Bypass roles", rolesOrNone(g.BypassRoles), rolesOrNone(roles))
+			g.BypassRoles = roles
+
+		case "win_limits":
+			// "default" drops the override so the server's limits apply again
+			var limits *models.WinLimits
+			if !strings.EqualFold(strings.TrimSpace(value), "default") {
+				parsed, err := utils.ParseWinLimits(value)
+				if err != nil {
+					return nil, err
+				}
+				limits = parsed
+			}
+			changed("Win limits", utils.FormatWinLimits(g.WinLimits), utils.FormatWinLimits(limits))
+			g.WinLimits = limits
+		}
+	}
+	for name := range values {
+		return nil, fmt.Errorf("unknown field `%s`", name)
+	}
+	return changes, nil
+}
+
+// editedEndTime parses "+1d"/"-30m" (relative to the current end) or "2h" (from now)
+func editedEndTime(current int64, value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	base, sign := models.Now(), int64(1)
+	switch {
+	case strings.HasPrefix(value, "+"):
+		base, value = current, value[1:]
+	case strings.HasPrefix(value, "-"):
+		base, sign, value = current, -1, value[1:]
+	}
+
+	d, err := utils.ParseDays(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid end time. Use +1d, -30m or 2h")
+	}
+	d *= time.Duration(sign)
+
+	endTime := base + d.Milliseconds()
+	if endTime <= models.Now() {
+		return 0, fmt.Errorf("the new end time is in the past. Use /gend to end the giveaway now")
+	}
+	return endTime, nil
+}
+
+func requirementField(g *models.Giveaway, name string) (string, *int) {
+	switch name {
+	case "invite_requirement":
+		return "Invites", &g.InviteRequirement
+	case "account_age":
+		return "Account age (days)", &g.AccountAgeRequirement
+	case "server_age":
+		return "Server age (days)", &g.ServerAgeRequirement
+	case "message_required":
+		return "Messages", &g.MessageRequired
+	default:
+		return "Voice minutes", &g.VoiceRequirement
+	}
+}
+
+func roleOrNone(roleID string) string {
+	if roleID == "" {
+		return "none"
+	}
+	return fmt.Sprintf("<@&%s>", roleID)
+}
+
+func rolesOrNone(roleIDs []string) string {
+	if len(roleIDs) == 0 {
+		return "none"
+	}
+	mentions := make([]string, len(roleIDs))
+	for i, id := range roleIDs {
+		mentions[i] = roleOrNone(id)
+	}
+	return strings.Join(mentions, ", ")
+}
+
+func HandleGEdit(s *discordgo.Session, i *discordgo.InteractionCreate, service *services.GiveawayService) {
+	ctx := framework.NewSlashContext(s, i)
+	GEditCmd(ctx, service)
+}
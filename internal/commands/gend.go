@@ -51,6 +51,10 @@ func GEndCmd(ctx framework.Context, db *database.Database, service *services.Giv
 		ctx.ReplyEphemeral(utils.EmojiCross + " Giveaway already ended.")
 		return
 	}
+	if g.Paused() {
+		ctx.ReplyEphemeral(utils.EmojiCross + " This giveaway is paused. Use /gresume before ending it.")
+		return
+	}
 
 	err = service.EndGiveaway(g.MessageID)
 	if err != nil {
@@ -0,0 +1,281 @@
+package commands
+
+import (
+	"discord-giveaway-bot/internal/commands/framework"
+	"discord-giveaway-bot/internal/models"
+	"discord-giveaway-bot/internal/services"
+	"discord-giveaway-bot/internal/utils"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var GHistory = &discordgo.ApplicationCommand{
+	Name:        "ghistory",
+	Description: "Browse this server's past and running giveaways",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionUser,
+			Name:        "host",
+			Description: "Only giveaways hosted by this user",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "status",
+			Description: "Only giveaways with this status (older cancellations are listed as ended)",
+			Required:    false,
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "Active", Value: models.HistoryStatusActive},
+				{Name: "Ended", Value: models.HistoryStatusEnded},
+				{Name: "Cancelled", Value: models.HistoryStatusCancelled},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "from",
+			Description: "Ending on or after: YYYY-MM-DD or e.g. 30d (ago)",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "to",
+			Description: "Ending on or before: YYYY-MM-DD or e.g. 7d (ago)",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "page",
+			Description: "Page to show",
+			Required:    false,
+			MinValue:    floatPtr(1),
+		},
+	},
+}
+
+const historyPerPage = 10
+
+func GHistoryCmd(ctx framework.Context, service *services.GiveawayService) {
+	var filter models.HistoryFilter
+	var from, to string
+	page := 1
+
+	if slashCtx, ok := ctx.(*framework.SlashContext); ok {
+		for _, opt := range slashCtx.Interaction.ApplicationCommandData().Options {
+			switch opt.Name {
+			case "host":
+				filter.HostID = opt.UserValue(nil).ID
+			case "status":
+				filter.Status = opt.StringValue()
+			case "from":
+				from = opt.StringValue()
+			case "to":
+				to = opt.StringValue()
+			case "page":
+				page = int(opt.IntValue())
+			}
+		}
+	} else if prefixCtx, ok := ctx.(*framework.PrefixContext); ok {
+		// !ghistory [page] [@host] [active|ended|cancelled] [from:<date>] [to:<date>]
+		for _, arg := range prefixCtx.Args {
+			lower := strings.ToLower(arg)
+			switch {
+			case lower == models.HistoryStatusActive || lower == models.HistoryStatusEnded || lower == models.HistoryStatusCancelled:
+				filter.Status = lower
+			case strings.HasPrefix(lower, "from:"):
+				from = arg[len("from:"):]
+			case strings.HasPrefix(lower, "to:"):
+				to = arg[len("to:"):]
+			case strings.HasPrefix(arg, "<@"):
+				filter.HostID = strings.Trim(arg, "<@!>")
+			default:
+				if n, err := strconv.Atoi(arg); err == nil {
+					page = n
+				}
+			}
+		}
+	}
+
+	now := time.Now()
+	if from != "" {
+		t, err := historyDate(from, now, false)
+		if err != nil {
+			ctx.ReplyEphemeral(utils.EmojiCross + " Invalid `from`: " + err.Error())
+			return
+		}
+		filter.From = t.UnixMilli()
+	}
+	if to != "" {
+		t, err := historyDate(to, now, true)
+		if err != nil {
+			ctx.ReplyEphemeral(utils.EmojiCross + " Invalid `to`: " + err.Error())
+			return
+		}
+		filter.To = t.UnixMilli()
+	}
+
+	embed, components, err := historyPage(service, ctx.GetGuildID(), filter, page)
+	if err != nil {
+		ctx.ReplyEphemeral(fmt.Sprintf("%s Failed to load giveaway history: %s", utils.EmojiCross, err.Error()))
+		return
+	}
+	ctx.ReplyComponent(embed, components)
+}
+
+// historyDate parses a YYYY-MM-DD date (UTC) or a duration ago like "30d".
+// Dates used as an upper bound cover the whole day.
+func historyDate(input string, now time.Time, endOfDay bool) (time.Time, error) {
+	input = strings.TrimSpace(input)
+	if t, err := time.Parse("2006-01-02", input); err == nil {
+		if endOfDay {
+			t = t.Add(24*time.Hour - time.Millisecond)
+		}
+		return t, nil
+	}
+	if d, err := utils.ParseDays(input); err == nil && d > 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("use YYYY-MM-DD or a duration ago like `30d`")
+}
+
+// historyPage renders one page of the guild's giveaway history. The filter is
+// carried in the Previous/Next button IDs so paging keeps it.
+func historyPage(service *services.GiveawayService, guildID string, filter models.HistoryFilter, page int) (*discordgo.MessageEmbed, []discordgo.MessageComponent, error) {
+	count, err := service.DB.CountGiveawayHistory(guildID, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	totalPages := (count + historyPerPage - 1) / historyPerPage
+	if totalPages < 1 {
+		totalPages = 1
+	}
+	if page > totalPages {
+		page = totalPages
+	}
+	if page < 1 {
+		page = 1
+	}
+
+	history, err := service.DB.GetGiveawayHistory(guildID, filter, (page-1)*historyPerPage, historyPerPage)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var sb strings.Builder
+	if desc := describeHistoryFilter(filter); desc != "" {
+		sb.WriteString(desc + "\n\n")
+	}
+	if len(history) == 0 {
+		sb.WriteString("No giveaways found.")
+	}
+	for _, g := range history {
+		status, when := "🟢 Active", fmt.Sprintf("ends <t:%d:R>", g.EndTime/1000)
+		switch {
+		case g.Cancelled:
+			status, when = "🚫 Cancelled", fmt.Sprintf("<t:%d:d>", g.EndTime/1000)
+		case g.Ended:
+			status, when = "🏁 Ended", fmt.Sprintf("<t:%d:d>", g.EndTime/1000)
+		}
+
+		sb.WriteString(fmt.Sprintf("**[%s](https://discord.com/channels/%s/%s/%s)** • %s • %s\n",
+			g.Prize, guildID, g.ChannelID, g.MessageID, status, when))
+		line := fmt.Sprintf("Hosted by <@%s> • %d participant(s)", g.HostID, g.Participants)
+		if g.Ended && !g.Cancelled {
+			line += " • Winners: " + mentionList(g.Winners)
+		}
+		sb.WriteString(line + "\n\n")
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "📜 Giveaway History",
+		Description: sb.String(),
+		Color:       0x2f3136,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Page %d of %d • %d Giveaways", page, totalPages, count),
+		},
+	}
+
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Previous",
+					Style:    discordgo.SecondaryButton,
+					CustomID: historyButtonID(filter, page-1),
+					Disabled: page <= 1,
+				},
+				discordgo.Button{
+					Label:    "Next",
+					Style:    discordgo.SecondaryButton,
+					CustomID: historyButtonID(filter, page+1),
+					Disabled: page >= totalPages,
+				},
+			},
+		},
+	}
+	return embed, components, nil
+}
+
+func describeHistoryFilter(f models.HistoryFilter) string {
+	var parts []string
+	if f.Status != "" {
+		parts = append(parts, "**"+strings.ToUpper(f.Status[:1])+f.Status[1:]+"**")
+	}
+	if f.HostID != "" {
+		parts = append(parts, fmt.Sprintf("hosted by <@%s>", f.HostID))
+	}
+	if f.From > 0 {
+		parts = append(parts, fmt.Sprintf("ending after <t:%d:d>", f.From/1000))
+	}
+	if f.To > 0 {
+		parts = append(parts, fmt.Sprintf("ending before <t:%d:d>", f.To/1000))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "Filter: " + strings.Join(parts, ", ")
+}
+
+// historyButtonID encodes a page and filter as ghistory_<page>_<host>_<status>_<from>_<to>
+func historyButtonID(f models.HistoryFilter, page int) string {
+	return fmt.Sprintf("ghistory_%d_%s_%s_%d_%d", page, f.HostID, f.Status, f.From, f.To)
+}
+
+func HandleGHistory(s *discordgo.Session, i *discordgo.InteractionCreate, service *services.GiveawayService) {
+	ctx := framework.NewSlashContext(s, i)
+	GHistoryCmd(ctx, service)
+}
+
+// HandleGHistoryPage flips a history listing to another page
+func HandleGHistoryPage(s *discordgo.Session, i *discordgo.InteractionCreate, service *services.GiveawayService) {
+	parts := strings.Split(i.MessageComponentData().CustomID, "_")
+	if len(parts) != 6 {
+		return
+	}
+	page, _ := strconv.Atoi(parts[1])
+	filter := models.HistoryFilter{HostID: parts[2], Status: parts[3]}
+	filter.From, _ = strconv.ParseInt(parts[4], 10, 64)
+	filter.To, _ = strconv.ParseInt(parts[5], 10, 64)
+
+	embed, components, err := historyPage(service, i.GuildID, filter, page)
+	if err != nil {
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: utils.EmojiCross + " Failed to load giveaway history.",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: components,
+		},
+	})
+}
@@ -0,0 +1,177 @@
+package commands
+
+import (
+	"discord-giveaway-bot/internal/commands/framework"
+	"discord-giveaway-bot/internal/models"
+	"discord-giveaway-bot/internal/services"
+	"discord-giveaway-bot/internal/utils"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var GInfo = &discordgo.ApplicationCommand{
+	Name:        "ginfo",
+	Description: "Show everything about a giveaway",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "message_id",
+			Description: "Message ID of the giveaway",
+			Required:    true,
+		},
+	},
+}
+
+// infoHistoryLimit is how many rerolls and edits /ginfo lists, newest last
+const infoHistoryLimit = 5
+
+func GInfoCmd(ctx framework.Context, service *services.GiveawayService) {
+	var messageID string
+
+	if slashCtx, ok := ctx.(*framework.SlashContext); ok {
+		messageID = slashCtx.Interaction.ApplicationCommandData().Options[0].StringValue()
+	} else if prefixCtx, ok := ctx.(*framework.PrefixContext); ok {
+		if len(prefixCtx.Args) < 1 {
+			ctx.Reply("Usage: `!ginfo <message_id>`")
+			return
+		}
+		messageID = prefixCtx.Args[0]
+	}
+
+	g, err := service.DB.GetGiveaway(messageID)
+	if err != nil || g == nil || g.GuildID != ctx.GetGuildID() {
+		ctx.ReplyEphemeral(utils.EmojiCross + " Giveaway not found.")
+		return
+	}
+
+	count, _ := service.DB.GetParticipantCount(g.ID)
+	entries, _ := service.DB.GetEntryTotal(g.ID)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("**Hosted By:** <@%s>\n**Winners:** %d\n**Status:** %s\n", g.HostID, g.WinnersCount, giveawayStatus(g)))
+	sb.WriteString(fmt.Sprintf("**Participants:** %d (%d %s)\n", count, entries, pluralEntries(entries)))
+	if g.ChannelID != "" && g.MessageID != "" && !g.Scheduled {
+		sb.WriteString(fmt.Sprintf("**Message:** https://discord.com/channels/%s/%s/%s\n", g.GuildID, g.ChannelID, g.MessageID))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "ℹ️ " + g.Prize,
+		Description: sb.String(),
+		Color:       0x2f3136,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Giveaway #%d", g.ID),
+		},
+	}
+	addField := func(name, value string) {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: name, Value: fieldValue(value)})
+	}
+
+	requirements := "None"
+	if lines := utils.RequirementLines(g); len(lines) > 0 {
+		requirements = strings.Join(lines, "\n")
+	}
+	addField("Requirements", requirements)
+
+	if !g.Reward.Empty() {
+		addField("Rewards", utils.RewardSummary(g.Reward, "coins"))
+	}
+
+	if g.EntryFee > 0 {
+		fee := fmt.Sprintf("**%d** coins", g.EntryFee)
+		if label := utils.FeeModeLabel(g.FeeMode); label != "" {
+			fee += " (" + label + ")"
+		}
+		if totals, err := service.DB.GetEscrowTotals(g.ID); err == nil && len(totals) > 0 {
+			fee += fmt.Sprintf("\nCollected **%d** • Refunded **%d** • Kept **%d** • Paid out **%d**",
+				totals[models.EscrowDeposit], totals[models.EscrowRefund], totals[models.EscrowKept], totals[models.EscrowPayout])
+		}
+		addField("Entry Fee", fee)
+	}
+
+	addField("Win Limits", fmt.Sprintf("%s (%s)", utils.DescribeWinLimits(service.WinLimits(g)), utils.FormatWinLimits(g.WinLimits)))
+
+	if g.Ended {
+		if winners, err := service.DB.GetWinnerRecords(g.ID); err == nil && len(winners) > 0 {
+			var lines []string
+			for _, w := range winners {
+				lines = append(lines, fmt.Sprintf("<@%s> %s <t:%d:R>", w.UserID, w.Status, w.WonAt/1000))
+			}
+			addField("Winners", strings.Join(lines, "\n"))
+		} else if err == nil {
+			addField("Winners", "None")
+		}
+	}
+
+	if rerolls, err := service.DB.GetRerollHistory(g.ID); err == nil && len(rerolls) > 0 {
+		var lines []string
+		for _, r := range lastN(rerolls, infoHistoryLimit) {
+			by := "automatically (" + r.Reason + ")"
+			if r.RerolledBy != "" {
+				by = fmt.Sprintf("by <@%s>", r.RerolledBy)
+			}
+			line := fmt.Sprintf("<t:%d:R> %s → %s", r.CreatedAt/1000, by, mentionList(r.Winners))
+			if r.ExcludedUser != "" {
+				line += fmt.Sprintf(" (replaced <@%s>)", r.ExcludedUser)
+			}
+			lines = append(lines, line)
+		}
+		addField(fmt.Sprintf("Reroll History (%d)", len(rerolls)), strings.Join(lines, "\n"))
+	}
+
+	if edits, err := service.DB.GetGiveawayEdits(g.ID); err == nil && len(edits) > 0 {
+		var lines []string
+		for _, e := range lastN(edits, infoHistoryLimit) {
+			lines = append(lines, fmt.Sprintf("<t:%d:R> <@%s>: %s", e.CreatedAt/1000, e.EditorID, strings.Join(e.Changes, "; ")))
+		}
+		addField(fmt.Sprintf("Edit History (%d)", len(edits)), strings.Join(lines, "\n"))
+	}
+
+	ctx.ReplyEmbed(embed)
+}
+
+// giveawayStatus describes where a giveaway is in its lifecycle
+func giveawayStatus(g *models.Giveaway) string {
+	switch {
+	case g.Ended:
+		return fmt.Sprintf("Ended <t:%d:f>", g.EndTime/1000)
+	case g.Scheduled:
+		return fmt.Sprintf("Scheduled, starts <t:%d:f> (<t:%d:R>)", g.StartTime/1000, g.StartTime/1000)
+	case g.Paused():
+		return fmt.Sprintf("⏸️ Paused since <t:%d:R>, **%s** left once resumed", g.PausedAt/1000, utils.FormatRemaining(g.PausedRemaining))
+	default:
+		return fmt.Sprintf("Running, ends <t:%d:f> (<t:%d:R>)", g.EndTime/1000, g.EndTime/1000)
+	}
+}
+
+func mentionList(ids []string) string {
+	if len(ids) == 0 {
+		return "nobody"
+	}
+	mentions := make([]string, len(ids))
+	for i, id := range ids {
+		mentions[i] = fmt.Sprintf("<@%s>", id)
+	}
+	return strings.Join(mentions, ", ")
+}
+
+func lastN[T any](items []T, n int) []T {
+	if len(items) > n {
+		return items[len(items)-n:]
+	}
+	return items
+}
+
+// fieldValue keeps an embed field inside Discord's 1024 character limit
+func fieldValue(s string) string {
+	if r := []rune(s); len(r) > 1024 {
+		return string(r[:1021]) + "..."
+	}
+	return s
+}
+
+func HandleGInfo(s *discordgo.Session, i *discordgo.InteractionCreate, service *services.GiveawayService) {
+	ctx := framework.NewSlashContext(s, i)
+	GInfoCmd(ctx, service)
+}
@@ -0,0 +1,309 @@
+package commands
+
+import (
+	"bytes"
+	"discord-giveaway-bot/internal/commands/framework"
+	"discord-giveaway-bot/internal/models"
+	"discord-giveaway-bot/internal/services"
+	"discord-giveaway-bot/internal/utils"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var GParticipants = &discordgo.ApplicationCommand{
+	Name:        "gparticipants",
+	Description: "List who entered a giveaway",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "message_id",
+			Description: "Message ID of the giveaway",
+			Required:    true,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "page",
+			Description: "Page to show",
+			Required:    false,
+			MinValue:    floatPtr(1),
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionBoolean,
+			Name:        "export",
+			Description: "Attach the full list as a CSV file",
+			Required:    false,
+		},
+	},
+}
+
+const participantsPerPage = 20
+
+func GParticipantsCmd(ctx framework.Context, service *services.GiveawayService) {
+	var messageID string
+	page := 1
+	export := false
+
+	if slashCtx, ok := ctx.(*framework.SlashContext); ok {
+		for _, opt := range slashCtx.Interaction.ApplicationCommandData().Options {
+			switch opt.Name {
+			case "message_id":
+				messageID = opt.StringValue()
+			case "page":
+				page = int(opt.IntValue())
+			case "export":
+				export = opt.BoolValue()
+			}
+		}
+	} else if prefixCtx, ok := ctx.(*framework.PrefixContext); ok {
+		if len(prefixCtx.Args) < 1 {
+			ctx.Reply("Usage: `!gparticipants <message_id> [page|csv]`")
+			return
+		}
+		messageID = prefixCtx.Args[0]
+		if len(prefixCtx.Args) > 1 {
+			if strings.EqualFold(prefixCtx.Args[1], "csv") {
+				export = true
+			} else if n, err := strconv.Atoi(prefixCtx.Args[1]); err == nil {
+				page = n
+			}
+		}
+	}
+
+	g, err := service.DB.GetGiveaway(messageID)
+	if err != nil || g == nil || g.GuildID != ctx.GetGuildID() {
+		ctx.ReplyEphemeral(utils.EmojiCross + " Giveaway not found.")
+		return
+	}
+	if !canViewEntries(g, ctx.GetAuthor().ID, memberPermissions(ctx)) {
+		ctx.ReplyEphemeral(utils.EmojiCross + " Only the host or members with Manage Server can see the participants.")
+		return
+	}
+
+	if export {
+		participants, err := service.DB.GetParticipants(g.ID, 0, 0)
+		if err != nil {
+			ctx.ReplyEphemeral(fmt.Sprintf("%s Failed to load participants: %s", utils.EmojiCross, err.Error()))
+			return
+		}
+		file, err := participantsCSV(g, participants)
+		if err != nil {
+			ctx.ReplyEphemeral(fmt.Sprintf("%s Failed to export participants: %s", utils.EmojiCross, err.Error()))
+			return
+		}
+		embed := &discordgo.MessageEmbed{
+			Title:       "👥 Participants: " + g.Prize,
+			Description: fmt.Sprintf("**%d** participant(s) exported.", len(participants)),
+			Color:       0x2f3136,
+		}
+		replyPrivately(ctx, embed, nil, []*discordgo.File{file})
+		return
+	}
+
+	embed, components, err := participantsPage(service, g, page)
+	if err != nil {
+		ctx.ReplyEphemeral(fmt.Sprintf("%s Failed to load participants: %s", utils.EmojiCross, err.Error()))
+		return
+	}
+	replyPrivately(ctx, embed, components, nil)
+}
+
+// replyPrivately keeps participant lists out of the channel: ephemeral for
+// slash commands, a DM for prefix commands. Page buttons only work in the
+// guild, so DMs go without them.
+func replyPrivately(ctx framework.Context, embed *discordgo.MessageEmbed, components []discordgo.MessageComponent, files []*discordgo.File) {
+	if slashCtx, ok := ctx.(*framework.SlashContext); ok {
+		slashCtx.Session.InteractionRespond(slashCtx.Interaction.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Embeds:     []*discordgo.MessageEmbed{embed},
+				Components: components,
+				Files:      files,
+				Flags:      discordgo.MessageFlagsEphemeral,
+			},
+		})
+		return
+	}
+
+	s := ctx.GetSession()
+	dm, err := s.UserChannelCreate(ctx.GetAuthor().ID)
+	if err == nil {
+		_, err = s.ChannelMessageSendComplex(dm.ID, &discordgo.MessageSend{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Files:  files,
+		})
+	}
+	if err != nil {
+		ctx.Reply(utils.EmojiCross + " I couldn't DM you the participants. Open your DMs or use `/gparticipants`.")
+		return
+	}
+	ctx.Reply("📬 Sent you the participants in DMs.")
+}
+
+// canViewEntries reports whether a member may see a giveaway's participants
+func canViewEntries(g *models.Giveaway, userID string, permissions int64) bool {
+	return userID == g.HostID || permissions&discordgo.PermissionManageGuild != 0
+}
+
+// memberPermissions returns the invoking member's permissions. Interactions
+// carry them; the member attached to a message doesn't, so prefix commands
+// compute them for the channel.
+func memberPermissions(ctx framework.Context) int64 {
+	if _, ok := ctx.(*framework.SlashContext); ok {
+		return ctx.GetMember().Permissions
+	}
+	perms, err := ctx.GetSession().UserChannelPermissions(ctx.GetAuthor().ID, ctx.GetChannelID())
+	if err != nil {
+		return 0
+	}
+	return perms
+}
+
+// participantsPage renders one page of participants with Previous/Next buttons
+func participantsPage(service *services.GiveawayService, g *models.Giveaway, page int) (*discordgo.MessageEmbed, []discordgo.MessageComponent, error) {
+	count, err := service.DB.GetParticipantCount(g.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	totalPages := (count + participantsPerPage - 1) / participantsPerPage
+	if totalPages < 1 {
+		totalPages = 1
+	}
+	if page > totalPages {
+		page = totalPages
+	}
+	if page < 1 {
+		page = 1
+	}
+
+	offset := (page - 1) * participantsPerPage
+	participants, err := service.DB.GetParticipants(g.ID, offset, participantsPerPage)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var sb strings.Builder
+	if len(participants) == 0 {
+		sb.WriteString("Nobody has entered yet.")
+	}
+	for n, p := range participants {
+		line := fmt.Sprintf("**%d.** <@%s> • joined <t:%d:R> • %d %s", offset+n+1, p.UserID, p.JoinedAt/1000, p.Weight, pluralEntries(p.Weight))
+		if p.BoughtEntries > 0 {
+			line += fmt.Sprintf(" (%d bought)", p.BoughtEntries)
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "👥 Participants: " + g.Prize,
+		Description: sb.String(),
+		Color:       0x2f3136,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Page %d of %d • %d Participants", page, totalPages, count),
+		},
+	}
+
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Previous",
+					Style:    discordgo.SecondaryButton,
+					CustomID: fmt.Sprintf("gparticipants_%d_%d", g.ID, page-1),
+					Disabled: page <= 1,
+				},
+				discordgo.Button{
+					Label:    "Next",
+					Style:    discordgo.SecondaryButton,
+					CustomID: fmt.Sprintf("gparticipants_%d_%d", g.ID, page+1),
+					Disabled: page >= totalPages,
+				},
+			},
+		},
+	}
+	return embed, components, nil
+}
+
+// participantsCSV writes every participant, in join order, to a CSV attachment
+func participantsCSV(g *models.Giveaway, participants []models.Participant) (*discordgo.File, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"user_id", "joined_at", "weight", "bought_entries"})
+	for _, p := range participants {
+		w.Write([]string{
+			p.UserID,
+			time.UnixMilli(p.JoinedAt).UTC().Format(time.RFC3339),
+			strconv.Itoa(p.Weight),
+			strconv.Itoa(p.BoughtEntries),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	return &discordgo.File{
+		Name:        fmt.Sprintf("giveaway-%d-participants.csv", g.ID),
+		ContentType: "text/csv",
+		Reader:      &buf,
+	}, nil
+}
+
+func pluralEntries(n int) string {
+	if n == 1 {
+		return "entry"
+	}
+	return "entries"
+}
+
+func HandleGParticipants(s *discordgo.Session, i *discordgo.InteractionCreate, service *services.GiveawayService) {
+	ctx := framework.NewSlashContext(s, i)
+	GParticipantsCmd(ctx, service)
+}
+
+// HandleGParticipantsPage flips a participant list to another page (gparticipants_<giveaway id>_<page>)
+func HandleGParticipantsPage(s *discordgo.Session, i *discordgo.InteractionCreate, service *services.GiveawayService) {
+	parts := strings.Split(i.MessageComponentData().CustomID, "_")
+	if len(parts) != 3 {
+		return
+	}
+	giveawayID, _ := strconv.ParseInt(parts[1], 10, 64)
+	page, _ := strconv.Atoi(parts[2])
+
+	respondError := func(msg string) {
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: utils.EmojiCross + " " + msg,
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+	}
+
+	g, err := service.DB.GetGiveawayByID(giveawayID)
+	if err != nil || g == nil || g.GuildID != i.GuildID {
+		respondError("Giveaway not found.")
+		return
+	}
+	if i.Member == nil || !canViewEntries(g, i.Member.User.ID, i.Member.Permissions) {
+		respondError("Only the host or members with Manage Server can see the participants.")
+		return
+	}
+
+	embed, components, err := participantsPage(service, g, page)
+	if err != nil {
+		respondError("Failed to load participants.")
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: components,
+		},
+	})
+}
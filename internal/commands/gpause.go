@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"discord-giveaway-bot/internal/commands/framework"
+	"discord-giveaway-bot/internal/services"
+	"discord-giveaway-bot/internal/utils"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var GPause = &discordgo.ApplicationCommand{
+	Name:        "gpause",
+	Description: "Pause a running giveaway (stops the timer and closes entries)",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "message_id",
+			Description: "Message ID of the giveaway",
+			Required:    true,
+		},
+	},
+}
+
+var GResume = &discordgo.ApplicationCommand{
+	Name:        "gresume",
+	Description: "Resume a paused giveaway",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "message_id",
+			Description: "Message ID of the giveaway",
+			Required:    true,
+		},
+	},
+}
+
+func GPauseCmd(ctx framework.Context, service *services.GiveawayService) {
+	setGiveawayPaused(ctx, service, true)
+}
+
+func GResumeCmd(ctx framework.Context, service *services.GiveawayService) {
+	setGiveawayPaused(ctx, service, false)
+}
+
+func setGiveawayPaused(ctx framework.Context, service *services.GiveawayService, pause bool) {
+	if ctx.GetMember().Permissions&discordgo.PermissionManageGuild == 0 {
+		ctx.ReplyEphemeral(utils.EmojiCross + " You need Manage Server permissions.")
+		return
+	}
+
+	name := "gresume"
+	if pause {
+		name = "gpause"
+	}
+
+	var messageID string
+
+	if slashCtx, ok := ctx.(*framework.SlashContext); ok {
+		messageID = slashCtx.Interaction.ApplicationCommandData().Options[0].StringValue()
+	} else if prefixCtx, ok := ctx.(*framework.PrefixContext); ok {
+		if len(prefixCtx.Args) < 1 {
+			ctx.Reply(fmt.Sprintf("Usage: `!%s <message_id>`", name))
+			return
+		}
+		messageID = prefixCtx.Args[0]
+	}
+
+	g, err := service.DB.GetGiveaway(messageID)
+	if err != nil || g == nil || g.GuildID != ctx.GetGuildID() {
+		ctx.ReplyEphemeral(utils.EmojiCross + " Giveaway not found.")
+		return
+	}
+
+	if pause {
+		if err := service.PauseGiveaway(g); err != nil {
+			ctx.ReplyEphemeral(fmt.Sprintf("%s Failed to pause giveaway: %s", utils.EmojiCross, err.Error()))
+			return
+		}
+		ctx.Reply(fmt.Sprintf("⏸️ Giveaway paused with **%s** left. Entries are closed until you run /gresume.", utils.FormatRemaining(g.PausedRemaining)))
+		return
+	}
+
+	if err := service.ResumeGiveaway(g); err != nil {
+		ctx.ReplyEphemeral(fmt.Sprintf("%s Failed to resume giveaway: %s", utils.EmojiCross, err.Error()))
+		return
+	}
+	ctx.Reply(fmt.Sprintf("%s Giveaway resumed. It now ends <t:%d:R>.", utils.EmojiTick, g.EndTime/1000))
+}
+
+func HandleGPause(s *discordgo.Session, i *discordgo.InteractionCreate, service *services.GiveawayService) {
+	ctx := framework.NewSlashContext(s, i)
+	GPauseCmd(ctx, service)
+}
+
+func HandleGResume(s *discordgo.Session, i *discordgo.InteractionCreate, service *services.GiveawayService) {
+	ctx := framework.NewSlashContext(s, i)
+	GResumeCmd(ctx, service)
+}
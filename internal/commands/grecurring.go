@@ -0,0 +1,231 @@
+package commands
+
+import (
+	"discord-giveaway-bot/internal/commands/framework"
+	"discord-giveaway-bot/internal/models"
+	"discord-giveaway-bot/internal/services"
+	"discord-giveaway-bot/internal/utils"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var GRecurring = &discordgo.ApplicationCommand{
+	Name:        "grecurring",
+	Description: "Manage giveaways that repeat on a schedule",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "create",
+			Description: "Create a recurring giveaway",
+			Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionString, Name: "schedule", Description: "daily, weekly, every 12h or cron in UTC (e.g. 0 18 * * FRI)", Required: true},
+				{Type: discordgo.ApplicationCommandOptionString, Name: "template", Description: "Saved template every occurrence uses (other options override it)", Autocomplete: true},
+				{Type: discordgo.ApplicationCommandOptionString, Name: "prize", Description: "Prize of every occurrence"},
+				{Type: discordgo.ApplicationCommandOptionInteger, Name: "winners", Description: "Number of winners", MinValue: floatPtr(1)},
+				{Type: discordgo.ApplicationCommandOptionString, Name: "duration", Description: "How long each occurrence runs (e.g. 1h, 1d)"},
+				{Type: discordgo.ApplicationCommandOptionChannel, Name: "channel", Description: "Channel to post in (default: current)", ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText, discordgo.ChannelTypeGuildNews}},
+				{Type: discordgo.ApplicationCommandOptionRole, Name: "ping_role", Description: "Role to ping when each occurrence starts"},
+				{Type: discordgo.ApplicationCommandOptionInteger, Name: "max_occurrences", Description: "Stop after this many occurrences", MinValue: floatPtr(1)},
+				{Type: discordgo.ApplicationCommandOptionString, Name: "end_date", Description: "Don't start occurrences after this: YYYY-MM-DD HH:MM (UTC), timestamp or 30d"},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "entry_mode",
+					Description: "How members enter (default: reaction)",
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "Reaction", Value: models.EntryModeReaction},
+						{Name: "Buttons (Enter / Leave)", Value: models.EntryModeButton},
+					},
+				},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "list",
+			Description: "List recurring giveaways",
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "stop",
+			Description: "Stop a recurring giveaway (the running occurrence still finishes)",
+			Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionInteger, Name: "id", Description: "ID from /grecurring list", Required: true},
+			},
+		},
+	},
+}
+
+func GRecurringCmd(ctx framework.Context, service *services.GiveawayService) {
+	if ctx.GetMember().Permissions&discordgo.PermissionManageGuild == 0 {
+		ctx.ReplyEphemeral(utils.EmojiCross + " You need Manage Server permissions.")
+		return
+	}
+
+	subCommand := "list"
+	var recurringID int64
+
+	if slashCtx, ok := ctx.(*framework.SlashContext); ok {
+		sub := slashCtx.Interaction.ApplicationCommandData().Options[0]
+		subCommand = sub.Name
+		switch subCommand {
+		case "create":
+			createRecurring(slashCtx, sub.Options, service)
+			return
+		case "stop":
+			recurringID = sub.Options[0].IntValue()
+		}
+	} else if prefixCtx, ok := ctx.(*framework.PrefixContext); ok {
+		if len(prefixCtx.Args) > 0 {
+			subCommand = strings.ToLower(prefixCtx.Args[0])
+		}
+		switch subCommand {
+		case "create":
+			ctx.Reply("Use `/grecurring create` to set up a recurring giveaway.")
+			return
+		case "stop":
+			if len(prefixCtx.Args) < 2 {
+				ctx.Reply("Usage: `!grecurring stop <id>`")
+				return
+			}
+			id, err := strconv.ParseInt(prefixCtx.Args[1], 10, 64)
+			if err != nil {
+				ctx.Reply(utils.EmojiCross + " Invalid ID.")
+				return
+			}
+			recurringID = id
+		}
+	}
+
+	switch subCommand {
+	case "list":
+		recurring, err := service.DB.GetActiveRecurringGiveaways(ctx.GetGuildID())
+		if err != nil {
+			ctx.ReplyEphemeral(fmt.Sprintf("%s Failed to fetch recurring giveaways: %s", utils.EmojiCross, err.Error()))
+			return
+		}
+		if len(recurring) == 0 {
+			ctx.ReplyEphemeral("No recurring giveaways in this server.")
+			return
+		}
+
+		var sb strings.Builder
+		sb.WriteString("**🔁 Recurring Giveaways**\n\n")
+		for _, r := range recurring {
+			occurrences := strconv.Itoa(r.Occurrences)
+			if r.MaxOccurrences > 0 {
+				occurrences += "/" + strconv.Itoa(r.MaxOccurrences)
+			}
+			sb.WriteString(fmt.Sprintf("• **%s** in <#%s>\n   ID: `%d` | `%s` | Runs %s | Occurrences: %s",
+				r.Template.Prize, r.ChannelID, r.ID, r.Schedule, utils.FormatRemaining(r.Template.Duration), occurrences))
+			if next := service.NextRecurringStart(r); !next.IsZero() {
+				sb.WriteString(fmt.Sprintf(" | Next <t:%d:R>", next.Unix()))
+			}
+			if r.EndDate > 0 {
+				sb.WriteString(fmt.Sprintf(" | Until <t:%d:d>", r.EndDate/1000))
+			}
+			if r.PingRoleID != "" {
+				sb.WriteString(" | Pings " + roleOrNone(r.PingRoleID))
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\nStop one with `/grecurring stop <id>`.")
+		ctx.ReplyEphemeral(sb.String())
+
+	case "stop":
+		if err := service.StopRecurringGiveaway(ctx.GetGuildID(), recurringID); err != nil {
+			ctx.ReplyEphemeral(fmt.Sprintf("%s Failed to stop: %s", utils.EmojiCross, err.Error()))
+			return
+		}
+		ctx.ReplyEphemeral(fmt.Sprintf("%s Recurring giveaway `%d` stopped. A running occurrence will still end normally.", utils.EmojiTick, recurringID))
+
+	default:
+		ctx.Reply("Usage: `!grecurring [list|stop <id>]`")
+	}
+}
+
+func createRecurring(ctx *framework.SlashContext, options []*discordgo.ApplicationCommandInteractionDataOption, service *services.GiveawayService) {
+	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, opt := range options {
+		optionMap[opt.Name] = opt
+	}
+
+	// The series keeps its own copy of the template, so editing or deleting
+	// the saved template later doesn't change it
+	tmpl := models.GiveawayTemplate{Emoji: utils.EmojiGiveaway, EntryMode: models.EntryModeReaction}
+	if opt, ok := optionMap["template"]; ok {
+		saved, err := service.DB.GetTemplate(ctx.GetGuildID(), strings.ToLower(opt.StringValue()))
+		if err != nil || saved == nil {
+			ctx.ReplyEphemeral(fmt.Sprintf("%s No template named `%s`. See `/gtemplate list`.", utils.EmojiCross, opt.StringValue()))
+			return
+		}
+		tmpl = saved.Template
+	}
+	if opt, ok := optionMap["prize"]; ok {
+		tmpl.Prize = opt.StringValue()
+	}
+	if opt, ok := optionMap["winners"]; ok {
+		tmpl.WinnersCount = int(opt.IntValue())
+	}
+	if opt, ok := optionMap["duration"]; ok {
+		duration, err := utils.ParseDays(opt.StringValue())
+		if err != nil || duration < time.Minute {
+			ctx.ReplyEphemeral(utils.EmojiCross + " Invalid duration format. Use 10m, 1h, 1d, etc.")
+			return
+		}
+		tmpl.Duration = duration.Milliseconds()
+	}
+	if tmpl.Prize == "" || tmpl.Duration <= 0 || tmpl.WinnersCount < 1 {
+		ctx.ReplyEphemeral(utils.EmojiCross + " A prize, winners and duration are required, either directly or from a template.")
+		return
+	}
+	if err := service.CheckRewardRole(ctx.GetGuildID(), ctx.GetAuthor().ID, tmpl.Reward); err != nil {
+		ctx.ReplyEphemeral(utils.EmojiCross + " " + err.Error())
+		return
+	}
+
+	r := &models.RecurringGiveaway{
+		GuildID:   ctx.GetGuildID(),
+		ChannelID: ctx.GetChannelID(),
+		HostID:    ctx.GetAuthor().ID,
+		Schedule:  strings.TrimSpace(optionMap["schedule"].StringValue()),
+		Template:  tmpl,
+	}
+
+	if opt, ok := optionMap["channel"]; ok {
+		r.ChannelID = opt.ChannelValue(ctx.Session).ID
+	}
+	if opt, ok := optionMap["ping_role"]; ok {
+		r.PingRoleID = opt.RoleValue(ctx.Session, ctx.GetGuildID()).ID
+	}
+	if opt, ok := optionMap["max_occurrences"]; ok {
+		r.MaxOccurrences = int(opt.IntValue())
+	}
+	if opt, ok := optionMap["end_date"]; ok {
+		endDate, err := utils.ParseFutureTime(opt.StringValue(), time.Now())
+		if err != nil {
+			ctx.ReplyEphemeral(utils.EmojiCross + " Invalid end date: " + err.Error())
+			return
+		}
+		r.EndDate = endDate.UnixMilli()
+	}
+	if opt, ok := optionMap["entry_mode"]; ok {
+		r.Template.EntryMode = opt.StringValue()
+	}
+
+	first, err := service.CreateRecurringGiveaway(r)
+	if err != nil {
+		ctx.ReplyEphemeral(fmt.Sprintf("%s Failed to create recurring giveaway: %s", utils.EmojiCross, err.Error()))
+		return
+	}
+
+	ctx.ReplyEphemeral(fmt.Sprintf("%s Recurring giveaway `%d` created! The first occurrence starts in <#%s> <t:%d:R>. Manage it with `/grecurring`.",
+		utils.EmojiTick, r.ID, r.ChannelID, first.Unix()))
+}
+
+func HandleGRecurring(s *discordgo.Session, i *discordgo.InteractionCreate, service *services.GiveawayService) {
+	ctx := framework.NewSlashContext(s, i)
+	GRecurringCmd(ctx, service)
+}
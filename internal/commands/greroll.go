@@ -2,13 +2,19 @@ package commands
 
 import (
 	"discord-giveaway-bot/internal/commands/framework"
+	"discord-giveaway-bot/internal/models"
 	"discord-giveaway-bot/internal/services"
 	"discord-giveaway-bot/internal/utils"
 	"fmt"
+	"regexp"
+	"strconv"
 
 	"github.com/bwmarrin/discordgo"
 )
 
+// userIDRegex matches a user mention or a bare user ID
+var userIDRegex = regexp.MustCompile(`^(?:<@!?)?(\d{15,21})>?$`)
+
 var GReroll = &discordgo.ApplicationCommand{
 	Name:        "greroll",
 	Description: "Reroll a giveaway winner",
@@ -19,6 +25,20 @@ var GReroll = &discordgo.ApplicationCommand{
 			Description: "Message ID of the giveaway",
 			Required:    true,
 		},
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "count",
+			Description: "Number of new winners (default: 1)",
+			Required:    false,
+			MinValue:    floatPtr(1),
+			MaxValue:    20,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionUser,
+			Name:        "exclude",
+			Description: "User who must not win this reroll",
+			Required:    false,
+		},
 	},
 }
 
@@ -29,23 +49,51 @@ func GRerollCmd(ctx framework.Context, service *services.GiveawayService) {
 	}
 
 	var messageID string
+	opts := services.RerollOptions{
+		Count:      1,
+		RerolledBy: ctx.GetAuthor().ID,
+		Reason:     models.RerollReasonManual,
+	}
 
 	if slashCtx, ok := ctx.(*framework.SlashContext); ok {
-		messageID = slashCtx.Interaction.ApplicationCommandData().Options[0].StringValue()
+		for _, opt := range slashCtx.Interaction.ApplicationCommandData().Options {
+			switch opt.Name {
+			case "message_id":
+				messageID = opt.StringValue()
+			case "count":
+				opts.Count = int(opt.IntValue())
+			case "exclude":
+				opts.Exclude = opt.UserValue(nil).ID
+			}
+		}
 	} else if prefixCtx, ok := ctx.(*framework.PrefixContext); ok {
 		if len(prefixCtx.Args) < 1 {
-			ctx.Reply("Usage: `!greroll <message_id>`")
+			ctx.Reply("Usage: `!greroll <message_id> [count] [@exclude]`")
 			return
 		}
 		messageID = prefixCtx.Args[0]
+		for _, arg := range prefixCtx.Args[1:] {
+			if m := userIDRegex.FindStringSubmatch(arg); m != nil {
+				opts.Exclude = m[1]
+			} else if n, err := strconv.Atoi(arg); err == nil && n > 0 && n <= 20 {
+				opts.Count = n
+			} else {
+				ctx.Reply(fmt.Sprintf("%s `%s` isn't a count (1-20) or a member to exclude.\nUsage: `!greroll <message_id> [count] [@exclude]`", utils.EmojiCross, arg))
+				return
+			}
+		}
 	}
 
-	_, err := service.RerollGiveaway(messageID)
+	winners, err := service.RerollGiveaway(messageID, opts)
 	if err != nil {
 		ctx.ReplyEphemeral(fmt.Sprintf("%s Failed to reroll: %s", utils.EmojiCross, err.Error()))
 		return
 	}
 
+	if len(winners) < opts.Count {
+		ctx.Reply(fmt.Sprintf("%s Rerolled %d new winner(s), only %d eligible participant(s) were left.", utils.EmojiTick, len(winners), len(winners)))
+		return
+	}
 	ctx.Reply(utils.EmojiTick + " Rerolled new winner(s)!")
 }
 
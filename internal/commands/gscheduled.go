@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"discord-giveaway-bot/internal/commands/framework"
+	"discord-giveaway-bot/internal/services"
+	"discord-giveaway-bot/internal/utils"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var GScheduled = &discordgo.ApplicationCommand{
+	Name:        "gscheduled",
+	Description: "Manage giveaways scheduled to start later",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "list",
+			Description: "List scheduled giveaways",
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "cancel",
+			Description: "Cancel a scheduled giveaway",
+			Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionInteger, Name: "id", Description: "ID from /gscheduled list", Required: true},
+			},
+		},
+	},
+}
+
+func GScheduledCmd(ctx framework.Context, service *services.GiveawayService) {
+	if ctx.GetMember().Permissions&discordgo.PermissionManageGuild == 0 {
+		ctx.ReplyEphemeral(utils.EmojiCross + " You need Manage Server permissions.")
+		return
+	}
+
+	subCommand := "list"
+	var giveawayID int64
+
+	if slashCtx, ok := ctx.(*framework.SlashContext); ok {
+		options := slashCtx.Interaction.ApplicationCommandData().Options
+		subCommand = options[0].Name
+		if subCommand == "cancel" {
+			giveawayID = options[0].Options[0].IntValue()
+		}
+	} else if prefixCtx, ok := ctx.(*framework.PrefixContext); ok {
+		if len(prefixCtx.Args) > 0 {
+			subCommand = strings.ToLower(prefixCtx.Args[0])
+		}
+		if subCommand == "cancel" {
+			if len(prefixCtx.Args) < 2 {
+				ctx.Reply("Usage: `!gscheduled cancel <id>`")
+				return
+			}
+			id, err := strconv.ParseInt(prefixCtx.Args[1], 10, 64)
+			if err != nil {
+				ctx.Reply(utils.EmojiCross + " Invalid ID.")
+				return
+			}
+			giveawayID = id
+		}
+	}
+
+	switch subCommand {
+	case "list":
+		giveaways, err := service.DB.GetScheduledGiveaways(ctx.GetGuildID())
+		if err != nil {
+			ctx.ReplyEphemeral(fmt.Sprintf("%s Failed to fetch scheduled giveaways: %s", utils.EmojiCross, err.Error()))
+			return
+		}
+		if len(giveaways) == 0 {
+			ctx.ReplyEphemeral("No scheduled giveaways in this server.")
+			return
+		}
+
+		var sb strings.Builder
+		sb.WriteString("**🗓️ Scheduled Giveaways**\n\n")
+		for _, g := range giveaways {
+			duration := time.Duration(g.EndTime-g.StartTime) * time.Millisecond
+			sb.WriteString(fmt.Sprintf("• **%s** in <#%s>\n   ID: `%d` | Starts <t:%d:R> | Runs %s | Host <@%s>\n",
+				g.Prize, g.ChannelID, g.ID, g.StartTime/1000, utils.FormatRemaining(duration.Milliseconds()), g.HostID))
+		}
+		sb.WriteString("\nCancel one with `/gscheduled cancel <id>`.")
+		ctx.ReplyEphemeral(sb.String())
+
+	case "cancel":
+		if err := service.CancelScheduledGiveaway(ctx.GetGuildID(), giveawayID); err != nil {
+			ctx.ReplyEphemeral(fmt.Sprintf("%s Failed to cancel: %s", utils.EmojiCross, err.Error()))
+			return
+		}
+		ctx.ReplyEphemeral(fmt.Sprintf("%s Scheduled giveaway `%d` cancelled.", utils.EmojiTick, giveawayID))
+
+	default:
+		ctx.Reply("Usage: `!gscheduled [list|cancel <id>]`")
+	}
+}
+
+func HandleGScheduled(s *discordgo.Session, i *discordgo.InteractionCreate, service *services.GiveawayService) {
+	ctx := framework.NewSlashContext(s, i)
+	GScheduledCmd(ctx, service)
+}
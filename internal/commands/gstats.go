@@ -0,0 +1,144 @@
+package commands
+
+import (
+	"discord-giveaway-bot/internal/commands/framework"
+	"discord-giveaway-bot/internal/models"
+	"discord-giveaway-bot/internal/services"
+	"discord-giveaway-bot/internal/utils"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var GStats = &discordgo.ApplicationCommand{
+	Name:        "gstats",
+	Description: "Show this server's giveaway statistics",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "days",
+			Description: "Only count the last N days (default: all time)",
+			Required:    false,
+			MinValue:    floatPtr(1),
+		},
+	},
+}
+
+// statsTopLimit is how many hosts and winners /gstats ranks
+const statsTopLimit = 5
+
+func GStatsCmd(ctx framework.Context, service *services.GiveawayService) {
+	var days int
+
+	if slashCtx, ok := ctx.(*framework.SlashContext); ok {
+		if options := slashCtx.Interaction.ApplicationCommandData().Options; len(options) > 0 {
+			days = int(options[0].IntValue())
+		}
+	} else if prefixCtx, ok := ctx.(*framework.PrefixContext); ok {
+		if len(prefixCtx.Args) > 0 {
+			n, err := strconv.Atoi(strings.TrimSuffix(strings.ToLower(prefixCtx.Args[0]), "d"))
+			if err != nil || n < 1 {
+				ctx.Reply("Usage: `!gstats [days]`")
+				return
+			}
+			days = n
+		}
+	}
+
+	var since int64
+	period := "All time"
+	if days > 0 {
+		since = time.Now().Add(-time.Duration(days) * 24 * time.Hour).UnixMilli()
+		period = fmt.Sprintf("Last %d day(s)", days)
+	}
+
+	guildID := ctx.GetGuildID()
+	stats, err := service.DB.GetGiveawayStats(guildID, since)
+	if err != nil {
+		ctx.ReplyEphemeral(fmt.Sprintf("%s Failed to load giveaway stats: %s", utils.EmojiCross, err.Error()))
+		return
+	}
+	hosts, err := service.DB.GetTopHosts(guildID, since, statsTopLimit)
+	if err != nil {
+		ctx.ReplyEphemeral(fmt.Sprintf("%s Failed to load giveaway stats: %s", utils.EmojiCross, err.Error()))
+		return
+	}
+	winners, err := service.DB.GetTopWinners(guildID, since, statsTopLimit)
+	if err != nil {
+		ctx.ReplyEphemeral(fmt.Sprintf("%s Failed to load giveaway stats: %s", utils.EmojiCross, err.Error()))
+		return
+	}
+
+	// Cancelled giveaways never drew, so they'd drag the average down
+	var average float64
+	if drawn := stats.Giveaways - stats.Cancelled; drawn > 0 {
+		average = float64(stats.Entries) / float64(drawn)
+	}
+	var leaveRate float64
+	if stats.Joins > 0 {
+		leaveRate = float64(stats.Leaves) / float64(stats.Joins) * 100
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: "📊 Giveaway Statistics",
+		Color: 0x2f3136,
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   "Giveaways",
+				Value:  fmt.Sprintf("**%d** total\n%d active • %d cancelled", stats.Giveaways, stats.Active, stats.Cancelled),
+				Inline: true,
+			},
+			{
+				Name:   "Participation",
+				Value:  fmt.Sprintf("**%d** entrants\n%d entries • %.1f per giveaway", stats.Entrants, stats.Entries, average),
+				Inline: true,
+			},
+			{
+				Name:   "Winners",
+				Value:  fmt.Sprintf("**%d** prizes won", stats.Winners),
+				Inline: true,
+			},
+			{
+				Name:   "Fee Revenue",
+				Value:  fmt.Sprintf("**%d** coins kept\n%d coins collected", stats.FeesKept, stats.FeesCollected),
+				Inline: true,
+			},
+			{
+				Name:   "Churn",
+				Value:  fmt.Sprintf("**%d** joins • **%d** leaves\n%.1f%% left again", stats.Joins, stats.Leaves, leaveRate),
+				Inline: true,
+			},
+			{
+				Name:  "Top Hosts",
+				Value: rankingLines(hosts, "giveaway(s)"),
+			},
+			{
+				Name:  "Most Frequent Winners",
+				Value: rankingLines(winners, "win(s)"),
+			},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: period,
+		},
+	}
+	ctx.ReplyEmbed(embed)
+}
+
+func rankingLines(counts []models.StatCount, unit string) string {
+	if len(counts) == 0 {
+		return "None yet"
+	}
+	var sb strings.Builder
+	for n, c := range counts {
+		sb.WriteString(fmt.Sprintf("**%d.** <@%s> • %d %s\n", n+1, c.UserID, c.Count, unit))
+	}
+	return sb.String()
+}
+
+func HandleGStats(s *discordgo.Session, i *discordgo.InteractionCreate, service *services.GiveawayService) {
+	ctx := framework.NewSlashContext(s, i)
+	GStatsCmd(ctx, service)
+}
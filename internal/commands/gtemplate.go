@@ -0,0 +1,415 @@
+package commands
+
+import (
+	"database/sql"
+	"discord-giveaway-bot/internal/commands/framework"
+	"discord-giveaway-bot/internal/models"
+	"discord-giveaway-bot/internal/services"
+	"discord-giveaway-bot/internal/utils"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// templateFields are the /gcreate options a template can hold
+var templateFields = map[string]bool{
+	"prize": true, "winners": true, "duration": true,
+	"role_requirement": true, "invite_requirement": true, "account_age": true, "server_age": true,
+	"captcha": true, "message_required": true, "voice": true, "custom_message": true,
+	"required_fees": true, "assign_role": true, "thumbnail": true, "custom_emoji": true,
+	"entry_mode": true, "bonus_roles": true, "bonus_rules": true, "claim_window": true,
+	"requirements": true, "bypass_roles": true, "rewards": true,
+}
+
+var GTemplate = &discordgo.ApplicationCommand{
+	Name:        "gtemplate",
+	Description: "Manage saved giveaway templates",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "save",
+			Description: "Save a template (saving an existing name replaces it)",
+			Options:     templateSaveOptions(),
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "list",
+			Description: "List this server's templates",
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "delete",
+			Description: "Delete a template",
+			Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionString, Name: "name", Description: "Template name", Required: true, Autocomplete: true},
+			},
+		},
+	},
+}
+
+// templateSaveOptions reuses the /gcreate option definitions so both stay in sync
+func templateSaveOptions() []*discordgo.ApplicationCommandOption {
+	options := []*discordgo.ApplicationCommandOption{
+		{Type: discordgo.ApplicationCommandOptionString, Name: "name", Description: "Template name", Required: true, MaxLength: 32},
+	}
+	for _, opt := range GCreate.Options {
+		if templateFields[opt.Name] {
+			options = append(options, opt)
+		}
+	}
+	return options
+}
+
+func GTemplateCmd(ctx framework.Context, service *services.GiveawayService) {
+	if ctx.GetMember().Permissions&discordgo.PermissionManageGuild == 0 {
+		ctx.ReplyEphemeral(utils.EmojiCross + " You need Manage Server permissions.")
+		return
+	}
+
+	subCommand := "list"
+	var name string
+
+	if slashCtx, ok := ctx.(*framework.SlashContext); ok {
+		sub := slashCtx.Interaction.ApplicationCommandData().Options[0]
+		subCommand = sub.Name
+		switch subCommand {
+		case "save":
+			saveTemplate(slashCtx, sub.Options, service)
+			return
+		case "delete":
+			name = strings.ToLower(sub.Options[0].StringValue())
+		}
+	} else if prefixCtx, ok := ctx.(*framework.PrefixContext); ok {
+		if len(prefixCtx.Args) > 0 {
+			subCommand = strings.ToLower(prefixCtx.Args[0])
+		}
+		switch subCommand {
+		case "save":
+			ctx.Reply("Use `/gtemplate save` to create a template.")
+			return
+		case "delete":
+			if len(prefixCtx.Args) < 2 {
+				ctx.Reply("Usage: `!gtemplate delete <name>`")
+				return
+			}
+			name = strings.ToLower(prefixCtx.Args[1])
+		}
+	}
+
+	switch subCommand {
+	case "list":
+		templates, err := service.DB.GetTemplates(ctx.GetGuildID())
+		if err != nil {
+			ctx.ReplyEphemeral(fmt.Sprintf("%s Failed to fetch templates: %s", utils.EmojiCross, err.Error()))
+			return
+		}
+		if len(templates) == 0 {
+			ctx.ReplyEphemeral("No templates yet. Save one with `/gtemplate save`.")
+			return
+		}
+
+		embed := &discordgo.MessageEmbed{
+			Title: "📋 Giveaway Templates",
+			Color: 0x2f3136,
+		}
+		for _, t := range templates {
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+				Name:  t.Name,
+				Value: templateSummary(t.Template),
+			})
+		}
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: "Use /gcreate template:<name> to start one"}
+		ctx.ReplyEmbed(embed)
+
+	case "delete":
+		deleted, err := service.DB.DeleteTemplate(ctx.GetGuildID(), name)
+		if err != nil {
+			ctx.ReplyEphemeral(fmt.Sprintf("%s Failed to delete template: %s", utils.EmojiCross, err.Error()))
+			return
+		}
+		if !deleted {
+			ctx.ReplyEphemeral(fmt.Sprintf("%s No template named `%s`.", utils.EmojiCross, name))
+			return
+		}
+		ctx.ReplyEphemeral(fmt.Sprintf("%s Template `%s` deleted.", utils.EmojiTick, name))
+
+	default:
+		ctx.Reply("Usage: `!gtemplate [list|delete <name>]`")
+	}
+}
+
+func saveTemplate(ctx *framework.SlashContext, options []*discordgo.ApplicationCommandInteractionDataOption, service *services.GiveawayService) {
+	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, opt := range options {
+		optionMap[opt.Name] = opt
+	}
+
+	name := strings.ToLower(strings.TrimSpace(optionMap["name"].StringValue()))
+	if name == "" || strings.ContainsAny(name, " \n") {
+		ctx.ReplyEphemeral(utils.EmojiCross + " Template names can't contain spaces.")
+		return
+	}
+
+	tmpl := models.GiveawayTemplate{Emoji: utils.EmojiGiveaway, EntryMode: models.EntryModeReaction}
+	if err := applyTemplateOptions(&tmpl, optionMap, service, ctx.GetGuildID()); err != nil {
+		ctx.ReplyEphemeral(utils.EmojiCross + " " + err.Error())
+		return
+	}
+	if err := service.CheckRewardRole(ctx.GetGuildID(), ctx.GetAuthor().ID, tmpl.Reward); err != nil {
+		ctx.ReplyEphemeral(utils.EmojiCross + " " + err.Error())
+		return
+	}
+
+	saved := &models.SavedTemplate{
+		GuildID:   ctx.GetGuildID(),
+		Name:      name,
+		Template:  tmpl,
+		CreatedBy: ctx.GetAuthor().ID,
+		CreatedAt: models.Now(),
+	}
+	if err := service.DB.SaveTemplate(saved); err != nil {
+		ctx.ReplyEphemeral(fmt.Sprintf("%s Failed to save template: %s", utils.EmojiCross, err.Error()))
+		return
+	}
+
+	ctx.ReplyEphemeral(fmt.Sprintf("%s Template `%s` saved:\n%s\n\nStart it with `/gcreate template:%s` or `!gcreate %s`.",
+		utils.EmojiTick, name, templateSummary(tmpl), name, name))
+}
+
+// applyTemplateOptions overrides template fields with the /gcreate-style options that were given
+func applyTemplateOptions(tmpl *models.GiveawayTemplate, optionMap map[string]*discordgo.ApplicationCommandInteractionDataOption, service *services.GiveawayService, guildID string) error {
+	s := service.Session
+	for name, opt := range optionMap {
+		if !templateFields[name] {
+			continue
+		}
+
+		switch name {
+		case "prize":
+			tmpl.Prize = opt.StringValue()
+		case "winners":
+			tmpl.WinnersCount = int(opt.IntValue())
+		case "duration":
+			duration, err := utils.ParseDays(opt.StringValue())
+			if err != nil || duration <= 0 {
+				return fmt.Errorf("invalid duration format. Use 10m, 1h, 2d, etc.")
+			}
+			tmpl.Duration = duration.Milliseconds()
+		case "role_requirement":
+			tmpl.RoleRequirement = opt.RoleValue(s, guildID).ID
+		case "invite_requirement":
+			tmpl.InviteRequirement = int(opt.IntValue())
+		case "account_age":
+			tmpl.AccountAgeRequirement = int(opt.IntValue())
+		case "server_age":
+			tmpl.ServerAgeRequirement = int(opt.IntValue())
+		case "captcha":
+			tmpl.CaptchaRequirement = opt.BoolValue()
+		case "message_required":
+			tmpl.MessageRequired = int(opt.IntValue())
+		case "voice":
+			tmpl.VoiceRequirement = int(opt.IntValue())
+		case "custom_message":
+			tmpl.CustomMessage = opt.StringValue()
+		case "required_fees":
+			fee, mode, err := utils.ParseEntryFee(opt.StringValue())
+			if err != nil {
+				return err
+			}
+			tmpl.EntryFee, tmpl.FeeMode = fee, mode
+		case "assign_role":
+			tmpl.AssignRole = opt.RoleValue(s, guildID).ID
+		case "thumbnail":
+			tmpl.Thumbnail = opt.StringValue()
+		case "custom_emoji":
+			// Parse and steal emoji if needed (for custom emojis)
+			emoji, err := utils.ParseAndStealEmoji(s, guildID, opt.StringValue())
+			if err != nil {
+				log.Printf("Failed to parse/steal emoji: %v, using default", err)
+				emoji = utils.EmojiGiveaway
+			}
+			tmpl.Emoji = emoji
+		case "entry_mode":
+			tmpl.EntryMode = opt.StringValue()
+		case "bonus_roles":
+			roles, err := utils.ParseBonusRoles(opt.StringValue())
+			if err != nil {
+				return err
+			}
+			tmpl.Bonus.Roles = roles
+		case "bonus_rules":
+			if err := utils.ParseBonusRules(opt.StringValue(), &tmpl.Bonus); err != nil {
+				return err
+			}
+		case "claim_window":
+			tmpl.ClaimHours = int(opt.IntValue())
+		case "requirements":
+			group, err := utils.ParseRequirements(opt.StringValue())
+			if err != nil {
+				return fmt.Errorf("invalid requirements: %v", err)
+			}
+			tmpl.Requirements = group
+		case "bypass_roles":
+			roles, err := utils.ParseRoleList(opt.StringValue())
+			if err != nil {
+				return err
+			}
+			tmpl.BypassRoles = roles
+		case "rewards":
+			reward, err := utils.ParseRewards(opt.StringValue(), shopItemLookup(service))
+			if err != nil {
+				return fmt.Errorf("invalid rewards: %v", err)
+			}
+			tmpl.Reward = reward
+		}
+	}
+	return nil
+}
+
+// shopItemLookup finds reward items by shop item ID or exact name
+func shopItemLookup(service *services.GiveawayService) utils.ItemLookup {
+	return func(nameOrID string) (*models.ShopItem, error) {
+		var item *models.ShopItem
+		var err error
+		if id, convErr := strconv.ParseInt(nameOrID, 10, 64); convErr == nil {
+			item, err = service.DB.GetShopItemByID(id)
+		} else {
+			item, err = service.DB.GetShopItem(nameOrID)
+		}
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return item, err
+	}
+}
+
+// templateSummary describes a template in a few lines
+func templateSummary(t models.GiveawayTemplate) string {
+	prize := t.Prize
+	if prize == "" {
+		prize = "*set when starting*"
+	}
+	winners := "*set when starting*"
+	if t.WinnersCount > 0 {
+		winners = fmt.Sprint(t.WinnersCount)
+	}
+	duration := "*set when starting*"
+	if t.Duration > 0 {
+		duration = utils.FormatRemaining(t.Duration)
+	}
+
+	lines := []string{
+		fmt.Sprintf("**Prize:** %s • **Winners:** %s • **Duration:** %s", prize, winners, duration),
+	}
+	var reqs []string
+	if t.RoleRequirement != "" {
+		reqs = append(reqs, "role "+roleOrNone(t.RoleRequirement))
+	}
+	if t.InviteRequirement > 0 {
+		reqs = append(reqs, fmt.Sprintf("%d invites", t.InviteRequirement))
+	}
+	if t.AccountAgeRequirement > 0 {
+		reqs = append(reqs, fmt.Sprintf("account %dd", t.AccountAgeRequirement))
+	}
+	if t.ServerAgeRequirement > 0 {
+		reqs = append(reqs, fmt.Sprintf("member %dd", t.ServerAgeRequirement))
+	}
+	if t.MessageRequired > 0 {
+		reqs = append(reqs, fmt.Sprintf("%d messages", t.MessageRequired))
+	}
+	if t.VoiceRequirement > 0 {
+		reqs = append(reqs, fmt.Sprintf("%d voice min", t.VoiceRequirement))
+	}
+	if t.CaptchaRequirement {
+		reqs = append(reqs, "captcha")
+	}
+	if !t.Requirements.Empty() {
+		reqs = append(reqs, "`"+utils.FormatRequirements(t.Requirements)+"`")
+	}
+	if len(t.BypassRoles) > 0 {
+		reqs = append(reqs, fmt.Sprintf("%d bypass roles", len(t.BypassRoles)))
+	}
+	if t.EntryFee > 0 {
+		fee := fmt.Sprintf("%d coin fee", t.EntryFee)
+		if label := utils.FeeModeLabel(t.FeeMode); label != "" {
+			fee += " " + label
+		}
+		reqs = append(reqs, fee)
+	}
+	if len(reqs) > 0 {
+		lines = append(lines, "**Requirements:** "+strings.Join(reqs, ", "))
+	}
+
+	extras := []string{"emoji " + utils.GiveawayEmojiMention(t.Emoji)}
+	if t.EntryMode == models.EntryModeButton {
+		extras = append(extras, "button entry")
+	}
+	if t.AssignRole != "" {
+		extras = append(extras, "assigns "+roleOrNone(t.AssignRole))
+	}
+	if !t.Bonus.Empty() {
+		extras = append(extras, "bonus entries")
+	}
+	if t.ClaimHours > 0 {
+		extras = append(extras, fmt.Sprintf("%dh claim window", t.ClaimHours))
+	}
+	if !t.Reward.Empty() {
+		extras = append(extras, "rewards "+utils.RewardSummary(t.Reward, "coins"))
+	}
+	if t.CustomMessage != "" {
+		extras = append(extras, "custom message")
+	}
+	if t.Thumbnail != "" {
+		extras = append(extras, "thumbnail")
+	}
+	lines = append(lines, strings.Join(extras, " • "))
+	return strings.Join(lines, "\n")
+}
+
+// HandleTemplateAutocomplete suggests template names for /gcreate template, /grecurring create template and /gtemplate delete
+func HandleTemplateAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate, service *services.GiveawayService) {
+	data := i.ApplicationCommandData()
+	options := data.Options
+	if len(options) > 0 && options[0].Type == discordgo.ApplicationCommandOptionSubCommand {
+		options = options[0].Options
+	}
+
+	var query string
+	for _, opt := range options {
+		if opt.Focused {
+			query = strings.ToLower(opt.StringValue())
+			break
+		}
+	}
+
+	templates, err := service.DB.GetTemplates(i.GuildID)
+	if err != nil {
+		return
+	}
+
+	choices := []*discordgo.ApplicationCommandOptionChoice{}
+	for _, t := range templates {
+		if !strings.Contains(t.Name, query) {
+			continue
+		}
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: t.Name, Value: t.Name})
+		if len(choices) == 25 {
+			break
+		}
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{
+			Choices: choices,
+		},
+	})
+}
+
+func HandleGTemplate(s *discordgo.Session, i *discordgo.InteractionCreate, service *services.GiveawayService) {
+	ctx := framework.NewSlashContext(s, i)
+	GTemplateCmd(ctx, service)
+}
@@ -0,0 +1,219 @@
+package commands
+
+import (
+	"discord-giveaway-bot/internal/commands/framework"
+	"discord-giveaway-bot/internal/models"
+	"discord-giveaway-bot/internal/services"
+	"discord-giveaway-bot/internal/utils"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var GVerify = &discordgo.ApplicationCommand{
+	Name:        "gverify",
+	Description: "Verify that a giveaway's winners were drawn fairly",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "message_id",
+			Description: "Message ID of the giveaway",
+			Required:    true,
+		},
+	},
+}
+
+// Discord rejects embeds with more than 25 fields or 6000 characters; the
+// budget leaves room for the description's closing lines
+const (
+	maxVerifyFields     = 20
+	verifyEmbedBudget   = 5000
+	maxVerifyWinnersLen = 700
+)
+
+func GVerifyCmd(ctx framework.Context, service *services.GiveawayService) {
+	var messageID string
+
+	if slashCtx, ok := ctx.(*framework.SlashContext); ok {
+		messageID = slashCtx.Interaction.ApplicationCommandData().Options[0].StringValue()
+	} else if prefixCtx, ok := ctx.(*framework.PrefixContext); ok {
+		if len(prefixCtx.Args) < 1 {
+			ctx.Reply("Usage: `!gverify <message_id>`")
+			return
+		}
+		messageID = prefixCtx.Args[0]
+	}
+
+	g, err := service.DB.GetGiveaway(messageID)
+	if err != nil || g == nil || g.GuildID != ctx.GetGuildID() {
+		ctx.ReplyEphemeral(utils.EmojiCross + " Giveaway not found.")
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: "🔒 Draw Verification: " + g.Prize,
+		Color: 0x2f3136,
+	}
+
+	// The secret stays hidden until the draw, otherwise the outcome could be predicted
+	if !g.Ended {
+		commitment := "None (created before fair draws)"
+		if g.DrawCommitment != "" {
+			commitment = fmt.Sprintf("`%s`", g.DrawCommitment)
+		}
+		embed.Description = fmt.Sprintf("**Commitment:** %s\n\nThis giveaway is still running. The secret behind the commitment is revealed here once it ends.", commitment)
+		ctx.ReplyEmbed(embed)
+		return
+	}
+
+	draws, err := service.DB.GetGiveawayDraws(g.ID)
+	if err != nil {
+		ctx.ReplyEphemeral(fmt.Sprintf("%s Failed to load draws: %s", utils.EmojiCross, err.Error()))
+		return
+	}
+	if len(draws) == 0 {
+		embed.Description = "No recorded draws for this giveaway. It ended before draws were made verifiable."
+		ctx.ReplyEmbed(embed)
+		return
+	}
+
+	committed := services.CommitmentMatches(g.DrawSecret, g.DrawCommitment)
+	commitLine := utils.EmojiTick + " sha256(secret) matches the commitment published at start"
+	switch {
+	case !committed:
+		commitLine = utils.EmojiCross + " Secret does **not** match a commitment published at start"
+	case g.CommitmentAtDraw:
+		// Nothing was published before the draw, so the secret proves nothing about fairness
+		commitLine = "⚠️ No pre-published commitment: this giveaway predates commitments, so its secret was only generated at draw time. The draw is reproducible but not provably fair."
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("**Commitment:** `%s`\n**Secret:** `%s`\n%s\n", g.DrawCommitment, g.DrawSecret, commitLine))
+
+	// Every draw goes into the attached log; as many as fit Discord's embed
+	// limits also get a field and their snapshot attached
+	allValid := committed
+	var log strings.Builder
+	var files []*discordgo.File
+	var rerolls, shown int
+	budget := verifyEmbedBudget - len(embed.Title) - sb.Len()
+	for _, draw := range draws {
+		v := services.VerifyDraw(g.DrawSecret, draw)
+		allValid = allValid && v.Valid()
+
+		var label string
+		switch draw.Kind {
+		case models.DrawKindReroll:
+			rerolls++
+			label = fmt.Sprintf("Reroll #%d", rerolls)
+		case models.DrawKindReplacement:
+			label = "Replacement Draw"
+		default:
+			label = "Draw"
+		}
+		label += fmt.Sprintf(" (draw %d)", draw.DrawNumber)
+		status, result := utils.EmojiTick, "verified"
+		if !v.Valid() {
+			status, result = utils.EmojiCross, "FAILED"
+		}
+
+		log.WriteString(fmt.Sprintf("%s: %s\n", label, result))
+		if draw.Secret != "" {
+			log.WriteString(fmt.Sprintf("  reroll secret:     %s\n", draw.Secret))
+		}
+		log.WriteString(fmt.Sprintf("  participants hash: %s\n  seed:              %s\n  winners:           %s\n\n",
+			draw.ParticipantsHash, draw.Seed, strings.Join(draw.Winners, ", ")))
+
+		winners := "None"
+		if len(draw.Winners) > 0 {
+			var mentions []string
+			for _, id := range draw.Winners {
+				mentions = append(mentions, fmt.Sprintf("<@%s>", id))
+			}
+			winners = strings.Join(mentions, ", ")
+		}
+		if len(winners) > maxVerifyWinnersLen {
+			winners = winners[:strings.LastIndex(winners[:maxVerifyWinnersLen], ",")] + ", … (see draw log)"
+		}
+
+		secret := ""
+		if draw.Secret != "" {
+			secret = fmt.Sprintf("**Reroll secret:** `%s`\n", draw.Secret)
+		}
+		field := &discordgo.MessageEmbedField{
+			Name: fmt.Sprintf("%s %s", status, label),
+			Value: fmt.Sprintf("%s**Participants hash:** `%s`\n**Seed:** `%s`\n**Winners:** %s\nSnapshot %s • Seed %s • Winners %s",
+				secret, draw.ParticipantsHash, draw.Seed, winners, checkMark(v.SnapshotOK), checkMark(v.SeedOK), checkMark(v.WinnersOK)),
+		}
+		size := len(field.Name) + len(field.Value)
+		if shown == maxVerifyFields || size > budget {
+			continue
+		}
+		budget -= size
+		shown++
+		embed.Fields = append(embed.Fields, field)
+
+		// Discord allows at most 10 attachments per message, one is the draw log
+		if len(files) < 9 {
+			files = append(files, &discordgo.File{
+				Name:        fmt.Sprintf("giveaway-%d-draw-%d.txt", g.ID, draw.DrawNumber),
+				ContentType: "text/plain",
+				Reader:      strings.NewReader(draw.Snapshot),
+			})
+		}
+	}
+	files = append([]*discordgo.File{{
+		Name:        fmt.Sprintf("giveaway-%d-draws.txt", g.ID),
+		ContentType: "text/plain",
+		Reader:      strings.NewReader(log.String()),
+	}}, files...)
+
+	switch {
+	case allValid && g.CommitmentAtDraw:
+		sb.WriteString("\n" + utils.EmojiTick + " **All draws reproduce.** The winners follow from the secret and the participant list.")
+		embed.Color = 0xFFA500
+	case allValid:
+		sb.WriteString("\n" + utils.EmojiTick + " **All draws verified.** The winners follow from the committed secret and the participant list.")
+		embed.Color = 0x00FF00
+	default:
+		sb.WriteString("\n" + utils.EmojiCross + " **Verification failed.** The stored draw doesn't match its inputs.")
+		embed.Color = 0xFF0000
+	}
+	if hidden := len(draws) - shown; hidden > 0 {
+		sb.WriteString(fmt.Sprintf("\n\n%d more draw(s) are only in the attached draw log.", hidden))
+	}
+	sb.WriteString("\n\nseed = sha256(`secret:participants_hash:draw_number`), participants_hash = sha256(attached snapshot). Rerolls use their own secret, drawn fresh at reroll time.")
+	embed.Description = sb.String()
+
+	replyWithFiles(ctx, embed, files)
+}
+
+func checkMark(ok bool) string {
+	if ok {
+		return "✅"
+	}
+	return "❌"
+}
+
+func replyWithFiles(ctx framework.Context, embed *discordgo.MessageEmbed, files []*discordgo.File) {
+	if slashCtx, ok := ctx.(*framework.SlashContext); ok {
+		slashCtx.Session.InteractionRespond(slashCtx.Interaction.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Embeds: []*discordgo.MessageEmbed{embed},
+				Files:  files,
+			},
+		})
+		return
+	}
+	ctx.GetSession().ChannelMessageSendComplex(ctx.GetChannelID(), &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{embed},
+		Files:  files,
+	})
+}
+
+func HandleGVerify(s *discordgo.Session, i *discordgo.InteractionCreate, service *services.GiveawayService) {
+	ctx := framework.NewSlashContext(s, i)
+	GVerifyCmd(ctx, service)
+}
@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"discord-giveaway-bot/internal/commands/framework"
+	"discord-giveaway-bot/internal/models"
+	"discord-giveaway-bot/internal/services"
+	"discord-giveaway-bot/internal/utils"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var GWins = &discordgo.ApplicationCommand{
+	Name:        "gwins",
+	Description: "Show someone's giveaway wins",
+	Options: []*discordgo.ApplicationCommandOption{
+		{Type: discordgo.ApplicationCommandOptionUser, Name: "user", Description: "User to check (default: you)", Required: false},
+	},
+}
+
+var GLimits = &discordgo.ApplicationCommand{
+	Name:        "glimits",
+	Description: "Show or set how often members can win giveaways",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "limits",
+			Description: "e.g. 3/30d (3 wins per 30 days), cooldown:7d, or none",
+			Required:    false,
+		},
+	},
+}
+
+// winsHistoryLimit is how many recent wins /gwins lists
+const winsHistoryLimit = 10
+
+func GWinsCmd(ctx framework.Context, service *services.GiveawayService) {
+	target := ctx.GetAuthor()
+
+	if slashCtx, ok := ctx.(*framework.SlashContext); ok {
+		if options := slashCtx.Interaction.ApplicationCommandData().Options; len(options) > 0 {
+			target = options[0].UserValue(slashCtx.Session)
+		}
+	} else if prefixCtx, ok := ctx.(*framework.PrefixContext); ok {
+		if len(prefixCtx.Args) > 0 {
+			if u, err := ctx.GetSession().User(strings.Trim(prefixCtx.Args[0], "<@!>")); err == nil {
+				target = u
+			}
+		}
+	}
+
+	wins, total, err := service.DB.GetUserWins(ctx.GetGuildID(), target.ID, winsHistoryLimit)
+	if err != nil {
+		ctx.ReplyEphemeral(fmt.Sprintf("%s Failed to load wins: %s", utils.EmojiCross, err.Error()))
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("<@%s> has won **%d** giveaway(s)", target.ID, total))
+	if until, err := service.WinCooldown(ctx.GetGuildID(), target.ID); err == nil && until > 0 {
+		sb.WriteString(fmt.Sprintf("\n⏳ On win cooldown until <t:%d:f> (<t:%d:R>)", until/1000, until/1000))
+	}
+
+	if len(wins) > 0 {
+		sb.WriteString("\n\n")
+		for _, w := range wins {
+			link := fmt.Sprintf("https://discord.com/channels/%s/%s/%s", ctx.GetGuildID(), w.ChannelID, w.MessageID)
+			line := fmt.Sprintf("• [%s](%s) <t:%d:R>", w.Prize, link, w.WonAt/1000)
+			switch w.Status {
+			case models.WinnerStatusPending:
+				line += " ⏳ unclaimed"
+			case models.WinnerStatusExpired:
+				line = fmt.Sprintf("• ~~%s~~ <t:%d:R> (not claimed)", w.Prize, w.WonAt/1000)
+			}
+			sb.WriteString(line + "\n")
+		}
+	}
+
+	ctx.ReplyEmbed(&discordgo.MessageEmbed{
+		Title:       "🏆 Giveaway Wins",
+		Description: sb.String(),
+		Color:       0x2f3136,
+	})
+}
+
+func GLimitsCmd(ctx framework.Context, service *services.GiveawayService) {
+	var input string
+	var set bool
+
+	if slashCtx, ok := ctx.(*framework.SlashContext); ok {
+		if options := slashCtx.Interaction.ApplicationCommandData().Options; len(options) > 0 {
+			input, set = options[0].StringValue(), true
+		}
+	} else if prefixCtx, ok := ctx.(*framework.PrefixContext); ok {
+		if len(prefixCtx.Args) > 0 {
+			input, set = strings.Join(prefixCtx.Args, " "), true
+		}
+	}
+
+	if !set {
+		limits, err := service.DB.GetWinLimits(ctx.GetGuildID())
+		if err != nil {
+			ctx.ReplyEphemeral(fmt.Sprintf("%s Failed to load win limits: %s", utils.EmojiCross, err.Error()))
+			return
+		}
+		ctx.ReplyEphemeral(fmt.Sprintf("**Win limits:** %s\n\nChange them with `/glimits limits:3/30d cooldown:7d`, or per giveaway with `/gedit win_limits:`.",
+			utils.DescribeWinLimits(limits)))
+		return
+	}
+
+	if ctx.GetMember().Permissions&discordgo.PermissionManageGuild == 0 {
+		ctx.ReplyEphemeral(utils.EmojiCross + " You need Manage Server permissions.")
+		return
+	}
+
+	limits, err := utils.ParseWinLimits(input)
+	if err != nil {
+		ctx.ReplyEphemeral(utils.EmojiCross + " " + err.Error())
+		return
+	}
+	if err := service.DB.SetWinLimits(ctx.GetGuildID(), limits); err != nil {
+		ctx.ReplyEphemeral(fmt.Sprintf("%s Failed to save win limits: %s", utils.EmojiCross, err.Error()))
+		return
+	}
+	ctx.ReplyEphemeral(fmt.Sprintf("%s Win limits set: **%s**", utils.EmojiTick, utils.DescribeWinLimits(limits)))
+}
+
+func HandleGWins(s *discordgo.Session, i *discordgo.InteractionCreate, service *services.GiveawayService) {
+	ctx := framework.NewSlashContext(s, i)
+	GWinsCmd(ctx, service)
+}
+
+func HandleGLimits(s *discordgo.Session, i *discordgo.InteractionCreate, service *services.GiveawayService) {
+	ctx := framework.NewSlashContext(s, i)
+	GLimitsCmd(ctx, service)
+}
@@ -103,6 +103,20 @@ func HandleHelpSelect(s *discordgo.Session, i *discordgo.InteractionCreate) {
 				{Name: "/greroll", Value: "Reroll a giveaway", Inline: false},
 				{Name: "/glist", Value: "List active giveaways", Inline: false},
 				{Name: "/gcancel", Value: "Cancel a giveaway", Inline: false},
+				{Name: "/gbuy", Value: "Buy extra entries for a giveaway", Inline: false},
+				{Name: "/gverify", Value: "Verify a giveaway's draw was fair", Inline: false},
+				{Name: "/gedit", Value: "Edit a running giveaway", Inline: false},
+				{Name: "/gpause", Value: "Pause a giveaway's timer and entries", Inline: false},
+				{Name: "/gresume", Value: "Resume a paused giveaway", Inline: false},
+				{Name: "/gscheduled", Value: "List or cancel giveaways scheduled with start_at", Inline: false},
+				{Name: "/grecurring", Value: "Create, list or stop giveaways that repeat daily, weekly or on a cron schedule", Inline: false},
+				{Name: "/gtemplate", Value: "Save, list or delete giveaway templates for `/gcreate template:` and `!gcreate <template>`", Inline: false},
+				{Name: "/gwins [user]", Value: "Show someone's giveaway wins and win cooldown", Inline: false},
+				{Name: "/glimits [limits]", Value: "Show or set how often members can win, e.g. `3/30d cooldown:7d`", Inline: false},
+				{Name: "/gparticipants", Value: "List who entered a giveaway, or `export` them as CSV", Inline: false},
+				{Name: "/ginfo", Value: "Show a giveaway's requirements, fees, entries, winners and history", Inline: false},
+				{Name: "/ghistory", Value: "Browse past and running giveaways, filtered by host, status or date. Giveaways cancelled before cancellations were tracked show as ended", Inline: false},
+				{Name: "/gstats [days]", Value: "Show giveaway totals, participation, top hosts and winners, fee revenue and churn", Inline: false},
 			},
 		}
 	case "help_economy":
@@ -140,7 +154,7 @@ func HandleHelpSelect(s *discordgo.Session, i *discordgo.InteractionCreate) {
 			Fields: []*discordgo.MessageEmbedField{
 				{Name: "/help", Value: "Show this menu", Inline: false},
 				{Name: "/ping", Value: "Check bot latency", Inline: false},
-				{Name: "/invites", Value: "Check your invites", Inline: false},
+				{Name: "/invites", Value: "Check invites (`user [user]`) or the invite `leaderboard`", Inline: false},
 			},
 		}
 	}
@@ -20,6 +20,20 @@ var Commands = []*discordgo.ApplicationCommand{
 	GReroll,
 	GList,
 	GCancel,
+	GBuy,
+	GVerify,
+	GEdit,
+	GPause,
+	GResume,
+	GScheduled,
+	GRecurring,
+	GTemplate,
+	GWins,
+	GLimits,
+	GParticipants,
+	GInfo,
+	GHistory,
+	GStats,
 	// Economy Commands
 	economy.Daily,
 	economy.Weekly,
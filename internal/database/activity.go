@@ -0,0 +1,44 @@
+package database
+
+import (
+	"discord-giveaway-bot/internal/models"
+)
+
+// Activity bucket operations (see models.ActivityBucket)
+
+// AddActivity adds messages and voice minutes to a user's bucket
+func (d *Database) AddActivity(guildID, userID string, bucket int64, messages, voiceMinutes int) error {
+	_, err := d.db.Exec(`
+		INSERT INTO user_activity (guild_id, user_id, bucket, message_count, voice_minutes)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT(guild_id, user_id, bucket)
+		DO UPDATE SET message_count = user_activity.message_count + $4, voice_minutes = user_activity.voice_minutes + $5
+	`, guildID, userID, models.ActivityBucket(bucket), messages, voiceMinutes)
+	return err
+}
+
+// GetActivitySince sums a user's activity in the buckets starting at or after
+// since (ms). The bucket since falls in is left out, as most of it may
+// predate since; windows are only accurate to the hour.
+func (d *Database) GetActivitySince(guildID, userID string, since int64) (*models.UserStats, error) {
+	stats := &models.UserStats{GuildID: guildID, UserID: userID}
+	first := models.ActivityBucket(since)
+	if first < since {
+		first += models.ActivityBucketMs
+	}
+	err := d.db.QueryRow(`
+		SELECT COALESCE(SUM(message_count), 0), COALESCE(SUM(voice_minutes), 0)
+		FROM user_activity
+		WHERE guild_id = $1 AND user_id = $2 AND bucket >= $3
+	`, guildID, userID, first).Scan(&stats.MessageCount, &stats.VoiceMinutes)
+	return stats, err
+}
+
+// PruneActivity deletes buckets older than before (ms)
+func (d *Database) PruneActivity(before int64) (int64, error) {
+	res, err := d.db.Exec("DELETE FROM user_activity WHERE bucket < $1", before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
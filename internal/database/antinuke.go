@@ -13,12 +13,14 @@ import (
 
 // GetAntiNukeConfig retrieves the antinuke configuration for a guild
 func (d *Database) GetAntiNukeConfig(guildID string) (*models.AntiNukeConfig, error) {
-	config := &models.AntiNukeConfig{GuildID: guildID}
+	config := &models.AntiNukeConfig{GuildID: guildID, NotifyOwner: true}
 	err := d.db.QueryRow(`
-		SELECT enabled, logs_channel, panic_mode, created_at, updated_at 
+		SELECT enabled, logs_channel, panic_mode, created_at, updated_at,
+		       COALESCE(notify_owner, true), COALESCE(fallback_channel, ''), COALESCE(alert_webhook_url, '')
 		FROM antinuke_config 
 		WHERE guild_id = $1
-	`, guildID).Scan(&config.Enabled, &config.LogsChannel, &config.PanicMode, &config.CreatedAt, &config.UpdatedAt)
+	`, guildID).Scan(&config.Enabled, &config.LogsChannel, &config.PanicMode, &config.CreatedAt, &config.UpdatedAt,
+		&config.NotifyOwner, &config.FallbackChannel, &config.AlertWebhookURL)
 
 	if err == sql.ErrNoRows {
 		log.Printf("⚠️  [DB] No antinuke_config record for guild %s (returning disabled default)", guildID)
@@ -91,6 +93,88 @@ func (d *Database) SetPanicMode(guildID string, enabled bool) error {
 	return err
 }
 
+// SetOwnerNotifications toggles owner/extra-owner DMs for a guild
+func (d *Database) SetOwnerNotifications(guildID string, enabled bool) error {
+	now := time.Now().Unix()
+	_, err := d.db.Exec(`
+		INSERT INTO antinuke_config (guild_id, enabled, notify_owner, created_at, updated_at)
+		VALUES ($1, false, $2, $3, $4)
+		ON CONFLICT (guild_id) DO UPDATE 
+		SET notify_owner = $5, updated_at = $6
+	`, guildID, enabled, now, now, enabled, now)
+	return err
+}
+
+// SetAntiNukeFallbackChannel sets the secondary log channel (empty to clear)
+func (d *Database) SetAntiNukeFallbackChannel(guildID, channelID string) error {
+	now := time.Now().Unix()
+	_, err := d.db.Exec(`
+		INSERT INTO antinuke_config (guild_id, enabled, fallback_channel, created_at, updated_at)
+		VALUES ($1, false, $2, $3, $4)
+		ON CONFLICT (guild_id) DO UPDATE 
+		SET fallback_channel = $5, updated_at = $6
+	`, guildID, channelID, now, now, channelID, now)
+	return err
+}
+
+// SetAntiNukeAlertWebhook sets the outbound alert webhook URL (empty to clear)
+func (d *Database) SetAntiNukeAlertWebhook(guildID, url string) error {
+	now := time.Now().Unix()
+	_, err := d.db.Exec(`
+		INSERT INTO antinuke_config (guild_id, enabled, alert_webhook_url, created_at, updated_at)
+		VALUES ($1, false, $2, $3, $4)
+		ON CONFLICT (guild_id) DO UPDATE 
+		SET alert_webhook_url = $5, updated_at = $6
+	`, guildID, url, now, now, url, now)
+	return err
+}
+
+// AntiNuke Extra Owner Operations
+
+// AddExtraOwner adds a user who receives owner escalation DMs
+func (d *Database) AddExtraOwner(guildID, userID, addedBy string) error {
+	now := time.Now().Unix()
+	_, err := d.db.Exec(`
+		INSERT INTO antinuke_extra_owners (guild_id, user_id, added_by, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (guild_id, user_id) DO NOTHING
+	`, guildID, userID, addedBy, now)
+	return err
+}
+
+// RemoveExtraOwner removes a user from the extra owners list
+func (d *Database) RemoveExtraOwner(guildID, userID string) error {
+	_, err := d.db.Exec(`
+		DELETE FROM antinuke_extra_owners 
+		WHERE guild_id = $1 AND user_id = $2
+	`, guildID, userID)
+	return err
+}
+
+// GetExtraOwners retrieves the extra owners for a guild
+func (d *Database) GetExtraOwners(guildID string) ([]*models.ExtraOwner, error) {
+	rows, err := d.db.Query(`
+		SELECT id, user_id, added_by, created_at
+		FROM antinuke_extra_owners
+		WHERE guild_id = $1
+		ORDER BY created_at ASC
+	`, guildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var owners []*models.ExtraOwner
+	for rows.Next() {
+		o := &models.ExtraOwner{GuildID: guildID}
+		if err := rows.Scan(&o.ID, &o.UserID, &o.AddedBy, &o.CreatedAt); err != nil {
+			return nil, err
+		}
+		owners = append(owners, o)
+	}
+	return owners, nil
+}
+
 // AntiNuke Action Operations
 
 // GetActionConfig retrieves configuration for a specific action
@@ -156,7 +240,7 @@ func (d *Database) SetActionConfig(guildID, actionType string, limitCount, windo
 	// If action type is "all", apply to all action types
 	if actionType == models.ActionAll {
 		for _, at := range models.GetAllActionTypes() {
-			err := d.setActionConfigSingle(guildID, at, limitCount, windowSeconds, punishment, now)
+			err := setActionConfigSingle(d.db, guildID, at, limitCount, windowSeconds, punishment, now)
 			if err != nil {
 				return err
 			}
@@ -164,11 +248,17 @@ func (d *Database) SetActionConfig(guildID, actionType string, limitCount, windo
 		return nil
 	}
 
-	return d.setActionConfigSingle(guildID, actionType, limitCount, windowSeconds, punishment, now)
+	return setActionConfigSingle(d.db, guildID, actionType, limitCount, windowSeconds, punishment, now)
 }
 
-func (d *Database) setActionConfigSingle(guildID, actionType string, limitCount, windowSeconds int, punishment string, now int64) error {
-	_, err := d.db.Exec(`
+// execer is satisfied by both *sql.DB and *sql.Tx, so single-row writes can
+// be shared with ApplyAntiNukeImport's transaction
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+func setActionConfigSingle(ex execer, guildID, actionType string, limitCount, windowSeconds int, punishment string, now int64) error {
+	_, err := ex.Exec(`
 		INSERT INTO antinuke_actions 
 		(guild_id, action_type, enabled, limit_count, window_seconds, punishment, created_at, updated_at)
 		VALUES ($1, $2, true, $3, $4, $5, $6, $7)
@@ -203,8 +293,11 @@ func (d *Database) UpdateActionPunishment(guildID, actionType, punishment string
 
 // DisableAction disables a specific action
 func (d *Database) DisableAction(guildID, actionType string) error {
-	now := time.Now().Unix()
-	_, err := d.db.Exec(`
+	return disableAction(d.db, guildID, actionType, time.Now().Unix())
+}
+
+func disableAction(ex execer, guildID, actionType string, now int64) error {
+	_, err := ex.Exec(`
 		UPDATE antinuke_actions 
 		SET enabled = false, updated_at = $1 
 		WHERE guild_id = $2 AND action_type = $3
@@ -273,6 +366,144 @@ func (d *Database) GetWhitelistEntries(guildID string) ([]*models.WhitelistEntry
 	return entries, nil
 }
 
+// AntiNuke Webhook Allowlist Operations
+
+// AddWebhookAllowEntry permits webhooks owned by an application/integration
+func (d *Database) AddWebhookAllowEntry(guildID, applicationID, addedBy string) error {
+	now := time.Now().Unix()
+	_, err := d.db.Exec(`
+		INSERT INTO antinuke_webhook_allowlist (guild_id, application_id, added_by, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (guild_id, application_id) DO NOTHING
+	`, guildID, applicationID, addedBy, now)
+	return err
+}
+
+// RemoveWebhookAllowEntry removes an application/integration from the webhook allowlist
+func (d *Database) RemoveWebhookAllowEntry(guildID, applicationID string) error {
+	_, err := d.db.Exec(`
+		DELETE FROM antinuke_webhook_allowlist 
+		WHERE guild_id = $1 AND application_id = $2
+	`, guildID, applicationID)
+	return err
+}
+
+// ApplyAntiNukeImport writes a whole imported config in one transaction, so a
+// failure halfway leaves the previous setup in place
+func (d *Database) ApplyAntiNukeImport(guildID, addedBy string, imp *models.AntiNukeImport) error {
+	now := time.Now().Unix()
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if c := imp.Config; c != nil {
+		_, err = tx.Exec(`
+			INSERT INTO antinuke_config (guild_id, enabled, panic_mode, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $4)
+			ON CONFLICT (guild_id) DO UPDATE
+			SET enabled = $2, panic_mode = $3, updated_at = $4
+		`, guildID, c.Enabled, c.PanicMode, now)
+		if err != nil {
+			return err
+		}
+		if c.LogsChannel != "" {
+			_, err = tx.Exec(`
+				UPDATE antinuke_config SET logs_channel = $1 WHERE guild_id = $2
+			`, c.LogsChannel, guildID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Actions go through the same writes as SetActionConfig and DisableAction
+	for _, a := range imp.Actions {
+		if err := setActionConfigSingle(tx, guildID, a.ActionType, a.LimitCount, a.WindowSeconds, a.Punishment, now); err != nil {
+			return err
+		}
+		if !a.Enabled {
+			if err := disableAction(tx, guildID, a.ActionType, now); err != nil {
+				return err
+			}
+		}
+	}
+	for _, actionType := range imp.DisableActions {
+		if err := disableAction(tx, guildID, actionType, now); err != nil {
+			return err
+		}
+	}
+
+	for _, w := range imp.Whitelist {
+		_, err = tx.Exec(`
+			INSERT INTO antinuke_whitelist (guild_id, target_id, target_type, added_by, created_at)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (guild_id, target_id) DO NOTHING
+		`, guildID, w.TargetID, w.TargetType, addedBy, now)
+		if err != nil {
+			return err
+		}
+	}
+	if len(imp.RemoveWhitelist) > 0 {
+		_, err = tx.Exec(`
+			DELETE FROM antinuke_whitelist
+			WHERE guild_id = $1 AND target_id = ANY($2)
+		`, guildID, pq.Array(imp.RemoveWhitelist))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, id := range imp.Webhooks {
+		_, err = tx.Exec(`
+			INSERT INTO antinuke_webhook_allowlist (guild_id, application_id, added_by, created_at)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (guild_id, application_id) DO NOTHING
+		`, guildID, id, addedBy, now)
+		if err != nil {
+			return err
+		}
+	}
+	if len(imp.RemoveWebhooks) > 0 {
+		_, err = tx.Exec(`
+			DELETE FROM antinuke_webhook_allowlist
+			WHERE guild_id = $1 AND application_id = ANY($2)
+		`, guildID, pq.Array(imp.RemoveWebhooks))
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetWebhookAllowEntries retrieves the webhook allowlist for a guild
+func (d *Database) GetWebhookAllowEntries(guildID string) ([]*models.WebhookAllowEntry, error) {
+	rows, err := d.db.Query(`
+		SELECT id, application_id, added_by, created_at
+		FROM antinuke_webhook_allowlist
+		WHERE guild_id = $1
+		ORDER BY created_at DESC
+	`, guildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*models.WebhookAllowEntry
+	for rows.Next() {
+		entry := &models.WebhookAllowEntry{GuildID: guildID}
+		err := rows.Scan(&entry.ID, &entry.ApplicationID, &entry.AddedBy, &entry.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
 // AntiNuke Event Tracking Operations
 
 // TrackActionEvent records an action event for rate limiting
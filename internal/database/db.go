@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/goccy/go-json"
 	_ "github.com/lib/pq"
 )
 
@@ -55,8 +56,26 @@ CREATE TABLE IF NOT EXISTS giveaways (
 	message_required INTEGER,
     voice_requirement INTEGER,
     entry_fee INTEGER DEFAULT 0,
+    fee_mode TEXT DEFAULT 'keep',
     assign_role TEXT,
-    thumbnail TEXT
+    thumbnail TEXT,
+    emoji TEXT DEFAULT '🎉',
+    entry_mode TEXT DEFAULT 'reaction',
+    bonus_rules TEXT DEFAULT '',
+    draw_secret TEXT DEFAULT '',
+    draw_commitment TEXT DEFAULT '',
+    claim_hours INTEGER DEFAULT 0,
+    paused_at BIGINT DEFAULT 0,
+    paused_remaining BIGINT DEFAULT 0,
+    scheduled INTEGER DEFAULT 0,
+    start_time BIGINT DEFAULT 0,
+    recurring_id INTEGER DEFAULT 0,
+    requirements TEXT DEFAULT '',
+    bypass_roles TEXT DEFAULT '',
+    reward TEXT DEFAULT '',
+    win_limits TEXT DEFAULT '',
+    cancelled INTEGER DEFAULT 0,
+    commitment_at_draw INTEGER DEFAULT 0
 );
 
 -- Captcha sessions table
@@ -76,6 +95,8 @@ CREATE TABLE IF NOT EXISTS participants (
     giveaway_id INTEGER NOT NULL,
     user_id TEXT NOT NULL,
     joined_at BIGINT NOT NULL,
+    weight INTEGER DEFAULT 1,
+    bought_entries INTEGER DEFAULT 0,
     FOREIGN KEY (giveaway_id) REFERENCES giveaways(id) ON DELETE CASCADE,
     UNIQUE(giveaway_id, user_id)
 );
@@ -86,9 +107,89 @@ CREATE TABLE IF NOT EXISTS winners (
     giveaway_id INTEGER NOT NULL,
     user_id TEXT NOT NULL,
     won_at BIGINT NOT NULL,
+    status TEXT DEFAULT 'won',
+    claim_deadline BIGINT DEFAULT 0,
+    claimed_at BIGINT DEFAULT 0,
     FOREIGN KEY (giveaway_id) REFERENCES giveaways(id) ON DELETE CASCADE
 );
 
+-- Provably fair draw records (one per end/reroll)
+CREATE TABLE IF NOT EXISTS giveaway_draws (
+    giveaway_id INTEGER NOT NULL,
+    draw_number INTEGER NOT NULL,
+    seed TEXT NOT NULL,
+    participants_hash TEXT NOT NULL,
+    snapshot TEXT NOT NULL,
+    winner_count INTEGER NOT NULL,
+    winners TEXT NOT NULL,
+    created_at BIGINT NOT NULL,
+    secret TEXT DEFAULT '',
+    kind TEXT DEFAULT '',
+    PRIMARY KEY (giveaway_id, draw_number),
+    FOREIGN KEY (giveaway_id) REFERENCES giveaways(id) ON DELETE CASCADE
+);
+
+-- Edit history
+CREATE TABLE IF NOT EXISTS giveaway_edits (
+    id SERIAL PRIMARY KEY,
+    giveaway_id INTEGER NOT NULL,
+    editor_id TEXT NOT NULL,
+    changes TEXT NOT NULL,
+    created_at BIGINT NOT NULL,
+    FOREIGN KEY (giveaway_id) REFERENCES giveaways(id) ON DELETE CASCADE
+);
+
+-- Reroll history
+CREATE TABLE IF NOT EXISTS giveaway_rerolls (
+    id SERIAL PRIMARY KEY,
+    giveaway_id INTEGER NOT NULL,
+    rerolled_by TEXT DEFAULT '',
+    reason TEXT NOT NULL,
+    count INTEGER NOT NULL,
+    excluded_user TEXT DEFAULT '',
+    winners TEXT NOT NULL,
+    created_at BIGINT NOT NULL,
+    FOREIGN KEY (giveaway_id) REFERENCES giveaways(id) ON DELETE CASCADE
+);
+
+-- Drawn winners replaced at draw time
+CREATE TABLE IF NOT EXISTS giveaway_disqualifications (
+    id SERIAL PRIMARY KEY,
+    giveaway_id INTEGER NOT NULL,
+    user_id TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    created_at BIGINT NOT NULL,
+    FOREIGN KEY (giveaway_id) REFERENCES giveaways(id) ON DELETE CASCADE
+);
+
+-- Recurring giveaway definitions
+CREATE TABLE IF NOT EXISTS recurring_giveaways (
+    id SERIAL PRIMARY KEY,
+    guild_id TEXT NOT NULL,
+    channel_id TEXT NOT NULL,
+    host_id TEXT NOT NULL,
+    schedule TEXT NOT NULL,
+    template TEXT NOT NULL,
+    ping_role_id TEXT DEFAULT '',
+    max_occurrences INTEGER DEFAULT 0,
+    end_date BIGINT DEFAULT 0,
+    occurrences INTEGER DEFAULT 0,
+    anchor BIGINT NOT NULL,
+    active INTEGER DEFAULT 1,
+    created_at BIGINT NOT NULL
+);
+
+-- Saved giveaway templates
+CREATE TABLE IF NOT EXISTS giveaway_templates (
+    id SERIAL PRIMARY KEY,
+    guild_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    template TEXT NOT NULL,
+    created_by TEXT NOT NULL,
+    created_at BIGINT NOT NULL,
+    UNIQUE(guild_id, name)
+);
+
 -- Refund tracking table
 CREATE TABLE IF NOT EXISTS giveaway_refunds (
     giveaway_id INTEGER NOT NULL,
@@ -108,13 +209,72 @@ CREATE TABLE IF NOT EXISTS user_stats (
     UNIQUE(guild_id, user_id)
 );
 
+-- Entry fee escrow ledger (deposits positive, refunds/kept/payouts negative)
+CREATE TABLE IF NOT EXISTS giveaway_escrow (
+    id SERIAL PRIMARY KEY,
+    giveaway_id INTEGER NOT NULL,
+    user_id TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    amount BIGINT NOT NULL,
+    created_at BIGINT NOT NULL
+);
+
+-- Per-winner prize deliveries (coins, shop items, roles)
+CREATE TABLE IF NOT EXISTS prize_deliveries (
+    id SERIAL PRIMARY KEY,
+    giveaway_id INTEGER NOT NULL,
+    user_id TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    status TEXT NOT NULL,
+    detail TEXT DEFAULT '',
+    expires_at BIGINT DEFAULT 0,
+    created_at BIGINT NOT NULL,
+    already_held BOOLEAN DEFAULT FALSE
+);
+
+-- Entries and leaves, kept after participants leave (for churn stats)
+CREATE TABLE IF NOT EXISTS participant_events (
+    id SERIAL PRIMARY KEY,
+    giveaway_id INTEGER NOT NULL,
+    guild_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    created_at BIGINT NOT NULL
+);
+
+-- Hourly activity buckets (for windowed activity requirements)
+CREATE TABLE IF NOT EXISTS user_activity (
+    guild_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    bucket BIGINT NOT NULL,
+    message_count INTEGER DEFAULT 0,
+    voice_minutes INTEGER DEFAULT 0,
+    PRIMARY KEY (guild_id, user_id, bucket)
+);
+
 -- Create indexes for better performance
 CREATE INDEX IF NOT EXISTS idx_giveaways_guild ON giveaways(guild_id);
 CREATE INDEX IF NOT EXISTS idx_giveaways_ended ON giveaways(ended);
+CREATE INDEX IF NOT EXISTS idx_giveaways_guild_end ON giveaways(guild_id, end_time);
+CREATE INDEX IF NOT EXISTS idx_giveaways_guild_host ON giveaways(guild_id, host_id);
+CREATE INDEX IF NOT EXISTS idx_giveaways_guild_created ON giveaways(guild_id, created_at);
 CREATE INDEX IF NOT EXISTS idx_participants_giveaway ON participants(giveaway_id);
 CREATE INDEX IF NOT EXISTS idx_participants_user ON participants(user_id);
+CREATE INDEX IF NOT EXISTS idx_participants_joined ON participants(giveaway_id, joined_at);
 CREATE INDEX IF NOT EXISTS idx_winners_giveaway ON winners(giveaway_id);
+CREATE INDEX IF NOT EXISTS idx_winners_pending ON winners(status, claim_deadline);
+CREATE INDEX IF NOT EXISTS idx_winners_user ON winners(user_id, won_at);
+CREATE INDEX IF NOT EXISTS idx_winners_giveaway_won ON winners(giveaway_id, won_at);
+CREATE INDEX IF NOT EXISTS idx_giveaway_rerolls_giveaway ON giveaway_rerolls(giveaway_id);
+CREATE INDEX IF NOT EXISTS idx_giveaway_edits_giveaway ON giveaway_edits(giveaway_id);
+CREATE INDEX IF NOT EXISTS idx_giveaway_disqualifications_giveaway ON giveaway_disqualifications(giveaway_id);
+CREATE INDEX IF NOT EXISTS idx_giveaway_escrow_giveaway ON giveaway_escrow(giveaway_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_prize_deliveries_giveaway ON prize_deliveries(giveaway_id);
+CREATE INDEX IF NOT EXISTS idx_prize_deliveries_expiry ON prize_deliveries(status, expires_at);
+CREATE INDEX IF NOT EXISTS idx_participant_events_guild ON participant_events(guild_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_recurring_giveaways_guild ON recurring_giveaways(guild_id, active);
 CREATE INDEX IF NOT EXISTS idx_user_stats_guild_user ON user_stats(guild_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_user_activity_bucket ON user_activity(bucket);
 CREATE INDEX IF NOT EXISTS idx_captcha_sessions_user_giveaway ON captcha_sessions(user_id, giveaway_id);
 
 -- Economy Users table
@@ -146,13 +306,32 @@ CREATE TABLE IF NOT EXISTS economy_config (
     gamble_enabled INTEGER DEFAULT 0,
     max_gamble_amount INTEGER DEFAULT 20000,
     allowed_channels TEXT DEFAULT '',
-    currency_emoji TEXT DEFAULT '<:Cash:1443554334670327848>'
+    currency_emoji TEXT DEFAULT '<:Cash:1443554334670327848>',
+    invite_fake_days INTEGER DEFAULT 7
+);
+
+-- Member joins attributed to invites
+CREATE TABLE IF NOT EXISTS invite_joins (
+    id SERIAL PRIMARY KEY,
+    guild_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    inviter_id TEXT DEFAULT '',
+    code TEXT DEFAULT '',
+    fake INTEGER DEFAULT 0,
+    rejoin INTEGER DEFAULT 0,
+    left_at BIGINT DEFAULT 0,
+    joined_at BIGINT NOT NULL
 );
+CREATE INDEX IF NOT EXISTS idx_invite_joins_inviter ON invite_joins(guild_id, inviter_id);
+CREATE INDEX IF NOT EXISTS idx_invite_joins_user ON invite_joins(guild_id, user_id);
 
 -- Guild Settings table
 CREATE TABLE IF NOT EXISTS guild_settings (
     guild_id TEXT PRIMARY KEY,
-    prefix TEXT DEFAULT '!'
+    prefix TEXT DEFAULT '!',
+    win_max INTEGER DEFAULT 0,
+    win_period BIGINT DEFAULT 0,
+    win_cooldown BIGINT DEFAULT 0
 );
 
 -- Auto Drag Rules table
@@ -253,6 +432,26 @@ CREATE TABLE IF NOT EXISTS antinuke_whitelist (
     UNIQUE(guild_id, target_id)
 );
 
+-- AntiNuke Webhook Allowlist table (integrations whose webhooks are always permitted)
+CREATE TABLE IF NOT EXISTS antinuke_webhook_allowlist (
+    id SERIAL PRIMARY KEY,
+    guild_id TEXT NOT NULL,
+    application_id TEXT NOT NULL,
+    added_by TEXT NOT NULL,
+    created_at BIGINT NOT NULL,
+    UNIQUE(guild_id, application_id)
+);
+
+-- AntiNuke Extra Owners table (users DM'd alongside the guild owner on every punishment)
+CREATE TABLE IF NOT EXISTS antinuke_extra_owners (
+    id SERIAL PRIMARY KEY,
+    guild_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    added_by TEXT NOT NULL,
+    created_at BIGINT NOT NULL,
+    UNIQUE(guild_id, user_id)
+);
+
 -- AntiNuke Events table (for rate limiting tracking)
 CREATE TABLE IF NOT EXISTS antinuke_events (
     id SERIAL PRIMARY KEY,
@@ -270,6 +469,8 @@ CREATE INDEX IF NOT EXISTS idx_antinuke_actions_guild ON antinuke_actions(guild_
 CREATE INDEX IF NOT EXISTS idx_antinuke_actions_guild_action ON antinuke_actions(guild_id, action_type);
 CREATE INDEX IF NOT EXISTS idx_antinuke_whitelist_guild ON antinuke_whitelist(guild_id);
 CREATE INDEX IF NOT EXISTS idx_antinuke_whitelist_target ON antinuke_whitelist(guild_id, target_id);
+CREATE INDEX IF NOT EXISTS idx_antinuke_webhook_allowlist_guild ON antinuke_webhook_allowlist(guild_id);
+CREATE INDEX IF NOT EXISTS idx_antinuke_extra_owners_guild ON antinuke_extra_owners(guild_id);
 CREATE INDEX IF NOT EXISTS idx_antinuke_events_guild_action ON antinuke_events(guild_id, action_type);
 CREATE INDEX IF NOT EXISTS idx_antinuke_events_timestamp ON antinuke_events(timestamp);
 CREATE INDEX IF NOT EXISTS idx_antinuke_events_guild_executor_time ON antinuke_events(guild_id, executor_id, action_type, timestamp);
@@ -307,6 +508,47 @@ func NewDatabase(cfg PostgresConfig) (*Database, error) {
 
 	// Migrations
 	_, _ = db.Exec("ALTER TABLE economy_config ADD COLUMN IF NOT EXISTS currency_emoji TEXT DEFAULT '<:Cash:1443554334670327848>'")
+	_, _ = db.Exec("ALTER TABLE economy_config ADD COLUMN IF NOT EXISTS invite_fake_days INTEGER DEFAULT 7")
+	_, _ = db.Exec("ALTER TABLE giveaways ADD COLUMN IF NOT EXISTS emoji TEXT DEFAULT '🎉'")
+	_, _ = db.Exec("ALTER TABLE giveaways ADD COLUMN IF NOT EXISTS entry_mode TEXT DEFAULT 'reaction'")
+	_, _ = db.Exec("ALTER TABLE giveaways ADD COLUMN IF NOT EXISTS bonus_rules TEXT DEFAULT ''")
+	_, _ = db.Exec("ALTER TABLE giveaways ADD COLUMN IF NOT EXISTS draw_secret TEXT DEFAULT ''")
+	_, _ = db.Exec("ALTER TABLE giveaways ADD COLUMN IF NOT EXISTS draw_commitment TEXT DEFAULT ''")
+	_, _ = db.Exec("ALTER TABLE giveaways ADD COLUMN IF NOT EXISTS claim_hours INTEGER DEFAULT 0")
+	_, _ = db.Exec("ALTER TABLE giveaways ADD COLUMN IF NOT EXISTS paused_at BIGINT DEFAULT 0")
+	_, _ = db.Exec("ALTER TABLE giveaways ADD COLUMN IF NOT EXISTS paused_remaining BIGINT DEFAULT 0")
+	_, _ = db.Exec("ALTER TABLE giveaways ADD COLUMN IF NOT EXISTS scheduled INTEGER DEFAULT 0")
+	_, _ = db.Exec("ALTER TABLE giveaways ADD COLUMN IF NOT EXISTS start_time BIGINT DEFAULT 0")
+	_, _ = db.Exec("ALTER TABLE giveaways ADD COLUMN IF NOT EXISTS recurring_id INTEGER DEFAULT 0")
+	_, _ = db.Exec("ALTER TABLE giveaways ADD COLUMN IF NOT EXISTS requirements TEXT DEFAULT ''")
+	_, _ = db.Exec("ALTER TABLE giveaways ADD COLUMN IF NOT EXISTS bypass_roles TEXT DEFAULT ''")
+	_, _ = db.Exec("ALTER TABLE giveaways ADD COLUMN IF NOT EXISTS reward TEXT DEFAULT ''")
+	_, _ = db.Exec("ALTER TABLE giveaways ADD COLUMN IF NOT EXISTS fee_mode TEXT DEFAULT 'keep'")
+	_, _ = db.Exec("ALTER TABLE giveaways ADD COLUMN IF NOT EXISTS win_limits TEXT DEFAULT ''")
+	_, _ = db.Exec("ALTER TABLE giveaways ADD COLUMN IF NOT EXISTS cancelled INTEGER DEFAULT 0")
+	_, _ = db.Exec("ALTER TABLE giveaways ADD COLUMN IF NOT EXISTS commitment_at_draw INTEGER DEFAULT 0")
+	_, _ = db.Exec("ALTER TABLE guild_settings ADD COLUMN IF NOT EXISTS win_max INTEGER DEFAULT 0")
+	_, _ = db.Exec("ALTER TABLE guild_settings ADD COLUMN IF NOT EXISTS win_period BIGINT DEFAULT 0")
+	_, _ = db.Exec("ALTER TABLE guild_settings ADD COLUMN IF NOT EXISTS win_cooldown BIGINT DEFAULT 0")
+	_, _ = db.Exec("ALTER TABLE giveaway_draws ADD COLUMN IF NOT EXISTS secret TEXT DEFAULT ''")
+	_, _ = db.Exec("ALTER TABLE giveaway_draws ADD COLUMN IF NOT EXISTS kind TEXT DEFAULT ''")
+	_, _ = db.Exec("ALTER TABLE prize_deliveries ADD COLUMN IF NOT EXISTS already_held BOOLEAN DEFAULT FALSE")
+	_, _ = db.Exec("ALTER TABLE winners ADD COLUMN IF NOT EXISTS status TEXT DEFAULT 'won'")
+	_, _ = db.Exec("ALTER TABLE winners ADD COLUMN IF NOT EXISTS claim_deadline BIGINT DEFAULT 0")
+	_, _ = db.Exec("ALTER TABLE winners ADD COLUMN IF NOT EXISTS claimed_at BIGINT DEFAULT 0")
+	_, _ = db.Exec("CREATE INDEX IF NOT EXISTS idx_winners_pending ON winners(status, claim_deadline)")
+	_, _ = db.Exec("CREATE INDEX IF NOT EXISTS idx_winners_user ON winners(user_id, won_at)")
+	_, _ = db.Exec("CREATE INDEX IF NOT EXISTS idx_participants_joined ON participants(giveaway_id, joined_at)")
+	_, _ = db.Exec("CREATE INDEX IF NOT EXISTS idx_giveaways_guild_end ON giveaways(guild_id, end_time)")
+	_, _ = db.Exec("CREATE INDEX IF NOT EXISTS idx_giveaways_guild_host ON giveaways(guild_id, host_id)")
+	_, _ = db.Exec("CREATE INDEX IF NOT EXISTS idx_giveaways_guild_created ON giveaways(guild_id, created_at)")
+	_, _ = db.Exec("CREATE INDEX IF NOT EXISTS idx_winners_giveaway_won ON winners(giveaway_id, won_at)")
+	_, _ = db.Exec("ALTER TABLE participants ADD COLUMN IF NOT EXISTS weight INTEGER DEFAULT 1")
+	_, _ = db.Exec("ALTER TABLE participants ADD COLUMN IF NOT EXISTS bought_entries INTEGER DEFAULT 0")
+	_, _ = db.Exec("ALTER TABLE antinuke_config ADD COLUMN IF NOT EXISTS panic_mode BOOLEAN DEFAULT FALSE")
+	_, _ = db.Exec("ALTER TABLE antinuke_config ADD COLUMN IF NOT EXISTS notify_owner BOOLEAN DEFAULT TRUE")
+	_, _ = db.Exec("ALTER TABLE antinuke_config ADD COLUMN IF NOT EXISTS fallback_channel TEXT DEFAULT ''")
+	_, _ = db.Exec("ALTER TABLE antinuke_config ADD COLUMN IF NOT EXISTS alert_webhook_url TEXT DEFAULT ''")
 
 	// Prepare the ping statement for ultra-low latency
 	pingStmt, err := db.Prepare("SELECT 1")
@@ -344,6 +586,11 @@ func (d *Database) Close() error {
 	return d.db.Close()
 }
 
+// Stats returns connection pool statistics
+func (d *Database) Stats() sql.DBStats {
+	return d.db.Stats()
+}
+
 func (d *Database) Ping() error {
 	// Use prepared statement for fastest possible ping
 	var err error
@@ -358,24 +605,112 @@ func (d *Database) Ping() error {
 
 // Giveaway operations
 
+func encodeBonusRules(b models.BonusRules) (string, error) {
+	if b.Empty() {
+		return "", nil
+	}
+	data, err := json.Marshal(b)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// encodeRequirements stores the requirement groups and bypass roles as JSON, empty when unset
+func encodeRequirements(g *models.Giveaway) (string, string, error) {
+	var requirements, bypassRoles string
+	if !g.Requirements.Empty() {
+		data, err := json.Marshal(g.Requirements)
+		if err != nil {
+			return "", "", err
+		}
+		requirements = string(data)
+	}
+	if len(g.BypassRoles) > 0 {
+		data, err := json.Marshal(g.BypassRoles)
+		if err != nil {
+			return "", "", err
+		}
+		bypassRoles = string(data)
+	}
+	return requirements, bypassRoles, nil
+}
+
+// encodeReward stores the structured prize as JSON, empty when there's nothing to deliver
+func encodeReward(r *models.Reward) (string, error) {
+	if r.Empty() {
+		return "", nil
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// encodeWinLimits stores a win limit override as JSON. An override with no
+// limits is still stored ("{}"), it's different from using the guild's limits.
+func encodeWinLimits(l *models.WinLimits) (string, error) {
+	if l == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// giveawayColumns is the column list every giveaway SELECT uses; keep in sync with scanGiveawayFrom
+const giveawayColumns = `
+			id, message_id, channel_id, guild_id, host_id, prize, winners_count,
+			end_time, ended, created_at, custom_message,
+			role_requirement, invite_requirement, account_age_requirement, server_age_requirement, 
+			captcha_requirement, message_required, voice_requirement, entry_fee, assign_role, thumbnail,
+			emoji, entry_mode, bonus_rules, draw_secret, draw_commitment, claim_hours,
+			paused_at, paused_remaining, scheduled, start_time, recurring_id, requirements, bypass_roles,
+			reward, fee_mode, win_limits, commitment_at_draw`
+
 func (d *Database) CreateGiveaway(g *models.Giveaway) (int64, error) {
 	query := `
 		INSERT INTO giveaways (
 			message_id, channel_id, guild_id, host_id, prize, winners_count,
 			end_time, created_at, custom_message, role_requirement, invite_requirement,
 			account_age_requirement, server_age_requirement, captcha_requirement,
-			message_required, voice_requirement, entry_fee, assign_role, thumbnail
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+			message_required, voice_requirement, entry_fee, assign_role, thumbnail, emoji, entry_mode,
+			bonus_rules, draw_secret, draw_commitment, claim_hours, scheduled, start_time, recurring_id,
+			requirements, bypass_roles, reward, fee_mode, win_limits
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33)
 		RETURNING id
 	`
 
+	bonusRules, err := encodeBonusRules(g.Bonus)
+	if err != nil {
+		return 0, err
+	}
+	requirements, bypassRoles, err := encodeRequirements(g)
+	if err != nil {
+		return 0, err
+	}
+	reward, err := encodeReward(g.Reward)
+	if err != nil {
+		return 0, err
+	}
+	winLimits, err := encodeWinLimits(g.WinLimits)
+	if err != nil {
+		return 0, err
+	}
+
 	var id int64
-	err := d.db.QueryRow(query,
+	err = d.db.QueryRow(query,
 		g.MessageID, g.ChannelID, g.GuildID, g.HostID, g.Prize, g.WinnersCount,
 		g.EndTime, models.Now(), g.CustomMessage,
 		g.RoleRequirement, g.InviteRequirement, g.AccountAgeRequirement, g.ServerAgeRequirement,
 		models.BoolToInt(g.CaptchaRequirement), g.MessageRequired, g.VoiceRequirement, g.EntryFee,
-		g.AssignRole, g.Thumbnail,
+		g.AssignRole, g.Thumbnail, g.Emoji, g.EntryMode,
+		bonusRules, g.DrawSecret, g.DrawCommitment, g.ClaimHours,
+		models.BoolToInt(g.Scheduled), g.StartTime, g.RecurringID,
+		requirements, bypassRoles, reward, g.FeeMode, winLimits,
 	).Scan(&id)
 
 	if err != nil {
@@ -386,11 +721,7 @@ func (d *Database) CreateGiveaway(g *models.Giveaway) (int64, error) {
 
 func (d *Database) GetGiveaway(messageID string) (*models.Giveaway, error) {
 	query := `
-		SELECT 
-			id, message_id, channel_id, guild_id, host_id, prize, winners_count,
-			end_time, ended, created_at, custom_message,
-			role_requirement, invite_requirement, account_age_requirement, server_age_requirement, 
-			captcha_requirement, message_required, voice_requirement, entry_fee, assign_role, thumbnail
+		SELECT ` + giveawayColumns + `
 		FROM giveaways WHERE message_id = $1
 	`
 	return d.scanGiveaway(d.db.QueryRow(query, messageID))
@@ -398,11 +729,7 @@ func (d *Database) GetGiveaway(messageID string) (*models.Giveaway, error) {
 
 func (d *Database) GetGiveawayByID(id int64) (*models.Giveaway, error) {
 	query := `
-		SELECT 
-			id, message_id, channel_id, guild_id, host_id, prize, winners_count,
-			end_time, ended, created_at, custom_message,
-			role_requirement, invite_requirement, account_age_requirement, server_age_requirement, 
-			captcha_requirement, message_required, voice_requirement, entry_fee, assign_role, thumbnail
+		SELECT ` + giveawayColumns + `
 		FROM giveaways WHERE id = $1
 	`
 	return d.scanGiveaway(d.db.QueryRow(query, id))
@@ -410,12 +737,8 @@ func (d *Database) GetGiveawayByID(id int64) (*models.Giveaway, error) {
 
 func (d *Database) GetActiveGiveaways(guildID string) ([]*models.Giveaway, error) {
 	query := `
-		SELECT 
-			id, message_id, channel_id, guild_id, host_id, prize, winners_count,
-			end_time, ended, created_at, custom_message,
-			role_requirement, invite_requirement, account_age_requirement, server_age_requirement, 
-			captcha_requirement, message_required, voice_requirement, entry_fee, assign_role, thumbnail
-		FROM giveaways WHERE guild_id = $1 AND ended = 0 ORDER BY end_time ASC
+		SELECT ` + giveawayColumns + `
+		FROM giveaways WHERE guild_id = $1 AND ended = 0 AND COALESCE(scheduled, 0) = 0 ORDER BY end_time ASC
 	`
 	rows, err := d.db.Query(query, guildID)
 	if err != nil {
@@ -436,12 +759,8 @@ func (d *Database) GetActiveGiveaways(guildID string) ([]*models.Giveaway, error
 
 func (d *Database) GetAllActiveGiveaways() ([]*models.Giveaway, error) {
 	query := `
-		SELECT 
-			id, message_id, channel_id, guild_id, host_id, prize, winners_count,
-			end_time, ended, created_at, custom_message,
-			role_requirement, invite_requirement, account_age_requirement, server_age_requirement, 
-			captcha_requirement, message_required, voice_requirement, entry_fee, assign_role, thumbnail
-		FROM giveaways WHERE ended = 0
+		SELECT ` + giveawayColumns + `
+		FROM giveaways WHERE ended = 0 AND COALESCE(scheduled, 0) = 0
 	`
 	rows, err := d.db.Query(query)
 	if err != nil {
@@ -460,16 +779,137 @@ func (d *Database) GetAllActiveGiveaways() ([]*models.Giveaway, error) {
 	return giveaways, nil
 }
 
+// GetScheduledGiveaways returns giveaways waiting to be posted, soonest first (all guilds when guildID is empty)
+func (d *Database) GetScheduledGiveaways(guildID string) ([]*models.Giveaway, error) {
+	query := `
+		SELECT ` + giveawayColumns + `
+		FROM giveaways WHERE scheduled = 1 AND ($1 = '' OR guild_id = $1) ORDER BY start_time ASC
+	`
+	rows, err := d.db.Query(query, guildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var giveaways []*models.Giveaway
+	for rows.Next() {
+		g, err := d.scanGiveawayRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		giveaways = append(giveaways, g)
+	}
+	return giveaways, nil
+}
+
+// StartScheduledGiveaway attaches the posted message to a scheduled giveaway and makes it live
+func (d *Database) StartScheduledGiveaway(id int64, messageID string, endTime int64) (bool, error) {
+	res, err := d.db.Exec("UPDATE giveaways SET message_id = $1, end_time = $2, scheduled = 0 WHERE id = $3 AND scheduled = 1",
+		messageID, endTime, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// DeleteScheduledGiveaway removes a giveaway that hasn't been posted yet
+func (d *Database) DeleteScheduledGiveaway(id int64, guildID string) (bool, error) {
+	res, err := d.db.Exec("DELETE FROM giveaways WHERE id = $1 AND guild_id = $2 AND scheduled = 1", id, guildID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
 func (d *Database) UpdateGiveawayMessageID(tempID, newID string) error {
 	_, err := d.db.Exec("UPDATE giveaways SET message_id = $1 WHERE message_id = $2", newID, tempID)
 	return err
 }
 
-func (d *Database) EndGiveaway(messageID string) error {
-	_, err := d.db.Exec("UPDATE giveaways SET ended = 1 WHERE message_id = $1", messageID)
+// UpdateGiveaway saves the editable fields of a running giveaway, reporting
+// false if it had already ended
+func (d *Database) UpdateGiveaway(g *models.Giveaway) (bool, error) {
+	requirements, bypassRoles, err := encodeRequirements(g)
+	if err != nil {
+		return false, err
+	}
+	winLimits, err := encodeWinLimits(g.WinLimits)
+	if err != nil {
+		return false, err
+	}
+	res, err := d.db.Exec(`
+		UPDATE giveaways SET
+			prize = $1, winners_count = $2, end_time = $3, custom_message = $4, thumbnail = $5,
+			role_requirement = $6, invite_requirement = $7, account_age_requirement = $8,
+			server_age_requirement = $9, captcha_requirement = $10, message_required = $11, voice_requirement = $12,
+			requirements = $13, bypass_roles = $14, win_limits = $15
+		WHERE id = $16 AND ended = 0
+	`, g.Prize, g.WinnersCount, g.EndTime, g.CustomMessage, g.Thumbnail,
+		g.RoleRequirement, g.InviteRequirement, g.AccountAgeRequirement,
+		g.ServerAgeRequirement, models.BoolToInt(g.CaptchaRequirement), g.MessageRequired, g.VoiceRequirement,
+		requirements, bypassRoles, winLimits,
+		g.ID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// PauseGiveaway freezes a running giveaway, storing how much time it had left
+func (d *Database) PauseGiveaway(id, pausedAt, remaining int64) (bool, error) {
+	res, err := d.db.Exec("UPDATE giveaways SET paused_at = $1, paused_remaining = $2 WHERE id = $3 AND ended = 0 AND COALESCE(paused_at, 0) = 0",
+		pausedAt, remaining, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// ResumeGiveaway unfreezes a paused giveaway with its new end time
+func (d *Database) ResumeGiveaway(id, endTime int64) (bool, error) {
+	res, err := d.db.Exec("UPDATE giveaways SET paused_at = 0, paused_remaining = 0, end_time = $1 WHERE id = $2 AND ended = 0 AND paused_at > 0",
+		endTime, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// EndGiveaway marks a running giveaway as ended. Returns false if it had
+// already ended or was paused in the meantime, so only one caller goes on to
+// draw and settle it and a pause always wins over a pending end.
+func (d *Database) EndGiveaway(messageID string) (bool, error) {
+	res, err := d.db.Exec("UPDATE giveaways SET ended = 1 WHERE message_id = $1 AND ended = 0 AND COALESCE(paused_at, 0) = 0", messageID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// ReopenGiveaway undoes EndGiveaway when its draw couldn't be recorded, so it
+// can be ended again
+func (d *Database) ReopenGiveaway(messageID string) error {
+	_, err := d.db.Exec("UPDATE giveaways SET ended = 0 WHERE message_id = $1 AND COALESCE(cancelled, 0) = 0", messageID)
 	return err
 }
 
+// CancelGiveaway ends a giveaway without a draw and marks it cancelled for its
+// history. Returns false if it had already ended.
+func (d *Database) CancelGiveaway(messageID string) (bool, error) {
+	res, err := d.db.Exec("UPDATE giveaways SET ended = 1, cancelled = 1 WHERE message_id = $1 AND ended = 0", messageID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
 // Participant operations
 
 func (d *Database) AddParticipant(giveawayID int64, userID string) error {
@@ -478,6 +918,20 @@ func (d *Database) AddParticipant(giveawayID int64, userID string) error {
 	return err
 }
 
+// InsertParticipant adds a participant with the given entry weight and reports whether a new row was created
+func (d *Database) InsertParticipant(giveawayID int64, userID string, weight int) (bool, error) {
+	if weight < 1 {
+		weight = 1
+	}
+	res, err := d.db.Exec("INSERT INTO participants (giveaway_id, user_id, joined_at, weight) VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING",
+		giveawayID, userID, models.Now(), weight)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
 func (d *Database) RemoveParticipant(giveawayID int64, userID string) error {
 	_, err := d.db.Exec("DELETE FROM participants WHERE giveaway_id = $1 AND user_id = $2", giveawayID, userID)
 	return err
@@ -489,22 +943,90 @@ func (d *Database) GetParticipantCount(giveawayID int64) (int, error) {
 	return count, err
 }
 
-func (d *Database) GetParticipants(giveawayID int64) ([]string, error) {
-	rows, err := d.db.Query("SELECT user_id FROM participants WHERE giveaway_id = $1", giveawayID)
+// GetParticipants returns a page of participants in the order they joined.
+// A limit of 0 returns everyone from offset on.
+func (d *Database) GetParticipants(giveawayID int64, offset, limit int) ([]models.Participant, error) {
+	query := `
+		SELECT id, user_id, joined_at, COALESCE(weight, 1), COALESCE(bought_entries, 0)
+		FROM participants WHERE giveaway_id = $1 ORDER BY joined_at ASC, id ASC OFFSET $2`
+	args := []interface{}{giveawayID, offset}
+	if limit > 0 {
+		query += " LIMIT $3"
+		args = append(args, limit)
+	}
+
+	rows, err := d.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var users []string
+	var participants []models.Participant
 	for rows.Next() {
-		var userID string
-		if err := rows.Scan(&userID); err != nil {
+		p := models.Participant{GiveawayID: giveawayID}
+		if err := rows.Scan(&p.ID, &p.UserID, &p.JoinedAt, &p.Weight, &p.BoughtEntries); err != nil {
 			return nil, err
 		}
-		users = append(users, userID)
+		participants = append(participants, p)
 	}
-	return users, nil
+	return participants, nil
+}
+
+// GetEntryTotal returns how many entries a giveaway has across all participants
+func (d *Database) GetEntryTotal(giveawayID int64) (int, error) {
+	var total int
+	err := d.db.QueryRow("SELECT COALESCE(SUM(COALESCE(weight, 1)), 0) FROM participants WHERE giveaway_id = $1", giveawayID).Scan(&total)
+	return total, err
+}
+
+// GetWeightedParticipants returns every participant with their entry weight
+func (d *Database) GetWeightedParticipants(giveawayID int64) ([]models.Participant, error) {
+	rows, err := d.db.Query(`
+		SELECT id, user_id, joined_at, COALESCE(weight, 1), COALESCE(bought_entries, 0)
+		FROM participants WHERE giveaway_id = $1 ORDER BY id ASC`, giveawayID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var participants []models.Participant
+	for rows.Next() {
+		p := models.Participant{GiveawayID: giveawayID}
+		if err := rows.Scan(&p.ID, &p.UserID, &p.JoinedAt, &p.Weight, &p.BoughtEntries); err != nil {
+			return nil, err
+		}
+		participants = append(participants, p)
+	}
+	return participants, nil
+}
+
+// GetParticipant returns a single participant row, or nil if the user hasn't entered
+func (d *Database) GetParticipant(giveawayID int64, userID string) (*models.Participant, error) {
+	p := models.Participant{GiveawayID: giveawayID, UserID: userID}
+	err := d.db.QueryRow(`
+		SELECT id, joined_at, COALESCE(weight, 1), COALESCE(bought_entries, 0)
+		FROM participants WHERE giveaway_id = $1 AND user_id = $2`, giveawayID, userID).
+		Scan(&p.ID, &p.JoinedAt, &p.Weight, &p.BoughtEntries)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// AddBoughtEntries adds coin-bought entries to a participant, enforcing the per-user cap atomically
+func (d *Database) AddBoughtEntries(giveawayID int64, userID string, amount, maxBought int) (bool, error) {
+	res, err := d.db.Exec(`
+		UPDATE participants SET weight = COALESCE(weight, 1) + $3, bought_entries = COALESCE(bought_entries, 0) + $3
+		WHERE giveaway_id = $1 AND user_id = $2 AND COALESCE(bought_entries, 0) + $3 <= $4`,
+		giveawayID, userID, amount, maxBought)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
 }
 
 func (d *Database) IsParticipant(giveawayID int64, userID string) (bool, error) {
@@ -519,11 +1041,76 @@ func (d *Database) IsParticipant(giveawayID int64, userID string) (bool, error)
 // Winner operations
 
 func (d *Database) AddWinner(giveawayID int64, userID string) error {
-	_, err := d.db.Exec("INSERT INTO winners (giveaway_id, user_id, won_at) VALUES ($1, $2, $3)",
-		giveawayID, userID, models.Now())
+	return d.AddWinnerWithDeadline(giveawayID, userID, 0)
+}
+
+// AddWinnerWithDeadline records a winner who must claim before claimDeadline (Unix ms, 0 = no claim needed)
+func (d *Database) AddWinnerWithDeadline(giveawayID int64, userID string, claimDeadline int64) error {
+	status := models.WinnerStatusWon
+	if claimDeadline > 0 {
+		status = models.WinnerStatusPending
+	}
+	_, err := d.db.Exec("INSERT INTO winners (giveaway_id, user_id, won_at, status, claim_deadline) VALUES ($1, $2, $3, $4, $5)",
+		giveawayID, userID, models.Now(), status, claimDeadline)
 	return err
 }
 
+const winnerColumns = "id, giveaway_id, user_id, won_at, COALESCE(status, 'won'), COALESCE(claim_deadline, 0), COALESCE(claimed_at, 0)"
+
+func scanWinners(rows *sql.Rows) ([]*models.Winner, error) {
+	defer rows.Close()
+
+	var winners []*models.Winner
+	for rows.Next() {
+		w := &models.Winner{}
+		if err := rows.Scan(&w.ID, &w.GiveawayID, &w.UserID, &w.WonAt, &w.Status, &w.ClaimDeadline, &w.ClaimedAt); err != nil {
+			return nil, err
+		}
+		winners = append(winners, w)
+	}
+	return winners, nil
+}
+
+// GetWinnerRecords returns every winner of a giveaway (including expired ones) in the order they were drawn
+func (d *Database) GetWinnerRecords(giveawayID int64) ([]*models.Winner, error) {
+	rows, err := d.db.Query("SELECT "+winnerColumns+" FROM winners WHERE giveaway_id = $1 ORDER BY id ASC", giveawayID)
+	if err != nil {
+		return nil, err
+	}
+	return scanWinners(rows)
+}
+
+// GetPendingClaims returns every winner still inside their claim window
+func (d *Database) GetPendingClaims() ([]*models.Winner, error) {
+	rows, err := d.db.Query("SELECT "+winnerColumns+" FROM winners WHERE status = $1", models.WinnerStatusPending)
+	if err != nil {
+		return nil, err
+	}
+	return scanWinners(rows)
+}
+
+// ClaimWinner marks a pending winner as claimed if the deadline hasn't passed
+func (d *Database) ClaimWinner(giveawayID int64, userID string, now int64) (bool, error) {
+	res, err := d.db.Exec("UPDATE winners SET status = $1, claimed_at = $2 WHERE giveaway_id = $3 AND user_id = $4 AND status = $5 AND claim_deadline >= $2",
+		models.WinnerStatusClaimed, now, giveawayID, userID, models.WinnerStatusPending)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// ExpireWinner marks a pending winner as expired; false if they claimed (or expired) already
+func (d *Database) ExpireWinner(giveawayID int64, userID string) (bool, error) {
+	res, err := d.db.Exec("UPDATE winners SET status = $1 WHERE giveaway_id = $2 AND user_id = $3 AND status = $4",
+		models.WinnerStatusExpired, giveawayID, userID, models.WinnerStatusPending)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
 func (d *Database) GetWinners(giveawayID int64) ([]string, error) {
 	rows, err := d.db.Query("SELECT user_id FROM winners WHERE giveaway_id = $1", giveawayID)
 	if err != nil {
@@ -619,46 +1206,20 @@ func (d *Database) AddMessageCount(guildID, userID string, amount int) error {
 
 // Helpers
 
-func (d *Database) scanGiveaway(row *sql.Row) (*models.Giveaway, error) {
-	var g models.Giveaway
-	var captchaReq int
-	var customMessage sql.NullString
-	var roleReq sql.NullString
-	var inviteReq sql.NullInt64
-	var accountAgeReq sql.NullInt64
-	var serverAgeReq sql.NullInt64
-	var messageReq sql.NullInt64
-	var voiceReq sql.NullInt64
-	var entryFee sql.NullInt64
-	var assignRole sql.NullString
-	var thumbnail sql.NullString
-
-	err := row.Scan(
-		&g.ID, &g.MessageID, &g.ChannelID, &g.GuildID, &g.HostID, &g.Prize, &g.WinnersCount,
-		&g.EndTime, &g.Ended, &g.CreatedAt, &customMessage,
-		&roleReq, &inviteReq, &accountAgeReq, &serverAgeReq, &captchaReq, &messageReq, &voiceReq, &entryFee,
-		&assignRole, &thumbnail,
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	g.CustomMessage = customMessage.String
-	g.RoleRequirement = roleReq.String
-	g.InviteRequirement = int(inviteReq.Int64)
-	g.AccountAgeRequirement = int(accountAgeReq.Int64)
-	g.ServerAgeRequirement = int(serverAgeReq.Int64)
-	g.CaptchaRequirement = models.IntToBool(captchaReq)
-	g.MessageRequired = int(messageReq.Int64)
-	g.VoiceRequirement = int(voiceReq.Int64)
-	g.EntryFee = int(entryFee.Int64)
-	g.AssignRole = assignRole.String
-	g.Thumbnail = thumbnail.String
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
-	return &g, nil
+func (d *Database) scanGiveaway(row *sql.Row) (*models.Giveaway, error) {
+	return d.scanGiveawayFrom(row)
 }
 
 func (d *Database) scanGiveawayRows(rows *sql.Rows) (*models.Giveaway, error) {
+	return d.scanGiveawayFrom(rows)
+}
+
+func (d *Database) scanGiveawayFrom(sc rowScanner) (*models.Giveaway, error) {
 	var g models.Giveaway
 	var captchaReq int
 	var customMessage sql.NullString
@@ -671,12 +1232,32 @@ func (d *Database) scanGiveawayRows(rows *sql.Rows) (*models.Giveaway, error) {
 	var entryFee sql.NullInt64
 	var assignRole sql.NullString
 	var thumbnail sql.NullString
-
-	err := rows.Scan(
+	var emoji sql.NullString
+	var entryMode sql.NullString
+	var bonusRules sql.NullString
+	var drawSecret sql.NullString
+	var drawCommitment sql.NullString
+	var claimHours sql.NullInt64
+	var pausedAt sql.NullInt64
+	var pausedRemaining sql.NullInt64
+	var scheduled sql.NullInt64
+	var startTime sql.NullInt64
+	var recurringID sql.NullInt64
+	var requirements sql.NullString
+	var bypassRoles sql.NullString
+	var reward sql.NullString
+	var feeMode sql.NullString
+	var winLimits sql.NullString
+	var commitmentAtDraw sql.NullInt64
+
+	err := sc.Scan(
 		&g.ID, &g.MessageID, &g.ChannelID, &g.GuildID, &g.HostID, &g.Prize, &g.WinnersCount,
 		&g.EndTime, &g.Ended, &g.CreatedAt, &customMessage,
 		&roleReq, &inviteReq, &accountAgeReq, &serverAgeReq, &captchaReq, &messageReq, &voiceReq, &entryFee,
 		&assignRole, &thumbnail,
+		&emoji, &entryMode, &bonusRules, &drawSecret, &drawCommitment, &claimHours,
+		&pausedAt, &pausedRemaining, &scheduled, &startTime, &recurringID, &requirements, &bypassRoles,
+		&reward, &feeMode, &winLimits, &commitmentAtDraw,
 	)
 	if err != nil {
 		return nil, err
@@ -691,8 +1272,54 @@ func (d *Database) scanGiveawayRows(rows *sql.Rows) (*models.Giveaway, error) {
 	g.MessageRequired = int(messageReq.Int64)
 	g.VoiceRequirement = int(voiceReq.Int64)
 	g.EntryFee = int(entryFee.Int64)
+	g.FeeMode = feeMode.String
+	if g.FeeMode == "" {
+		g.FeeMode = models.FeeModeKeep
+	}
 	g.AssignRole = assignRole.String
 	g.Thumbnail = thumbnail.String
+	g.Emoji = emoji.String
+	if g.Emoji == "" {
+		g.Emoji = "🎉"
+	}
+	g.EntryMode = entryMode.String
+	if g.EntryMode == "" {
+		g.EntryMode = models.EntryModeReaction
+	}
+	g.DrawSecret = drawSecret.String
+	g.DrawCommitment = drawCommitment.String
+	g.CommitmentAtDraw = commitmentAtDraw.Int64 == 1
+	g.ClaimHours = int(claimHours.Int64)
+	g.PausedAt = pausedAt.Int64
+	g.PausedRemaining = pausedRemaining.Int64
+	g.Scheduled = scheduled.Int64 == 1
+	g.StartTime = startTime.Int64
+	g.RecurringID = recurringID.Int64
+	if bonusRules.String != "" {
+		// A malformed rule set only loses the bonuses, never the giveaway
+		_ = json.Unmarshal([]byte(bonusRules.String), &g.Bonus)
+	}
+	if requirements.String != "" {
+		var group models.RequirementGroup
+		if err := json.Unmarshal([]byte(requirements.String), &group); err == nil {
+			g.Requirements = &group
+		}
+	}
+	if bypassRoles.String != "" {
+		_ = json.Unmarshal([]byte(bypassRoles.String), &g.BypassRoles)
+	}
+	if reward.String != "" {
+		var r models.Reward
+		if err := json.Unmarshal([]byte(reward.String), &r); err == nil {
+			g.Reward = &r
+		}
+	}
+	if winLimits.String != "" {
+		var l models.WinLimits
+		if err := json.Unmarshal([]byte(winLimits.String), &l); err == nil {
+			g.WinLimits = &l
+		}
+	}
 
 	return &g, nil
 }
@@ -50,8 +50,10 @@ func (d *Database) UpdateEconomyUser(u *models.EconomyUser) error {
 
 // Atomic Operations
 
-func (d *Database) AddUserBalance(guildID, userID string, amount int64) (int64, error) {
-	query := `
+// Balance updates shared with the escrow, which runs them in its own
+// transaction. All take $1 user_id, $2 guild_id, $3 amount.
+const (
+	addBalanceQuery = `
 		INSERT INTO economy_users (user_id, guild_id, balance, total_earned)
 		VALUES ($1, $2, $3, $3)
 		ON CONFLICT(user_id, guild_id) DO UPDATE SET
@@ -59,8 +61,32 @@ func (d *Database) AddUserBalance(guildID, userID string, amount int64) (int64,
 			total_earned = economy_users.total_earned + $3
 		RETURNING balance
 	`
+	refundBalanceQuery = `
+		INSERT INTO economy_users (user_id, guild_id, balance)
+		VALUES ($1, $2, $3)
+		ON CONFLICT(user_id, guild_id) DO UPDATE SET
+			balance = economy_users.balance + $3,
+			total_spent = GREATEST(economy_users.total_spent - $3, 0)
+		RETURNING balance
+	`
+	removeBalanceQuery = `
+		UPDATE economy_users
+		SET balance = balance - $3, total_spent = total_spent + $3
+		WHERE user_id = $1 AND guild_id = $2 AND balance >= $3
+		RETURNING balance
+	`
+)
+
+func (d *Database) AddUserBalance(guildID, userID string, amount int64) (int64, error) {
+	var newBalance int64
+	err := d.db.QueryRow(addBalanceQuery, userID, guildID, amount).Scan(&newBalance)
+	return newBalance, err
+}
+
+// RefundUserBalance returns spent coins, undoing the spend rather than counting them as earned
+func (d *Database) RefundUserBalance(guildID, userID string, amount int64) (int64, error) {
 	var newBalance int64
-	err := d.db.QueryRow(query, userID, guildID, amount).Scan(&newBalance)
+	err := d.db.QueryRow(refundBalanceQuery, userID, guildID, amount).Scan(&newBalance)
 	return newBalance, err
 }
 
@@ -69,14 +95,8 @@ func (d *Database) RemoveUserBalance(guildID, userID string, amount int64) (int6
 	// We can do this in one query with a WHERE clause, but we need to handle "not found" vs "insufficient funds"
 	// Actually, for atomic updates, we can try to update and check rows affected or returning.
 
-	query := `
-		UPDATE economy_users 
-		SET balance = balance - $3, total_spent = total_spent + $3
-		WHERE user_id = $1 AND guild_id = $2 AND balance >= $3
-		RETURNING balance
-	`
 	var newBalance int64
-	err := d.db.QueryRow(query, userID, guildID, amount).Scan(&newBalance)
+	err := d.db.QueryRow(removeBalanceQuery, userID, guildID, amount).Scan(&newBalance)
 
 	if err == sql.ErrNoRows {
 		// Either user doesn't exist or insufficient funds
@@ -118,20 +138,21 @@ func (d *Database) GetEconomyLeaderboard(guildID string, limit int) ([]*models.E
 
 func (d *Database) GetEconomyConfig(guildID string) (*models.EconomyConfig, error) {
 	// Cache logic moved to service layer
-	query := `SELECT guild_id, message_reward, vc_reward_per_min, daily_reward, weekly_reward, hourly_reward, invite_reward, react_reward, poll_reward, event_reward, upvote_reward, gamble_enabled, max_gamble_amount, allowed_channels, currency_emoji FROM economy_config WHERE guild_id = $1`
+	query := `SELECT guild_id, message_reward, vc_reward_per_min, daily_reward, weekly_reward, hourly_reward, invite_reward, react_reward, poll_reward, event_reward, upvote_reward, gamble_enabled, max_gamble_amount, allowed_channels, currency_emoji, invite_fake_days FROM economy_config WHERE guild_id = $1`
 	row := d.db.QueryRow(query, guildID)
 
 	var c models.EconomyConfig
 	var gambleEnabled int
 	var currencyEmoji sql.NullString
+	var inviteFakeDays sql.NullInt64
 	err := row.Scan(
 		&c.GuildID, &c.MessageReward, &c.VCRewardPerMin, &c.DailyReward, &c.WeeklyReward,
 		&c.HourlyReward, &c.InviteReward, &c.ReactReward, &c.PollReward, &c.EventReward,
-		&c.UpvoteReward, &gambleEnabled, &c.MaxGambleAmount, &c.AllowedChannels, &currencyEmoji,
+		&c.UpvoteReward, &gambleEnabled, &c.MaxGambleAmount, &c.AllowedChannels, &currencyEmoji, &inviteFakeDays,
 	)
 	if err == sql.ErrNoRows {
 		// Return default config
-		return &models.EconomyConfig{GuildID: guildID, MaxGambleAmount: 20000, CurrencyEmoji: "<:Cash:1443554334670327848>"}, nil
+		return &models.EconomyConfig{GuildID: guildID, MaxGambleAmount: 20000, CurrencyEmoji: "<:Cash:1443554334670327848>", InviteFakeDays: models.DefaultInviteFakeDays}, nil
 	}
 	if err != nil {
 		return nil, err
@@ -141,6 +162,10 @@ func (d *Database) GetEconomyConfig(guildID string) (*models.EconomyConfig, erro
 	if c.CurrencyEmoji == "" {
 		c.CurrencyEmoji = "<:Cash:1443554334670327848>"
 	}
+	c.InviteFakeDays = models.DefaultInviteFakeDays
+	if inviteFakeDays.Valid {
+		c.InviteFakeDays = int(inviteFakeDays.Int64)
+	}
 
 	return &c, nil
 }
@@ -149,8 +174,8 @@ func (d *Database) UpdateEconomyConfig(c *models.EconomyConfig) error {
 	query := `
 		INSERT INTO economy_config (
 			guild_id, message_reward, vc_reward_per_min, daily_reward, weekly_reward, hourly_reward,
-			invite_reward, react_reward, poll_reward, event_reward, upvote_reward, gamble_enabled, max_gamble_amount, allowed_channels, currency_emoji
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+			invite_reward, react_reward, poll_reward, event_reward, upvote_reward, gamble_enabled, max_gamble_amount, allowed_channels, currency_emoji, invite_fake_days
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 		ON CONFLICT(guild_id) DO UPDATE SET
 			message_reward = EXCLUDED.message_reward,
 			vc_reward_per_min = EXCLUDED.vc_reward_per_min,
@@ -165,11 +190,12 @@ func (d *Database) UpdateEconomyConfig(c *models.EconomyConfig) error {
 			gamble_enabled = EXCLUDED.gamble_enabled,
 			max_gamble_amount = EXCLUDED.max_gamble_amount,
 			allowed_channels = EXCLUDED.allowed_channels,
-			currency_emoji = EXCLUDED.currency_emoji
+			currency_emoji = EXCLUDED.currency_emoji,
+			invite_fake_days = EXCLUDED.invite_fake_days
 	`
 	_, err := d.db.Exec(query,
 		c.GuildID, c.MessageReward, c.VCRewardPerMin, c.DailyReward, c.WeeklyReward, c.HourlyReward,
-		c.InviteReward, c.ReactReward, c.PollReward, c.EventReward, c.UpvoteReward, models.BoolToInt(c.GambleEnabled), c.MaxGambleAmount, c.AllowedChannels, c.CurrencyEmoji,
+		c.InviteReward, c.ReactReward, c.PollReward, c.EventReward, c.UpvoteReward, models.BoolToInt(c.GambleEnabled), c.MaxGambleAmount, c.AllowedChannels, c.CurrencyEmoji, c.InviteFakeDays,
 	)
 	return err
 }
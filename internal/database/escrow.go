@@ -0,0 +1,135 @@
+package database
+
+import (
+	"database/sql"
+	"discord-giveaway-bot/internal/models"
+	"errors"
+	"fmt"
+)
+
+// Entry fee escrow operations
+
+func (d *Database) AddEscrowEntry(e *models.EscrowEntry) error {
+	return d.db.QueryRow(`
+		INSERT INTO giveaway_escrow (giveaway_id, user_id, kind, amount, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, e.GiveawayID, e.UserID, e.Kind, e.Amount, e.CreatedAt).Scan(&e.ID)
+}
+
+// MoveEscrowCoins moves coins between a user's balance and the escrow and
+// records the movement, in one transaction. Deposits come out of the balance,
+// refunds go back as unspent coins and payouts are credited as earned.
+// Returns the user's new balance.
+func (d *Database) MoveEscrowCoins(guildID string, e *models.EscrowEntry) (int64, error) {
+	var query string
+	amount := e.Amount
+	switch e.Kind {
+	case models.EscrowDeposit:
+		query = removeBalanceQuery
+	case models.EscrowRefund:
+		query, amount = refundBalanceQuery, -amount
+	case models.EscrowPayout:
+		query, amount = addBalanceQuery, -amount
+	default:
+		return 0, fmt.Errorf("escrow %s doesn't move coins", e.Kind)
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var balance int64
+	err = tx.QueryRow(query, e.UserID, guildID, amount).Scan(&balance)
+	if err == sql.ErrNoRows {
+		return 0, errors.New("insufficient funds")
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO giveaway_escrow (giveaway_id, user_id, kind, amount, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, e.GiveawayID, e.UserID, e.Kind, e.Amount, e.CreatedAt).Scan(&e.ID)
+	if err != nil {
+		return 0, err
+	}
+	return balance, tx.Commit()
+}
+
+// GetEscrowHeld returns what's still held for each participant. Pot payouts
+// come out of the shared pot, not anyone's own fees, so they're left out.
+func (d *Database) GetEscrowHeld(giveawayID int64) (map[string]int64, error) {
+	rows, err := d.db.Query(`
+		SELECT user_id, SUM(amount) FROM giveaway_escrow
+		WHERE giveaway_id = $1 AND kind <> $2
+		GROUP BY user_id
+		HAVING SUM(amount) > 0
+	`, giveawayID, models.EscrowPayout)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	held := make(map[string]int64)
+	for rows.Next() {
+		var userID string
+		var amount int64
+		if err := rows.Scan(&userID, &amount); err != nil {
+			return nil, err
+		}
+		held[userID] = amount
+	}
+	return held, nil
+}
+
+// GetEscrowHeldFor returns what's still held for one participant
+func (d *Database) GetEscrowHeldFor(giveawayID int64, userID string) (int64, error) {
+	var held int64
+	err := d.db.QueryRow(`
+		SELECT COALESCE(SUM(amount), 0) FROM giveaway_escrow
+		WHERE giveaway_id = $1 AND user_id = $2 AND kind <> $3
+	`, giveawayID, userID, models.EscrowPayout).Scan(&held)
+	return held, err
+}
+
+// GetEscrowPot returns the collected pot (deposits less refunds) and what's
+// left of it after payouts and any remainder that was kept. Forfeits only move
+// coins from a participant into the pot, so they count towards neither.
+func (d *Database) GetEscrowPot(giveawayID int64) (pot, remaining int64, err error) {
+	err = d.db.QueryRow(`
+		SELECT COALESCE(SUM(amount) FILTER (WHERE kind IN ($2, $3)), 0),
+			COALESCE(SUM(amount) FILTER (WHERE kind <> $4), 0)
+		FROM giveaway_escrow WHERE giveaway_id = $1
+	`, giveawayID, models.EscrowDeposit, models.EscrowRefund, models.EscrowForfeit).Scan(&pot, &remaining)
+	return pot, remaining, err
+}
+
+// GetEscrowTotals returns how many coins moved through the escrow, by kind.
+// Amounts are positive whichever way the coins went.
+func (d *Database) GetEscrowTotals(giveawayID int64) (map[string]int64, error) {
+	rows, err := d.db.Query(`
+		SELECT kind, SUM(ABS(amount)) FROM giveaway_escrow
+		WHERE giveaway_id = $1
+		GROUP BY kind
+	`, giveawayID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := make(map[string]int64)
+	for rows.Next() {
+		var kind string
+		var amount int64
+		if err := rows.Scan(&kind, &amount); err != nil {
+			return nil, err
+		}
+		totals[kind] = amount
+	}
+	return totals, nil
+}
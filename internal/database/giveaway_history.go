@@ -0,0 +1,185 @@
+package database
+
+import (
+	"discord-giveaway-bot/internal/models"
+
+	"github.com/goccy/go-json"
+)
+
+// Provably fair draw operations
+
+// SetDrawCommitment stores the draw secret for giveaways created before
+// commitments existed, flagging that it wasn't published at start
+func (d *Database) SetDrawCommitment(giveawayID int64, secret, commitment string) error {
+	_, err := d.db.Exec("UPDATE giveaways SET draw_secret = $1, draw_commitment = $2, commitment_at_draw = 1 WHERE id = $3", secret, commitment, giveawayID)
+	return err
+}
+
+// SaveGiveawayDraw stores a draw record. Draw numbers are unique per
+// giveaway; returns false if another draw already took this one.
+func (d *Database) SaveGiveawayDraw(draw *models.GiveawayDraw) (bool, error) {
+	winners, err := json.Marshal(draw.Winners)
+	if err != nil {
+		return false, err
+	}
+	res, err := d.db.Exec(`
+		INSERT INTO giveaway_draws (giveaway_id, draw_number, seed, participants_hash, snapshot, winner_count, winners, created_at, secret, kind)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (giveaway_id, draw_number) DO NOTHING
+	`, draw.GiveawayID, draw.DrawNumber, draw.Seed, draw.ParticipantsHash, draw.Snapshot, draw.WinnerCount, string(winners), draw.CreatedAt, draw.Secret, draw.Kind)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// GetGiveawayDraws returns every draw for a giveaway in draw order
+func (d *Database) GetGiveawayDraws(giveawayID int64) ([]*models.GiveawayDraw, error) {
+	rows, err := d.db.Query(`
+		SELECT draw_number, seed, participants_hash, snapshot, winner_count, winners, created_at, COALESCE(secret, ''), COALESCE(kind, '')
+		FROM giveaway_draws WHERE giveaway_id = $1 ORDER BY draw_number ASC
+	`, giveawayID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var draws []*models.GiveawayDraw
+	for rows.Next() {
+		draw := &models.GiveawayDraw{GiveawayID: giveawayID}
+		var winners string
+		if err := rows.Scan(&draw.DrawNumber, &draw.Seed, &draw.ParticipantsHash, &draw.Snapshot, &draw.WinnerCount, &winners, &draw.CreatedAt, &draw.Secret, &draw.Kind); err != nil {
+			return nil, err
+		}
+		if draw.Kind == "" {
+			// Recorded before draws had kinds, when every later draw was a reroll
+			draw.Kind = models.DrawKindReroll
+			if draw.DrawNumber == 0 {
+				draw.Kind = models.DrawKindInitial
+			}
+		}
+		if err := json.Unmarshal([]byte(winners), &draw.Winners); err != nil {
+			return nil, err
+		}
+		draws = append(draws, draw)
+	}
+	return draws, nil
+}
+
+// GetNextDrawNumber returns the number the giveaway's next draw (end or reroll) gets
+func (d *Database) GetNextDrawNumber(giveawayID int64) (int, error) {
+	var next int
+	err := d.db.QueryRow("SELECT COALESCE(MAX(draw_number) + 1, 0) FROM giveaway_draws WHERE giveaway_id = $1", giveawayID).Scan(&next)
+	return next, err
+}
+
+// Reroll history operations
+
+func (d *Database) AddRerollRecord(r *models.Reroll) error {
+	winners, err := json.Marshal(r.Winners)
+	if err != nil {
+		return err
+	}
+	return d.db.QueryRow(`
+		INSERT INTO giveaway_rerolls (giveaway_id, rerolled_by, reason, count, excluded_user, winners, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`, r.GiveawayID, r.RerolledBy, r.Reason, r.Count, r.ExcludedUser, string(winners), r.CreatedAt).Scan(&r.ID)
+}
+
+// GetRerollHistory returns a giveaway's rerolls, oldest first
+func (d *Database) GetRerollHistory(giveawayID int64) ([]*models.Reroll, error) {
+	rows, err := d.db.Query(`
+		SELECT id, COALESCE(rerolled_by, ''), reason, count, COALESCE(excluded_user, ''), winners, created_at
+		FROM giveaway_rerolls WHERE giveaway_id = $1 ORDER BY id ASC
+	`, giveawayID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []*models.Reroll
+	for rows.Next() {
+		r := &models.Reroll{GiveawayID: giveawayID}
+		var winners string
+		if err := rows.Scan(&r.ID, &r.RerolledBy, &r.Reason, &r.Count, &r.ExcludedUser, &winners, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(winners), &r.Winners); err != nil {
+			return nil, err
+		}
+		history = append(history, r)
+	}
+	return history, nil
+}
+
+// Edit history operations
+
+func (d *Database) AddGiveawayEdit(e *models.GiveawayEdit) error {
+	changes, err := json.Marshal(e.Changes)
+	if err != nil {
+		return err
+	}
+	return d.db.QueryRow(`
+		INSERT INTO giveaway_edits (giveaway_id, editor_id, changes, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`, e.GiveawayID, e.EditorID, string(changes), e.CreatedAt).Scan(&e.ID)
+}
+
+// GetGiveawayEdits returns a giveaway's edit history, oldest first
+func (d *Database) GetGiveawayEdits(giveawayID int64) ([]*models.GiveawayEdit, error) {
+	rows, err := d.db.Query(`
+		SELECT id, editor_id, changes, created_at
+		FROM giveaway_edits WHERE giveaway_id = $1 ORDER BY id ASC
+	`, giveawayID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var edits []*models.GiveawayEdit
+	for rows.Next() {
+		e := &models.GiveawayEdit{GiveawayID: giveawayID}
+		var changes string
+		if err := rows.Scan(&e.ID, &e.EditorID, &changes, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(changes), &e.Changes); err != nil {
+			return nil, err
+		}
+		edits = append(edits, e)
+	}
+	return edits, nil
+}
+
+func (d *Database) AddDisqualification(dq *models.Disqualification) error {
+	return d.db.QueryRow(`
+		INSERT INTO giveaway_disqualifications (giveaway_id, user_id, reason, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`, dq.GiveawayID, dq.UserID, dq.Reason, dq.CreatedAt).Scan(&dq.ID)
+}
+
+// GetDisqualifications returns the winners replaced at draw time, oldest first
+func (d *Database) GetDisqualifications(giveawayID int64) ([]*models.Disqualification, error) {
+	rows, err := d.db.Query(`
+		SELECT id, user_id, reason, created_at
+		FROM giveaway_disqualifications WHERE giveaway_id = $1 ORDER BY id ASC
+	`, giveawayID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var disqualifications []*models.Disqualification
+	for rows.Next() {
+		dq := &models.Disqualification{GiveawayID: giveawayID}
+		if err := rows.Scan(&dq.ID, &dq.UserID, &dq.Reason, &dq.CreatedAt); err != nil {
+			return nil, err
+		}
+		disqualifications = append(disqualifications, dq)
+	}
+	return disqualifications, nil
+}
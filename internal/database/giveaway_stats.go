@@ -0,0 +1,163 @@
+package database
+
+import (
+	"discord-giveaway-bot/internal/models"
+	"fmt"
+	"strings"
+)
+
+// Giveaway history and analytics. Scheduled giveaways haven't happened yet,
+// so they're left out of both.
+
+// AddParticipantEvent logs a participant joining or leaving a giveaway
+func (d *Database) AddParticipantEvent(giveawayID int64, guildID, userID, kind string) error {
+	_, err := d.db.Exec(`
+		INSERT INTO participant_events (giveaway_id, guild_id, user_id, kind, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, giveawayID, guildID, userID, kind, models.Now())
+	return err
+}
+
+// historyWhere builds the WHERE clause for a guild's history with the filter applied
+func historyWhere(guildID string, f models.HistoryFilter) (string, []interface{}) {
+	conditions := []string{"g.guild_id = $1", "COALESCE(g.scheduled, 0) = 0"}
+	args := []interface{}{guildID}
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if f.HostID != "" {
+		add("g.host_id = $%d", f.HostID)
+	}
+	if f.From > 0 {
+		add("g.end_time >= $%d", f.From)
+	}
+	if f.To > 0 {
+		add("g.end_time <= $%d", f.To)
+	}
+	switch f.Status {
+	case models.HistoryStatusActive:
+		conditions = append(conditions, "g.ended = 0")
+	case models.HistoryStatusEnded:
+		conditions = append(conditions, "g.ended = 1", "COALESCE(g.cancelled, 0) = 0")
+	case models.HistoryStatusCancelled:
+		conditions = append(conditions, "COALESCE(g.cancelled, 0) = 1")
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// CountGiveawayHistory returns how many giveaways match the filter
+func (d *Database) CountGiveawayHistory(guildID string, f models.HistoryFilter) (int, error) {
+	where, args := historyWhere(guildID, f)
+	var count int
+	err := d.db.QueryRow("SELECT COUNT(*) FROM giveaways g"+where, args...).Scan(&count)
+	return count, err
+}
+
+// GetGiveawayHistory returns a page of the guild's giveaways, latest end time first
+func (d *Database) GetGiveawayHistory(guildID string, f models.HistoryFilter, offset, limit int) ([]*models.GiveawaySummary, error) {
+	where, args := historyWhere(guildID, f)
+	args = append(args, models.WinnerStatusExpired, limit, offset)
+	query := fmt.Sprintf(`
+		SELECT g.id, g.message_id, g.channel_id, g.host_id, g.prize, g.winners_count, g.end_time,
+			g.ended, COALESCE(g.cancelled, 0),
+			(SELECT COUNT(*) FROM participants p WHERE p.giveaway_id = g.id),
+			COALESCE((SELECT string_agg(w.user_id, ',' ORDER BY w.id) FROM winners w
+				WHERE w.giveaway_id = g.id AND COALESCE(w.status, 'won') <> $%d), '')
+		FROM giveaways g%s
+		ORDER BY g.end_time DESC, g.id DESC
+		LIMIT $%d OFFSET $%d
+	`, len(args)-2, where, len(args)-1, len(args))
+
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []*models.GiveawaySummary
+	for rows.Next() {
+		g := &models.GiveawaySummary{}
+		var winners string
+		if err := rows.Scan(&g.ID, &g.MessageID, &g.ChannelID, &g.HostID, &g.Prize, &g.WinnersCount, &g.EndTime,
+			&g.Ended, &g.Cancelled, &g.Participants, &winners); err != nil {
+			return nil, err
+		}
+		if winners != "" {
+			g.Winners = strings.Split(winners, ",")
+		}
+		history = append(history, g)
+	}
+	return history, nil
+}
+
+// GetGiveawayStats returns the guild's totals for giveaways created since the given time
+func (d *Database) GetGiveawayStats(guildID string, since int64) (*models.GiveawayStats, error) {
+	s := &models.GiveawayStats{}
+	err := d.db.QueryRow(`
+		WITH gs AS (
+			SELECT id, ended, COALESCE(cancelled, 0) AS cancelled FROM giveaways
+			WHERE guild_id = $1 AND COALESCE(scheduled, 0) = 0 AND created_at >= $2
+		)
+		SELECT
+			(SELECT COUNT(*) FROM gs),
+			(SELECT COUNT(*) FROM gs WHERE ended = 0),
+			(SELECT COUNT(*) FROM gs WHERE cancelled = 1),
+			(SELECT COUNT(*) FROM participants p JOIN gs ON gs.id = p.giveaway_id),
+			(SELECT COUNT(DISTINCT p.user_id) FROM participants p JOIN gs ON gs.id = p.giveaway_id),
+			(SELECT COUNT(*) FROM winners w JOIN gs ON gs.id = w.giveaway_id WHERE COALESCE(w.status, 'won') <> $3),
+			(SELECT COALESCE(SUM(e.amount), 0) FROM giveaway_escrow e JOIN gs ON gs.id = e.giveaway_id WHERE e.kind = $4),
+			(SELECT COALESCE(-SUM(e.amount), 0) FROM giveaway_escrow e JOIN gs ON gs.id = e.giveaway_id WHERE e.kind = $5),
+			(SELECT COUNT(*) FROM participant_events WHERE guild_id = $1 AND created_at >= $2 AND kind = $6),
+			(SELECT COUNT(*) FROM participant_events WHERE guild_id = $1 AND created_at >= $2 AND kind = $7)
+	`, guildID, since, models.WinnerStatusExpired, models.EscrowDeposit, models.EscrowKept,
+		models.ParticipantJoined, models.ParticipantLeft).Scan(
+		&s.Giveaways, &s.Active, &s.Cancelled, &s.Entries, &s.Entrants, &s.Winners,
+		&s.FeesCollected, &s.FeesKept, &s.Joins, &s.Leaves)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// GetTopHosts returns who hosted the most giveaways created since the given time
+func (d *Database) GetTopHosts(guildID string, since int64, limit int) ([]models.StatCount, error) {
+	return d.queryStatCounts(`
+		SELECT host_id, COUNT(*) FROM giveaways
+		WHERE guild_id = $1 AND COALESCE(scheduled, 0) = 0 AND created_at >= $2
+		GROUP BY host_id
+		ORDER BY COUNT(*) DESC, host_id
+		LIMIT $3
+	`, guildID, since, limit)
+}
+
+// GetTopWinners returns who won the most giveaways since the given time
+func (d *Database) GetTopWinners(guildID string, since int64, limit int) ([]models.StatCount, error) {
+	return d.queryStatCounts(`
+		SELECT w.user_id, COUNT(*) FROM winners w
+		JOIN giveaways g ON g.id = w.giveaway_id
+		WHERE g.guild_id = $1 AND w.won_at >= $2 AND COALESCE(w.status, 'won') <> $4
+		GROUP BY w.user_id
+		ORDER BY COUNT(*) DESC, w.user_id
+		LIMIT $3
+	`, guildID, since, limit, models.WinnerStatusExpired)
+}
+
+func (d *Database) queryStatCounts(query string, args ...interface{}) ([]models.StatCount, error) {
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []models.StatCount
+	for rows.Next() {
+		var c models.StatCount
+		if err := rows.Scan(&c.UserID, &c.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, nil
+}
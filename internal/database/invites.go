@@ -0,0 +1,85 @@
+package database
+
+import (
+	"database/sql"
+	"discord-giveaway-bot/internal/models"
+)
+
+// Invite tracking operations
+
+// inviteStatsColumns sums an inviter's joins into models.InviteStats
+const inviteStatsColumns = `
+	COUNT(*) FILTER (WHERE fake = 0 AND rejoin = 0 AND left_at = 0),
+	COUNT(*) FILTER (WHERE fake = 0 AND rejoin = 0 AND left_at > 0),
+	COUNT(*) FILTER (WHERE fake = 1),
+	COUNT(*) FILTER (WHERE fake = 0 AND rejoin = 1)`
+
+// RecordInviteJoin stores a join, flagging it as a rejoin if the member joined before
+func (d *Database) RecordInviteJoin(j *models.InviteJoin) error {
+	err := d.db.QueryRow("SELECT EXISTS(SELECT 1 FROM invite_joins WHERE guild_id = $1 AND user_id = $2)",
+		j.GuildID, j.UserID).Scan(&j.Rejoin)
+	if err != nil {
+		return err
+	}
+	return d.db.QueryRow(`
+		INSERT INTO invite_joins (guild_id, user_id, inviter_id, code, fake, rejoin, left_at, joined_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`, j.GuildID, j.UserID, j.InviterID, j.Code, models.BoolToInt(j.Fake), models.BoolToInt(j.Rejoin),
+		j.LeftAt, j.JoinedAt).Scan(&j.ID)
+}
+
+// MarkInviteLeft records that a member left, so their join stops counting
+func (d *Database) MarkInviteLeft(guildID, userID string, leftAt int64) error {
+	_, err := d.db.Exec("UPDATE invite_joins SET left_at = $1 WHERE guild_id = $2 AND user_id = $3 AND left_at = 0",
+		leftAt, guildID, userID)
+	return err
+}
+
+// GetInviteStats returns an inviter's invite counts
+func (d *Database) GetInviteStats(guildID, inviterID string) (*models.InviteStats, error) {
+	stats := &models.InviteStats{InviterID: inviterID}
+	err := d.db.QueryRow("SELECT "+inviteStatsColumns+" FROM invite_joins WHERE guild_id = $1 AND inviter_id = $2",
+		guildID, inviterID).Scan(&stats.Regular, &stats.Left, &stats.Fake, &stats.Rejoins)
+	return stats, err
+}
+
+// GetInviteLeaderboard returns the guild's top inviters by regular invites
+func (d *Database) GetInviteLeaderboard(guildID string, limit int) ([]*models.InviteStats, error) {
+	rows, err := d.db.Query(`
+		SELECT inviter_id, `+inviteStatsColumns+`
+		FROM invite_joins
+		WHERE guild_id = $1 AND inviter_id <> ''
+		GROUP BY inviter_id
+		ORDER BY 2 DESC, inviter_id ASC
+		LIMIT $2
+	`, guildID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var leaderboard []*models.InviteStats
+	for rows.Next() {
+		s := &models.InviteStats{}
+		if err := rows.Scan(&s.InviterID, &s.Regular, &s.Left, &s.Fake, &s.Rejoins); err != nil {
+			return nil, err
+		}
+		leaderboard = append(leaderboard, s)
+	}
+	return leaderboard, nil
+}
+
+// GetInviter returns who invited a member on their latest join, or "" if unknown
+func (d *Database) GetInviter(guildID, userID string) (string, error) {
+	var inviterID string
+	err := d.db.QueryRow(`
+		SELECT inviter_id FROM invite_joins
+		WHERE guild_id = $1 AND user_id = $2
+		ORDER BY joined_at DESC LIMIT 1
+	`, guildID, userID).Scan(&inviterID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return inviterID, err
+}
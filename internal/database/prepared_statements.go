@@ -45,11 +45,7 @@ func (d *Database) InitPreparedStatements() error {
 
 	// Giveaway queries
 	d.PreparedStmts.getGiveaway, err = d.db.Prepare(`
-		SELECT id, message_id, channel_id, guild_id, host_id, prize, winners_count,
-			end_time, ended, created_at, custom_message,
-			role_requirement, invite_requirement, account_age_requirement, 
-			server_age_requirement, captcha_requirement, message_required, 
-			voice_requirement, entry_fee, assign_role, thumbnail
+		SELECT ` + giveawayColumns + `
 		FROM giveaways WHERE message_id = $1
 	`)
 	if err != nil {
@@ -57,11 +53,7 @@ func (d *Database) InitPreparedStatements() error {
 	}
 
 	d.PreparedStmts.getGiveawayByID, err = d.db.Prepare(`
-		SELECT id, message_id, channel_id, guild_id, host_id, prize, winners_count,
-			end_time, ended, created_at, custom_message,
-			role_requirement, invite_requirement, account_age_requirement, 
-			server_age_requirement, captcha_requirement, message_required, 
-			voice_requirement, entry_fee, assign_role, thumbnail
+		SELECT ` + giveawayColumns + `
 		FROM giveaways WHERE id = $1
 	`)
 	if err != nil {
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"database/sql"
+	"discord-giveaway-bot/internal/models"
+)
+
+// Prize delivery operations
+
+const prizeDeliveryColumns = "id, giveaway_id, user_id, kind, status, COALESCE(detail, ''), expires_at, created_at, COALESCE(already_held, FALSE)"
+
+func (d *Database) AddPrizeDelivery(p *models.PrizeDelivery) error {
+	return d.db.QueryRow(`
+		INSERT INTO prize_deliveries (giveaway_id, user_id, kind, status, detail, expires_at, created_at, already_held)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`, p.GiveawayID, p.UserID, p.Kind, p.Status, p.Detail, p.ExpiresAt, p.CreatedAt, p.AlreadyHeld).Scan(&p.ID)
+}
+
+// GetPrizeDeliveries returns every delivery for a giveaway, oldest first
+func (d *Database) GetPrizeDeliveries(giveawayID int64) ([]*models.PrizeDelivery, error) {
+	rows, err := d.db.Query("SELECT "+prizeDeliveryColumns+" FROM prize_deliveries WHERE giveaway_id = $1 ORDER BY id ASC", giveawayID)
+	if err != nil {
+		return nil, err
+	}
+	return scanPrizeDeliveries(rows)
+}
+
+// GetPrizeDelivery returns a delivery by ID, or nil if it doesn't exist
+func (d *Database) GetPrizeDelivery(id int64) (*models.PrizeDelivery, error) {
+	p := &models.PrizeDelivery{}
+	err := d.db.QueryRow("SELECT "+prizeDeliveryColumns+" FROM prize_deliveries WHERE id = $1", id).Scan(
+		&p.ID, &p.GiveawayID, &p.UserID, &p.Kind, &p.Status, &p.Detail, &p.ExpiresAt, &p.CreatedAt, &p.AlreadyHeld)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// GetTemporaryRoleDeliveries returns delivered roles that still have to be taken back
+func (d *Database) GetTemporaryRoleDeliveries() ([]*models.PrizeDelivery, error) {
+	rows, err := d.db.Query("SELECT "+prizeDeliveryColumns+" FROM prize_deliveries WHERE kind = $1 AND status = $2 AND expires_at > 0",
+		models.PrizeKindRole, models.DeliveryStatusDelivered)
+	if err != nil {
+		return nil, err
+	}
+	return scanPrizeDeliveries(rows)
+}
+
+// ExpirePrizeDelivery marks a temporary role as removed. Returns false if it already was.
+func (d *Database) ExpirePrizeDelivery(id int64) (bool, error) {
+	res, err := d.db.Exec("UPDATE prize_deliveries SET status = $1 WHERE id = $2 AND status = $3",
+		models.DeliveryStatusExpired, id, models.DeliveryStatusDelivered)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func scanPrizeDeliveries(rows *sql.Rows) ([]*models.PrizeDelivery, error) {
+	defer rows.Close()
+
+	var deliveries []*models.PrizeDelivery
+	for rows.Next() {
+		p := &models.PrizeDelivery{}
+		if err := rows.Scan(&p.ID, &p.GiveawayID, &p.UserID, &p.Kind, &p.Status, &p.Detail, &p.ExpiresAt, &p.CreatedAt, &p.AlreadyHeld); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, p)
+	}
+	return deliveries, nil
+}
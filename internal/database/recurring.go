@@ -0,0 +1,106 @@
+package database
+
+import (
+	"database/sql"
+	"discord-giveaway-bot/internal/models"
+
+	"github.com/goccy/go-json"
+)
+
+// Recurring giveaway operations
+
+const recurringColumns = `id, guild_id, channel_id, host_id, schedule, template, COALESCE(ping_role_id, ''),
+	max_occurrences, end_date, occurrences, anchor, active, created_at`
+
+func (d *Database) CreateRecurringGiveaway(r *models.RecurringGiveaway) error {
+	template, err := json.Marshal(r.Template)
+	if err != nil {
+		return err
+	}
+	return d.db.QueryRow(`
+		INSERT INTO recurring_giveaways (guild_id, channel_id, host_id, schedule, template, ping_role_id,
+			max_occurrences, end_date, occurrences, anchor, active, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id
+	`, r.GuildID, r.ChannelID, r.HostID, r.Schedule, string(template), r.PingRoleID,
+		r.MaxOccurrences, r.EndDate, r.Occurrences, r.Anchor, models.BoolToInt(r.Active), r.CreatedAt).Scan(&r.ID)
+}
+
+// GetRecurringGiveaway returns a recurring giveaway, or nil if it doesn't exist
+func (d *Database) GetRecurringGiveaway(id int64) (*models.RecurringGiveaway, error) {
+	r, err := scanRecurring(d.db.QueryRow("SELECT "+recurringColumns+" FROM recurring_giveaways WHERE id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return r, err
+}
+
+// GetActiveRecurringGiveaways returns a guild's running recurring giveaways
+func (d *Database) GetActiveRecurringGiveaways(guildID string) ([]*models.RecurringGiveaway, error) {
+	rows, err := d.db.Query("SELECT "+recurringColumns+" FROM recurring_giveaways WHERE guild_id = $1 AND active = 1 ORDER BY id ASC", guildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recurring []*models.RecurringGiveaway
+	for rows.Next() {
+		r, err := scanRecurring(rows)
+		if err != nil {
+			return nil, err
+		}
+		recurring = append(recurring, r)
+	}
+	return recurring, nil
+}
+
+// IncrementRecurringOccurrences counts an occurrence that has started
+func (d *Database) IncrementRecurringOccurrences(id int64) error {
+	_, err := d.db.Exec("UPDATE recurring_giveaways SET occurrences = occurrences + 1 WHERE id = $1", id)
+	return err
+}
+
+// StopRecurringGiveaway deactivates a recurring giveaway (guildID empty = any guild)
+func (d *Database) StopRecurringGiveaway(id int64, guildID string) (bool, error) {
+	res, err := d.db.Exec("UPDATE recurring_giveaways SET active = 0 WHERE id = $1 AND ($2 = '' OR guild_id = $2) AND active = 1", id, guildID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// DeletePendingOccurrences removes not-yet-posted occurrences and returns their IDs
+func (d *Database) DeletePendingOccurrences(recurringID int64) ([]int64, error) {
+	rows, err := d.db.Query("DELETE FROM giveaways WHERE recurring_id = $1 AND scheduled = 1 RETURNING id", recurringID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func scanRecurring(row rowScanner) (*models.RecurringGiveaway, error) {
+	r := &models.RecurringGiveaway{}
+	var template string
+	var active int
+	err := row.Scan(&r.ID, &r.GuildID, &r.ChannelID, &r.HostID, &r.Schedule, &template, &r.PingRoleID,
+		&r.MaxOccurrences, &r.EndDate, &r.Occurrences, &r.Anchor, &active, &r.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	r.Active = models.IntToBool(active)
+	if err := json.Unmarshal([]byte(template), &r.Template); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
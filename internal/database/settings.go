@@ -2,6 +2,7 @@ package database
 
 import (
 	"database/sql"
+	"discord-giveaway-bot/internal/models"
 )
 
 func (d *Database) GetGuildPrefix(guildID string) (string, error) {
@@ -24,3 +25,23 @@ func (d *Database) SetGuildPrefix(guildID, prefix string) error {
 	_, err := d.db.Exec(query, guildID, prefix)
 	return err
 }
+
+// GetWinLimits returns the guild's win limits (empty if none are set)
+func (d *Database) GetWinLimits(guildID string) (*models.WinLimits, error) {
+	l := &models.WinLimits{}
+	err := d.db.QueryRow("SELECT COALESCE(win_max, 0), COALESCE(win_period, 0), COALESCE(win_cooldown, 0) FROM guild_settings WHERE guild_id = $1",
+		guildID).Scan(&l.MaxWins, &l.Period, &l.Cooldown)
+	if err == sql.ErrNoRows {
+		return l, nil
+	}
+	return l, err
+}
+
+func (d *Database) SetWinLimits(guildID string, l *models.WinLimits) error {
+	query := `
+		INSERT INTO guild_settings (guild_id, win_max, win_period, win_cooldown) VALUES ($1, $2, $3, $4)
+		ON CONFLICT(guild_id) DO UPDATE SET win_max = EXCLUDED.win_max, win_period = EXCLUDED.win_period, win_cooldown = EXCLUDED.win_cooldown
+	`
+	_, err := d.db.Exec(query, guildID, l.MaxWins, l.Period, l.Cooldown)
+	return err
+}
@@ -0,0 +1,79 @@
+package database
+
+import (
+	"database/sql"
+	"discord-giveaway-bot/internal/models"
+
+	"github.com/goccy/go-json"
+)
+
+// Giveaway template operations
+
+// SaveTemplate creates a template or replaces the guild's template with the same name
+func (d *Database) SaveTemplate(t *models.SavedTemplate) error {
+	template, err := json.Marshal(t.Template)
+	if err != nil {
+		return err
+	}
+	return d.db.QueryRow(`
+		INSERT INTO giveaway_templates (guild_id, name, template, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (guild_id, name) DO UPDATE SET template = $3, created_by = $4, created_at = $5
+		RETURNING id
+	`, t.GuildID, t.Name, string(template), t.CreatedBy, t.CreatedAt).Scan(&t.ID)
+}
+
+// GetTemplate returns a guild's template by name, or nil if it doesn't exist
+func (d *Database) GetTemplate(guildID, name string) (*models.SavedTemplate, error) {
+	t, err := scanTemplate(d.db.QueryRow(`
+		SELECT id, guild_id, name, template, created_by, created_at
+		FROM giveaway_templates WHERE guild_id = $1 AND name = $2
+	`, guildID, name))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return t, err
+}
+
+// GetTemplates returns a guild's templates sorted by name
+func (d *Database) GetTemplates(guildID string) ([]*models.SavedTemplate, error) {
+	rows, err := d.db.Query(`
+		SELECT id, guild_id, name, template, created_by, created_at
+		FROM giveaway_templates WHERE guild_id = $1 ORDER BY name ASC
+	`, guildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []*models.SavedTemplate
+	for rows.Next() {
+		t, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	return templates, nil
+}
+
+func (d *Database) DeleteTemplate(guildID, name string) (bool, error) {
+	res, err := d.db.Exec("DELETE FROM giveaway_templates WHERE guild_id = $1 AND name = $2", guildID, name)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func scanTemplate(row rowScanner) (*models.SavedTemplate, error) {
+	t := &models.SavedTemplate{}
+	var template string
+	if err := row.Scan(&t.ID, &t.GuildID, &t.Name, &template, &t.CreatedBy, &t.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(template), &t.Template); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
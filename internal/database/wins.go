@@ -0,0 +1,70 @@
+package database
+
+import (
+	"discord-giveaway-bot/internal/models"
+)
+
+// Win history operations. Wins that expired unclaimed were never really won,
+// so they don't count.
+
+// GetWinTimes returns when each user in the guild won since the given time, newest first
+func (d *Database) GetWinTimes(guildID string, since int64) (map[string][]int64, error) {
+	rows, err := d.db.Query(`
+		SELECT w.user_id, w.won_at
+		FROM winners w
+		JOIN giveaways g ON g.id = w.giveaway_id
+		WHERE g.guild_id = $1 AND w.won_at >= $2 AND COALESCE(w.status, 'won') <> $3
+		ORDER BY w.won_at DESC
+	`, guildID, since, models.WinnerStatusExpired)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	wins := make(map[string][]int64)
+	for rows.Next() {
+		var userID string
+		var wonAt int64
+		if err := rows.Scan(&userID, &wonAt); err != nil {
+			return nil, err
+		}
+		wins[userID] = append(wins[userID], wonAt)
+	}
+	return wins, nil
+}
+
+// GetUserWins returns a user's latest wins in the guild and their total number of wins
+func (d *Database) GetUserWins(guildID, userID string, limit int) ([]*models.WinRecord, int, error) {
+	var total int
+	err := d.db.QueryRow(`
+		SELECT COUNT(*) FROM winners w
+		JOIN giveaways g ON g.id = w.giveaway_id
+		WHERE g.guild_id = $1 AND w.user_id = $2 AND COALESCE(w.status, 'won') <> $3
+	`, guildID, userID, models.WinnerStatusExpired).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := d.db.Query(`
+		SELECT g.id, g.message_id, g.channel_id, g.prize, COALESCE(w.status, 'won'), w.won_at
+		FROM winners w
+		JOIN giveaways g ON g.id = w.giveaway_id
+		WHERE g.guild_id = $1 AND w.user_id = $2
+		ORDER BY w.won_at DESC
+		LIMIT $3
+	`, guildID, userID, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var wins []*models.WinRecord
+	for rows.Next() {
+		w := &models.WinRecord{}
+		if err := rows.Scan(&w.GiveawayID, &w.MessageID, &w.ChannelID, &w.Prize, &w.Status, &w.WonAt); err != nil {
+			return nil, 0, err
+		}
+		wins = append(wins, w)
+	}
+	return wins, total, nil
+}
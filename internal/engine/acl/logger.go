@@ -139,22 +139,35 @@ func sendToDiscord(entries []LogEntry) {
 		}
 	}
 
-	// Send to each guild's log channel
+	// Send to each guild's log channel, falling back to the secondary channel /
+	// outbound webhook if the primary was never set or has been deleted
 	for guildID, guildLogs := range guildEntries {
 		channelID := GetGuildLogChannel(guildID)
 		if channelID == "" {
-			continue // No log channel configured for this guild
+			deliverLogFallback(guildID, guildLogs)
+			continue
+		}
+		if err := sendToChannel(channelID, guildLogs); err != nil {
+			deliverLogFallback(guildID, guildLogs)
 		}
-		sendToChannel(channelID, guildLogs)
 	}
 }
 
 // sendToChannel sends logs to a specific channel
-func sendToChannel(channelID string, entries []LogEntry) {
+func sendToChannel(channelID string, entries []LogEntry) error {
 	if len(entries) == 0 {
-		return
+		return nil
 	}
 
+	_, err := discordSess.ChannelMessageSendEmbed(channelID, buildLogEmbed(entries))
+	if err != nil {
+		fmt.Printf("[LOGGER] Failed to send to Discord channel %s: %v\n", channelID, err)
+	}
+	return err
+}
+
+// buildLogEmbed renders a batch of log entries as a single embed
+func buildLogEmbed(entries []LogEntry) *discordgo.MessageEmbed {
 	// Build embed
 	var description strings.Builder
 	for i, entry := range entries {
@@ -173,7 +186,7 @@ func sendToChannel(channelID string, entries []LogEntry) {
 		description.WriteString("\n")
 	}
 
-	embed := &discordgo.MessageEmbed{
+	return &discordgo.MessageEmbed{
 		Title:       "🛡️ AntiNuke Detection Log",
 		Description: description.String(),
 		Color:       getColorForLevel(entries[0].Level),
@@ -182,11 +195,6 @@ func sendToChannel(channelID string, entries []LogEntry) {
 			Text: fmt.Sprintf("%d events logged", len(entries)),
 		},
 	}
-
-	_, err := discordSess.ChannelMessageSendEmbed(channelID, embed)
-	if err != nil {
-		fmt.Printf("[LOGGER] Failed to send to Discord channel %s: %v\n", channelID, err)
-	}
 }
 
 func getEmojiForLevel(level string) string {
@@ -0,0 +1,333 @@
+package acl
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/goccy/go-json"
+)
+
+// Escalation notifications.
+// Every punishment is DM'd to the guild owner and any configured extra owners so
+// an attacker deleting the log channel can't blind the server staff. Punishments
+// are grouped into incidents; after the first alert of an incident, further
+// punishments are merged into a single update at most once per NotifyMergeInterval.
+
+const (
+	// IncidentIdleTimeout closes an incident after this long without a new punishment
+	IncidentIdleTimeout = 5 * time.Minute
+	// NotifyMergeInterval is the minimum gap between two alerts for the same incident
+	NotifyMergeInterval = 30 * time.Second
+
+	maxIncidentLines = 15
+)
+
+// NotifyConfig holds the escalation settings for a guild
+type NotifyConfig struct {
+	NotifyOwner     bool     // DM the guild owner on every punishment
+	ExtraOwners     []string // Additional user IDs that receive the same DMs
+	FallbackChannel string   // Secondary log channel used when the primary is gone
+	WebhookURL      string   // Outbound webhook used when no log channel is reachable
+}
+
+// incident aggregates punishments in a guild until it goes quiet
+type incident struct {
+	ID        string
+	Started   time.Time
+	LastEvent time.Time
+	LastSent  time.Time
+	Total     int
+	Alerts    int
+	Pending   []string
+	Scheduled bool
+}
+
+var (
+	notifyConfigs     = make(map[string]NotifyConfig)
+	notifyConfigsLock sync.RWMutex
+
+	incidents     = make(map[string]*incident)
+	incidentsLock sync.Mutex
+
+	webhookClient = &http.Client{Timeout: 5 * time.Second}
+)
+
+// discordWebhookHosts serve Discord webhooks under /api/webhooks/
+var discordWebhookHosts = map[string]bool{
+	"discord.com":        true,
+	"discordapp.com":     true,
+	"canary.discord.com": true,
+	"ptb.discord.com":    true,
+}
+
+// extraWebhookHosts are non-Discord hosts an alert webhook may point at
+var extraWebhookHosts = make(map[string]bool)
+
+// SetAlertWebhookHosts allows alert webhooks on additional hosts (config
+// alert_webhook_hosts). Call it before the bot starts.
+func SetAlertWebhookHosts(hosts []string) {
+	for _, h := range hosts {
+		extraWebhookHosts[strings.ToLower(strings.TrimSpace(h))] = true
+	}
+}
+
+// ValidateAlertWebhook checks that an alert webhook is an https Discord
+// webhook or points at a host allowed in the config, so guild admins can't
+// make the bot post to arbitrary addresses
+func ValidateAlertWebhook(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" || u.User != nil {
+		return fmt.Errorf("webhook URL must be a valid `https://` URL")
+	}
+	host := strings.ToLower(u.Host)
+	if discordWebhookHosts[host] {
+		if !strings.HasPrefix(u.Path, "/api/webhooks/") && !strings.HasPrefix(u.Path, "/api/v10/webhooks/") {
+			return fmt.Errorf("webhook URL must be a Discord webhook (`https://discord.com/api/webhooks/...`)")
+		}
+		return nil
+	}
+	if extraWebhookHosts[host] {
+		return nil
+	}
+	return fmt.Errorf("webhook URL must be a Discord webhook (`https://discord.com/api/webhooks/...`)")
+}
+
+// SetGuildNotifyConfig replaces the escalation settings for a guild
+func SetGuildNotifyConfig(guildID string, cfg NotifyConfig) {
+	notifyConfigsLock.Lock()
+	notifyConfigs[guildID] = cfg
+	notifyConfigsLock.Unlock()
+}
+
+// GetGuildNotifyConfig returns the escalation settings for a guild.
+// Guilds without stored settings still notify the owner.
+func GetGuildNotifyConfig(guildID string) NotifyConfig {
+	notifyConfigsLock.RLock()
+	defer notifyConfigsLock.RUnlock()
+	cfg, ok := notifyConfigs[guildID]
+	if !ok {
+		return NotifyConfig{NotifyOwner: true}
+	}
+	return cfg
+}
+
+// notifyPunishment records a punishment (or a failed attempt) against the guild's
+// current incident and sends or schedules the owner alert
+func notifyPunishment(guildID, userID, punishType, reason string, execErr error) {
+	if discordSess == nil || guildID == "" {
+		return
+	}
+
+	line := fmt.Sprintf("`%s` <@%s> (%s)", punishType, userID, userID)
+	if execErr != nil {
+		line = fmt.Sprintf("❌ `%s` FAILED on <@%s> (%s): %v", punishType, userID, userID, execErr)
+	}
+	if reason != "" {
+		line += " — " + reason
+	}
+	line += fmt.Sprintf(" <t:%d:T>", time.Now().Unix())
+
+	now := time.Now()
+
+	incidentsLock.Lock()
+	inc, ok := incidents[guildID]
+	if !ok || now.Sub(inc.LastEvent) > IncidentIdleTimeout {
+		inc = &incident{
+			ID:      fmt.Sprintf("%s-%d", guildID, now.Unix()),
+			Started: now,
+		}
+		incidents[guildID] = inc
+	}
+	inc.LastEvent = now
+	inc.Total++
+	inc.Pending = append(inc.Pending, line)
+
+	if inc.Scheduled {
+		incidentsLock.Unlock()
+		return
+	}
+
+	wait := NotifyMergeInterval - now.Sub(inc.LastSent)
+	if inc.LastSent.IsZero() || wait <= 0 {
+		incidentsLock.Unlock()
+		flushIncident(guildID, inc)
+		return
+	}
+
+	inc.Scheduled = true
+	incidentsLock.Unlock()
+	time.AfterFunc(wait, func() { flushIncident(guildID, inc) })
+}
+
+// flushIncident sends everything queued on the incident as one alert
+func flushIncident(guildID string, inc *incident) {
+	incidentsLock.Lock()
+	lines := inc.Pending
+	inc.Pending = nil
+	inc.Scheduled = false
+	if len(lines) == 0 {
+		incidentsLock.Unlock()
+		return
+	}
+	inc.LastSent = time.Now()
+	inc.Alerts++
+	first := inc.Alerts == 1
+	total := inc.Total
+	started := inc.Started
+	incidentID := inc.ID
+	incidentsLock.Unlock()
+
+	embed := buildIncidentEmbed(guildID, incidentID, started, lines, total, first)
+	sendOwnerAlerts(guildID, embed)
+
+	// Keep the map from growing with guilds that had a single incident long ago
+	pruneIncidents()
+}
+
+func buildIncidentEmbed(guildID, incidentID string, started time.Time, lines []string, total int, first bool) *discordgo.MessageEmbed {
+	guildName := guildID
+	if discordSess.State != nil {
+		if g, err := discordSess.State.Guild(guildID); err == nil && g.Name != "" {
+			guildName = g.Name
+		}
+	}
+
+	title := "🚨 AntiNuke Incident"
+	if !first {
+		title = "🚨 AntiNuke Incident Update"
+	}
+
+	var sb strings.Builder
+	for i, l := range lines {
+		if i >= maxIncidentLines {
+			sb.WriteString(fmt.Sprintf("*...and %d more*\n", len(lines)-maxIncidentLines))
+			break
+		}
+		sb.WriteString("• " + l + "\n")
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       title,
+		Description: fmt.Sprintf("Punishments were executed in **%s**.\n\n%s", guildName, sb.String()),
+		Color:       0xFF0000,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Incident", Value: fmt.Sprintf("`%s`", incidentID), Inline: true},
+			{Name: "Started", Value: fmt.Sprintf("<t:%d:R>", started.Unix()), Inline: true},
+			{Name: "Total Punishments", Value: fmt.Sprintf("%d", total), Inline: true},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Repeated alerts are merged every %v while the incident is active", NotifyMergeInterval),
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+}
+
+// sendOwnerAlerts DMs the owner and extra owners
+func sendOwnerAlerts(guildID string, embed *discordgo.MessageEmbed) {
+	cfg := GetGuildNotifyConfig(guildID)
+
+	recipients := make([]string, 0, len(cfg.ExtraOwners)+1)
+	seen := make(map[string]struct{})
+	add := func(id string) {
+		if id == "" {
+			return
+		}
+		if _, ok := seen[id]; ok {
+			return
+		}
+		seen[id] = struct{}{}
+		recipients = append(recipients, id)
+	}
+
+	if cfg.NotifyOwner {
+		add(guildOwnerID(guildID))
+	}
+	for _, id := range cfg.ExtraOwners {
+		add(id)
+	}
+
+	for _, userID := range recipients {
+		dm, err := discordSess.UserChannelCreate(userID)
+		if err != nil {
+			log.Printf("[NOTIFY] Failed to open DM with %s: %v", userID, err)
+			continue
+		}
+		if _, err := discordSess.ChannelMessageSendEmbed(dm.ID, embed); err != nil {
+			log.Printf("[NOTIFY] Failed to DM %s: %v", userID, err)
+		}
+	}
+}
+
+func guildOwnerID(guildID string) string {
+	if discordSess.State != nil {
+		if g, err := discordSess.State.Guild(guildID); err == nil && g.OwnerID != "" {
+			return g.OwnerID
+		}
+	}
+	g, err := discordSess.Guild(guildID)
+	if err != nil {
+		return ""
+	}
+	return g.OwnerID
+}
+
+func pruneIncidents() {
+	incidentsLock.Lock()
+	defer incidentsLock.Unlock()
+	for id, inc := range incidents {
+		if !inc.Scheduled && time.Since(inc.LastEvent) > IncidentIdleTimeout {
+			delete(incidents, id)
+		}
+	}
+}
+
+// deliverLogFallback is used by the logger when the primary log channel is missing
+// or unreachable: try the secondary channel first, then the outbound webhook
+func deliverLogFallback(guildID string, entries []LogEntry) {
+	cfg := GetGuildNotifyConfig(guildID)
+
+	if cfg.FallbackChannel != "" && cfg.FallbackChannel != GetGuildLogChannel(guildID) {
+		if err := sendToChannel(cfg.FallbackChannel, entries); err == nil {
+			return
+		}
+	}
+
+	if cfg.WebhookURL != "" {
+		if err := postWebhook(cfg.WebhookURL, buildLogEmbed(entries)); err != nil {
+			log.Printf("[NOTIFY] Webhook delivery failed for guild %s: %v", guildID, err)
+		}
+	}
+}
+
+// postWebhook sends an embed to an outbound webhook URL (Discord-compatible payload)
+func postWebhook(url string, embed *discordgo.MessageEmbed) error {
+	// Stored URLs may predate the host check
+	if err := ValidateAlertWebhook(url); err != nil {
+		return err
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"username": "AntiNuke",
+		"embeds":   []*discordgo.MessageEmbed{embed},
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := webhookClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package acl
 
 import (
+	"discord-giveaway-bot/internal/metrics"
 	"fmt"
 	"log"
 	"runtime"
@@ -51,6 +52,11 @@ var idBufferPool = sync.Pool{
 	},
 }
 
+// QueueDepths returns the current length and capacity of the standard and fast-ban queues
+func QueueDepths() (punish, punishCap, fastBan, fastBanCap int) {
+	return len(punishQueue), cap(punishQueue), len(fastBanQueue), cap(fastBanQueue)
+}
+
 // InitPunishWorker initializes the punishment worker with Discord session
 func InitPunishWorker(session *discordgo.Session) {
 	discordSession = session
@@ -108,8 +114,8 @@ func PushPunish(task PunishTask) {
 	select {
 	case punishQueue <- task:
 	default:
-		// ACL Overload - Drop or log error to atomic counter
-		// log.Printf("[ACL] WARNING: Punishment queue full, dropping task for user %d", task.UserID)
+		// ACL Overload - dropped, counted so overload shows up in the metrics
+		metrics.PunishmentsTotal.Inc(task.Type, "dropped")
 	}
 }
 
@@ -256,7 +262,8 @@ func executePunishment(task PunishTask) {
 
 	case "QUARANTINE":
 		// Remove all roles from the user concurrently
-		member, err := discordSession.GuildMember(guildID, userID)
+		var member *discordgo.Member
+		member, err = discordSession.GuildMember(guildID, userID)
 		if err == nil {
 			var wg sync.WaitGroup
 			for _, roleID := range member.Roles {
@@ -284,6 +291,17 @@ func executePunishment(task PunishTask) {
 		return
 	}
 
+	// Export to /metrics
+	if task.DetectionTime > 0 {
+		metrics.DetectionLatency.ObserveDuration(task.DetectionTime)
+	}
+	metrics.ExecutionLatency.ObserveDuration(time.Since(start))
+	if err != nil {
+		metrics.PunishmentsTotal.Inc(task.Type, "failure")
+	} else {
+		metrics.PunishmentsTotal.Inc(task.Type, "success")
+	}
+
 	if err != nil {
 		log.Printf("[ACL] Failed to execute %s on user %d: %v", task.Type, task.UserID, err)
 		go PushLogEntry(LogEntry{
@@ -293,5 +311,13 @@ func executePunishment(task PunishTask) {
 			UserID:  userID,
 			Action:  task.Type,
 		})
+		go notifyPunishment(guildID, userID, task.Type, task.Reason, err)
+		return
 	}
+
+	// Escalate to the owner / extra owners (merged per incident)
+	go notifyPunishment(guildID, userID, task.Type, task.Reason, nil)
+
+	// Tear down any webhooks the executor planted during the incident
+	go cleanupExecutorWebhooks(guildID, userID)
 }
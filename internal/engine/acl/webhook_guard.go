@@ -0,0 +1,253 @@
+package acl
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// WebhookIncidentWindow is how far back we look for webhooks (and their messages)
+// created or modified by an executor once that executor gets punished
+const WebhookIncidentWindow = 10 * time.Minute
+
+// webhookTouch records the last create/update of a webhook by an executor
+type webhookTouch struct {
+	ExecutorID string
+	At         time.Time
+}
+
+var (
+	// guildID -> webhookID -> last touch
+	webhookActivity     = make(map[string]map[string]webhookTouch)
+	webhookActivityLock sync.Mutex
+
+	// guildID -> set of application/integration IDs whose webhooks are always permitted
+	webhookAllowlist     = make(map[string]map[string]struct{})
+	webhookAllowlistLock sync.RWMutex
+)
+
+// TrackWebhookActivity remembers that executorID created or modified webhookID.
+// Called from the auditor for WEBHOOK_CREATE / WEBHOOK_UPDATE audit entries.
+func TrackWebhookActivity(guildID, executorID, webhookID string) {
+	if guildID == "" || executorID == "" || webhookID == "" {
+		return
+	}
+
+	now := time.Now()
+
+	webhookActivityLock.Lock()
+	defer webhookActivityLock.Unlock()
+
+	hooks, ok := webhookActivity[guildID]
+	if !ok {
+		hooks = make(map[string]webhookTouch)
+		webhookActivity[guildID] = hooks
+	}
+
+	// Prune stale entries while we hold the lock
+	for id, t := range hooks {
+		if now.Sub(t.At) > WebhookIncidentWindow {
+			delete(hooks, id)
+		}
+	}
+
+	hooks[webhookID] = webhookTouch{ExecutorID: executorID, At: now}
+}
+
+// SetWebhookAllowlist replaces the webhook allowlist for a guild
+func SetWebhookAllowlist(guildID string, appIDs []string) {
+	set := make(map[string]struct{}, len(appIDs))
+	for _, id := range appIDs {
+		set[id] = struct{}{}
+	}
+
+	webhookAllowlistLock.Lock()
+	webhookAllowlist[guildID] = set
+	webhookAllowlistLock.Unlock()
+}
+
+// AllowWebhookApp adds an application/integration ID to a guild's webhook allowlist
+func AllowWebhookApp(guildID, appID string) {
+	webhookAllowlistLock.Lock()
+	defer webhookAllowlistLock.Unlock()
+	set, ok := webhookAllowlist[guildID]
+	if !ok {
+		set = make(map[string]struct{})
+		webhookAllowlist[guildID] = set
+	}
+	set[appID] = struct{}{}
+}
+
+// DisallowWebhookApp removes an application/integration ID from a guild's webhook allowlist
+func DisallowWebhookApp(guildID, appID string) {
+	webhookAllowlistLock.Lock()
+	defer webhookAllowlistLock.Unlock()
+	if set, ok := webhookAllowlist[guildID]; ok {
+		delete(set, appID)
+	}
+}
+
+// isWebhookAllowed reports whether a webhook belongs to an allowlisted integration
+func isWebhookAllowed(guildID string, wh *discordgo.Webhook) bool {
+	webhookAllowlistLock.RLock()
+	defer webhookAllowlistLock.RUnlock()
+
+	set := webhookAllowlist[guildID]
+	if len(set) == 0 {
+		return false
+	}
+	if wh.ApplicationID != "" {
+		if _, ok := set[wh.ApplicationID]; ok {
+			return true
+		}
+	}
+	// Bot-created webhooks carry the bot user, whose ID matches the application ID
+	if wh.User != nil {
+		if _, ok := set[wh.User.ID]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// executorWebhooks returns the webhook IDs touched by executorID inside the incident window
+func executorWebhooks(guildID, executorID string) map[string]webhookTouch {
+	cutoff := time.Now().Add(-WebhookIncidentWindow)
+	result := make(map[string]webhookTouch)
+
+	webhookActivityLock.Lock()
+	defer webhookActivityLock.Unlock()
+
+	for id, t := range webhookActivity[guildID] {
+		if t.ExecutorID == executorID && t.At.After(cutoff) {
+			result[id] = t
+		}
+	}
+	return result
+}
+
+// cleanupExecutorWebhooks deletes every webhook the punished executor created or
+// modified during the incident window and purges the messages those webhooks posted.
+// Runs after a successful punishment, off the hot path.
+func cleanupExecutorWebhooks(guildID, executorID string) {
+	if discordSession == nil {
+		return
+	}
+
+	start := time.Now()
+	cutoff := start.Add(-WebhookIncidentWindow)
+	touched := executorWebhooks(guildID, executorID)
+
+	hooks, err := discordSession.GuildWebhooks(guildID)
+	if err != nil {
+		log.Printf("[ACL] Webhook cleanup: failed to list webhooks for guild %s: %v", guildID, err)
+		return
+	}
+
+	// channelID -> webhook IDs removed from that channel
+	removed := make(map[string]map[string]struct{})
+	skipped := 0
+	reason := fmt.Sprintf("AntiNuke: webhook created by punished user %s", executorID)
+
+	for _, wh := range hooks {
+		_, wasTouched := touched[wh.ID]
+		if !wasTouched {
+			// Fall back to the creator + snowflake age when the audit entry was missed
+			if wh.User == nil || wh.User.ID != executorID {
+				continue
+			}
+			created, err := discordgo.SnowflakeTimestamp(wh.ID)
+			if err != nil || created.Before(cutoff) {
+				continue
+			}
+		}
+
+		if isWebhookAllowed(guildID, wh) {
+			skipped++
+			continue
+		}
+
+		if err := discordSession.WebhookDelete(wh.ID, discordgo.WithAuditLogReason(reason)); err != nil {
+			log.Printf("[ACL] Webhook cleanup: failed to delete webhook %s: %v", wh.ID, err)
+			continue
+		}
+
+		if removed[wh.ChannelID] == nil {
+			removed[wh.ChannelID] = make(map[string]struct{})
+		}
+		removed[wh.ChannelID][wh.ID] = struct{}{}
+	}
+
+	if len(removed) == 0 {
+		return
+	}
+
+	purged := 0
+	webhookCount := 0
+	for channelID, ids := range removed {
+		webhookCount += len(ids)
+		purged += purgeWebhookMessages(channelID, ids, cutoff)
+	}
+
+	executionTime := time.Since(start)
+	log.Printf("[ACL] 🪝 WEBHOOK CLEANUP | User %s | Webhooks: %d | Messages: %d | Allowlisted: %d | Execution: %v",
+		executorID, webhookCount, purged, skipped, executionTime)
+
+	PushLogEntry(LogEntry{
+		Message: fmt.Sprintf("Deleted %d webhook(s) and %d message(s) from user %s", webhookCount, purged, executorID),
+		Level:   "warn",
+		GuildID: guildID,
+		UserID:  executorID,
+		Action:  "WEBHOOK_CLEANUP",
+		Latency: executionTime,
+	})
+}
+
+// purgeWebhookMessages bulk-deletes recent messages in channelID posted by any of the given webhooks
+func purgeWebhookMessages(channelID string, webhookIDs map[string]struct{}, cutoff time.Time) int {
+	purged := 0
+	before := ""
+
+	// Walk back at most 500 messages, stopping once we're past the incident window
+	for page := 0; page < 5; page++ {
+		msgs, err := discordSession.ChannelMessages(channelID, 100, before, "", "")
+		if err != nil || len(msgs) == 0 {
+			break
+		}
+
+		var ids []string
+		reachedCutoff := false
+		for _, m := range msgs {
+			if m.Timestamp.Before(cutoff) {
+				reachedCutoff = true
+				break
+			}
+			if _, ok := webhookIDs[m.WebhookID]; ok {
+				ids = append(ids, m.ID)
+			}
+		}
+
+		switch {
+		case len(ids) == 1:
+			if err := discordSession.ChannelMessageDelete(channelID, ids[0]); err == nil {
+				purged++
+			}
+		case len(ids) > 1:
+			if err := discordSession.ChannelMessagesBulkDelete(channelID, ids); err == nil {
+				purged += len(ids)
+			} else {
+				log.Printf("[ACL] Webhook cleanup: bulk delete failed in channel %s: %v", channelID, err)
+			}
+		}
+
+		if reachedCutoff || len(msgs) < 100 {
+			break
+		}
+		before = msgs[len(msgs)-1].ID
+	}
+
+	return purged
+}
@@ -1,6 +1,7 @@
 package auditor
 
 import (
+	"discord-giveaway-bot/internal/engine/acl"
 	"discord-giveaway-bot/internal/engine/cde"
 	"discord-giveaway-bot/internal/engine/fdl"
 	"discord-giveaway-bot/internal/engine/ring"
@@ -65,6 +66,11 @@ func (h *EventHandlers) OnGuildAuditLogEntryCreate(s *discordgo.Session, e *disc
 		reqType = fdl.EvtRoleDelete
 	case discordgo.AuditLogActionWebhookCreate:
 		reqType = fdl.EvtWebhookCreate
+		acl.TrackWebhookActivity(e.GuildID, e.UserID, e.TargetID)
+	case discordgo.AuditLogActionWebhookUpdate:
+		// Only remembered for cleanup, webhook edits aren't a detection trigger
+		acl.TrackWebhookActivity(e.GuildID, e.UserID, e.TargetID)
+		return
 	case discordgo.AuditLogActionChannelCreate:
 		reqType = fdl.EvtChannelCreate
 	case discordgo.AuditLogActionRoleCreate:
@@ -95,6 +101,8 @@ func (h *EventHandlers) OnGuildAuditLogEntryCreate(s *discordgo.Session, e *disc
 		DetectionStart: startNano,
 	}
 
+	fdl.TotalEvents.Inc(evt.UserID)
+
 	// 4. Push to Ring Buffer (Lock-free / High Perf)
 	// Inlined Push logic would be faster but we use the method for safety
 	if !h.eventRing.Push(&evt) {
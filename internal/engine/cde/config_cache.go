@@ -12,6 +12,10 @@ import (
 var dbInstance *database.Database
 var configMutex sync.RWMutex
 
+// Set once the initial guild config load has finished (for /readyz)
+var configsLoaded atomic.Bool
+var configsLoadedCount atomic.Int64
+
 // InitCDE initializes the CDE with database connection
 func InitCDE(db *database.Database) {
 	dbInstance = db
@@ -89,6 +93,17 @@ func LoadGuildConfig(guildID uint64) error {
 	return nil
 }
 
+// MarkConfigsLoaded records that the startup config load has completed
+func MarkConfigsLoaded(count int) {
+	configsLoadedCount.Store(int64(count))
+	configsLoaded.Store(true)
+}
+
+// ConfigsLoaded reports whether guild configs have been loaded and how many
+func ConfigsLoaded() (bool, int) {
+	return configsLoaded.Load(), int(configsLoadedCount.Load())
+}
+
 // IsAntiNukeEnabled checks if antinuke is enabled for a guild (ATOMIC FAST PATH)
 func IsAntiNukeEnabled(guildID uint64) bool {
 	idx := hashGuild(guildID)
@@ -0,0 +1,253 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Minimal Prometheus text-format exporter.
+// Counters/histograms are lock-free on the write path so they are safe to
+// touch from the ACL workers; everything else is pulled at scrape time via
+// registered funcs.
+
+type collector interface {
+	write(w *bufio.Writer)
+}
+
+var (
+	registry     []collector
+	registryLock sync.Mutex
+)
+
+func register(c collector) {
+	registryLock.Lock()
+	registry = append(registry, c)
+	registryLock.Unlock()
+}
+
+// ============================================================================
+// Counter vectors
+// ============================================================================
+
+// CounterVec is a counter partitioned by label values
+type CounterVec struct {
+	name   string
+	help   string
+	labels []string
+	values sync.Map // joined label values -> *uint64
+}
+
+// NewCounterVec creates and registers a labelled counter
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{name: name, help: help, labels: labels}
+	register(c)
+	return c
+}
+
+// Inc increments the counter for the given label values
+func (c *CounterVec) Inc(labelValues ...string) {
+	key := strings.Join(labelValues, "\xff")
+	v, ok := c.values.Load(key)
+	if !ok {
+		v, _ = c.values.LoadOrStore(key, new(uint64))
+	}
+	atomic.AddUint64(v.(*uint64), 1)
+}
+
+func (c *CounterVec) write(w *bufio.Writer) {
+	writeHeader(w, c.name, c.help, "counter")
+
+	var keys []string
+	c.values.Range(func(k, _ interface{}) bool {
+		keys = append(keys, k.(string))
+		return true
+	})
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v, _ := c.values.Load(k)
+		fmt.Fprintf(w, "%s%s %d\n", c.name, formatLabels(c.labels, strings.Split(k, "\xff")), atomic.LoadUint64(v.(*uint64)))
+	}
+}
+
+// ============================================================================
+// Histograms
+// ============================================================================
+
+// Histogram tracks observations in cumulative buckets (seconds)
+type Histogram struct {
+	name    string
+	help    string
+	buckets []float64
+	counts  []uint64 // one per bucket, +Inf is count
+	count   uint64
+	sumBits uint64 // float64 bits, updated via CAS
+}
+
+// NewHistogram creates and registers a histogram with the given upper bounds
+func NewHistogram(name, help string, buckets []float64) *Histogram {
+	h := &Histogram{
+		name:    name,
+		help:    help,
+		buckets: buckets,
+		counts:  make([]uint64, len(buckets)),
+	}
+	register(h)
+	return h
+}
+
+// Observe records a value in seconds
+func (h *Histogram) Observe(v float64) {
+	for i, b := range h.buckets {
+		if v <= b {
+			atomic.AddUint64(&h.counts[i], 1)
+			break
+		}
+	}
+	atomic.AddUint64(&h.count, 1)
+
+	for {
+		old := atomic.LoadUint64(&h.sumBits)
+		next := math.Float64bits(math.Float64frombits(old) + v)
+		if atomic.CompareAndSwapUint64(&h.sumBits, old, next) {
+			break
+		}
+	}
+}
+
+// ObserveDuration records a duration
+func (h *Histogram) ObserveDuration(d time.Duration) {
+	h.Observe(d.Seconds())
+}
+
+func (h *Histogram) write(w *bufio.Writer) {
+	writeHeader(w, h.name, h.help, "histogram")
+
+	var cumulative uint64
+	for i, b := range h.buckets {
+		cumulative += atomic.LoadUint64(&h.counts[i])
+		fmt.Fprintf(w, "%s_bucket{le=\"%s\"} %d\n", h.name, formatFloat(b), cumulative)
+	}
+	count := atomic.LoadUint64(&h.count)
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", h.name, count)
+	fmt.Fprintf(w, "%s_sum %s\n", h.name, formatFloat(math.Float64frombits(atomic.LoadUint64(&h.sumBits))))
+	fmt.Fprintf(w, "%s_count %d\n", h.name, count)
+}
+
+// ============================================================================
+// Pull-based gauges and counters
+// ============================================================================
+
+type funcCollector struct {
+	name  string
+	help  string
+	kind  string
+	value func() float64
+}
+
+func (f *funcCollector) write(w *bufio.Writer) {
+	writeHeader(w, f.name, f.help, f.kind)
+	fmt.Fprintf(w, "%s %s\n", f.name, formatFloat(f.value()))
+}
+
+// GaugeFunc registers a gauge whose value is read at scrape time
+func GaugeFunc(name, help string, fn func() float64) {
+	register(&funcCollector{name: name, help: help, kind: "gauge", value: fn})
+}
+
+// CounterFunc registers a monotonically increasing value read at scrape time
+func CounterFunc(name, help string, fn func() float64) {
+	register(&funcCollector{name: name, help: help, kind: "counter", value: fn})
+}
+
+// ============================================================================
+// Exposition
+// ============================================================================
+
+// Handler serves every registered metric in Prometheus text format
+func Handler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+		registryLock.Lock()
+		collectors := make([]collector, len(registry))
+		copy(collectors, registry)
+		registryLock.Unlock()
+
+		w := bufio.NewWriter(rw)
+		for _, c := range collectors {
+			c.write(w)
+		}
+		w.Flush()
+	})
+}
+
+func writeHeader(w *bufio.Writer, name, help, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, kind)
+}
+
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i, n := range names {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		v := ""
+		if i < len(values) {
+			v = values[i]
+		}
+		v = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+		fmt.Fprintf(&sb, "%s=\"%s\"", n, v)
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return fmt.Sprintf("%g", v)
+}
+
+// ============================================================================
+// Shared metrics
+// ============================================================================
+
+var (
+	// PunishmentsTotal counts ACL punishments by type (BAN/KICK/...) and result (success/failure/dropped)
+	PunishmentsTotal = NewCounterVec("antinuke_punishments_total", "Punishments executed or dropped by the ACL, by type and result.", "type", "result")
+
+	// DetectionLatency is the time from audit log receipt to the CDE verdict
+	DetectionLatency = NewHistogram("antinuke_detection_latency_seconds", "Time from event ingestion to detection.",
+		[]float64{0.000001, 0.0000025, 0.000005, 0.00001, 0.000025, 0.00005, 0.0001, 0.001, 0.01})
+
+	// ExecutionLatency is the time the ACL spent carrying out a punishment
+	ExecutionLatency = NewHistogram("antinuke_execution_latency_seconds", "Time spent executing a punishment against the Discord API.",
+		[]float64{0.025, 0.05, 0.1, 0.15, 0.25, 0.5, 1, 2.5, 5})
+
+	// RESTLatency is observed for every Discord REST call through PerfTransport
+	RESTLatency = NewHistogram("discord_rest_latency_seconds", "Discord REST API round-trip latency.",
+		[]float64{0.025, 0.05, 0.1, 0.15, 0.25, 0.5, 1, 2.5})
+
+	// CommandLatency is observed for every interaction handled by the bot
+	CommandLatency = NewHistogram("bot_command_latency_seconds", "Interaction/command handling latency.",
+		[]float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5})
+)
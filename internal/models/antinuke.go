@@ -11,6 +11,11 @@ type AntiNukeConfig struct {
 	PanicMode   bool   // If true, all limits are set to 1/1s and punishment is Ban
 	CreatedAt   int64
 	UpdatedAt   int64
+
+	// Escalation settings
+	NotifyOwner     bool   // DM the guild owner (and extra owners) on every punishment
+	FallbackChannel string // Secondary log channel used when LogsChannel is gone
+	AlertWebhookURL string // Outbound webhook used when no log channel is reachable
 }
 
 // ActionConfig represents configuration for a specific action type
@@ -36,6 +41,37 @@ type WhitelistEntry struct {
 	CreatedAt  int64
 }
 
+// WebhookAllowEntry represents an integration/application whose webhooks are never cleaned up
+type WebhookAllowEntry struct {
+	ID            int64
+	GuildID       string
+	ApplicationID string
+	AddedBy       string
+	CreatedAt     int64
+}
+
+// ExtraOwner represents a user who receives owner escalation DMs
+type ExtraOwner struct {
+	ID        int64
+	GuildID   string
+	UserID    string
+	AddedBy   string
+	CreatedAt int64
+}
+
+// AntiNukeImport is a batch of antinuke changes written in one transaction.
+// A nil Config leaves antinuke_config untouched; an empty LogsChannel keeps
+// the current one.
+type AntiNukeImport struct {
+	Config          *AntiNukeConfig
+	Actions         []*ActionConfig
+	DisableActions  []string
+	Whitelist       []*WhitelistEntry
+	RemoveWhitelist []string
+	Webhooks        []string
+	RemoveWebhooks  []string
+}
+
 // ActionEvent represents a tracked event for rate limiting
 type ActionEvent struct {
 	ID         int64
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type Giveaway struct {
 	ID            int64  `json:"id"`
@@ -24,27 +27,494 @@ type Giveaway struct {
 	MessageRequired       int    `json:"message_required"`
 	VoiceRequirement      int    `json:"voice_requirement"`
 	EntryFee              int    `json:"entry_fee"`
+	FeeMode               string `json:"fee_mode"` // FeeMode*, what happens to escrowed fees at the end
 
 	// New Features
 	AssignRole string `json:"assign_role"`
 	Thumbnail  string `json:"thumbnail"`
-	Emoji      string `json:"emoji"` // Custom emoji for giveaway reactions
+	Emoji      string `json:"emoji"`      // Custom emoji for giveaway reactions
+	EntryMode  string `json:"entry_mode"` // EntryModeReaction or EntryModeButton
+
+	// Weighted odds
+	Bonus BonusRules `json:"bonus"`
+
+	// Provably fair draws: Commitment = sha256(Secret) is published at start,
+	// Secret is only revealed (via /gverify) once the giveaway has ended, so it
+	// never goes into JSON (the Redis cache included)
+	DrawSecret     string `json:"-"`
+	DrawCommitment string `json:"draw_commitment"`
+	// CommitmentAtDraw marks giveaways from before commitments existed, whose
+	// secret was only made up at draw time and never published in advance
+	CommitmentAtDraw bool `json:"commitment_at_draw"`
+
+	// Winners must claim within ClaimHours or get rerolled (0 = no claim window)
+	ClaimHours int `json:"claim_hours"`
+
+	// Paused giveaways keep their remaining time (ms) and are out of the ending queue
+	PausedAt        int64 `json:"paused_at"`
+	PausedRemaining int64 `json:"paused_remaining"`
+
+	// Scheduled giveaways are stored before they're posted; StartTime is when they go live (ms)
+	Scheduled bool  `json:"scheduled"`
+	StartTime int64 `json:"start_time"`
+
+	// Occurrence of a recurring giveaway (0 = one-off)
+	RecurringID int64 `json:"recurring_id"`
+
+	// Composable requirements, checked together with the single-valued ones above.
+	// Members with a bypass role skip every requirement.
+	Requirements *RequirementGroup `json:"requirements,omitempty"`
+	BypassRoles  []string          `json:"bypass_roles,omitempty"`
+
+	// Delivered to every winner automatically (nil = nothing to deliver)
+	Reward *Reward `json:"reward,omitempty"`
+
+	// Overrides the guild's win limits (nil = use the guild's)
+	WinLimits *WinLimits `json:"win_limits,omitempty"`
 }
 
-type Participant struct {
+// Paused reports whether the giveaway is currently paused
+func (g *Giveaway) Paused() bool {
+	return g.PausedAt > 0
+}
+
+// Reward is what each winner is given automatically, alongside the free-text prize
+type Reward struct {
+	Coins        int64  `json:"coins,omitempty"`
+	ItemID       int64  `json:"item_id,omitempty"` // Shop item, given via Database.GiveItem
+	ItemName     string `json:"item_name,omitempty"`
+	ItemQuantity int    `json:"item_quantity,omitempty"`
+	RoleID       string `json:"role_id,omitempty"`
+	RoleDuration int64  `json:"role_duration,omitempty"` // ms; 0 keeps the role
+}
+
+// Empty reports whether there's nothing to deliver
+func (r *Reward) Empty() bool {
+	return r == nil || (r.Coins <= 0 && r.ItemID == 0 && r.RoleID == "")
+}
+
+// WinLimits stop the same members winning everything: at most MaxWins wins
+// per rolling Period, and no wins at all for Cooldown after winning.
+// Zero values disable a limit.
+type WinLimits struct {
+	MaxWins  int   `json:"max_wins,omitempty"`
+	Period   int64 `json:"period,omitempty"`   // ms
+	Cooldown int64 `json:"cooldown,omitempty"` // ms
+}
+
+// Empty reports whether no limit applies
+func (l *WinLimits) Empty() bool {
+	return l == nil || ((l.MaxWins <= 0 || l.Period <= 0) && l.Cooldown <= 0)
+}
+
+// Lookback is how far back wins matter for these limits
+func (l *WinLimits) Lookback() int64 {
+	if l.MaxWins > 0 && l.Period > l.Cooldown {
+		return l.Period
+	}
+	return l.Cooldown
+}
+
+// BlockedUntil returns when a user with the given wins (newest first) can win
+// again, or 0 if they can win now
+func (l *WinLimits) BlockedUntil(wins []int64, now int64) int64 {
+	var until int64
+	if l.Cooldown > 0 && len(wins) > 0 && wins[0]+l.Cooldown > now {
+		until = wins[0] + l.Cooldown
+	}
+	// The MaxWins-th newest win has to leave the window first
+	if l.MaxWins > 0 && l.Period > 0 && len(wins) >= l.MaxWins {
+		if free := wins[l.MaxWins-1] + l.Period; free > now && free > until {
+			until = free
+		}
+	}
+	return until
+}
+
+// WinRecord is one of a user's wins, with the giveaway it was for
+type WinRecord struct {
+	GiveawayID int64  `json:"giveaway_id"`
+	MessageID  string `json:"message_id"`
+	ChannelID  string `json:"channel_id"`
+	Prize      string `json:"prize"`
+	Status     string `json:"status"` // WinnerStatus*
+	WonAt      int64  `json:"won_at"`
+}
+
+// GiveawaySummary is one row of a guild's giveaway history
+type GiveawaySummary struct {
+	ID           int64    `json:"id"`
+	MessageID    string   `json:"message_id"`
+	ChannelID    string   `json:"channel_id"`
+	HostID       string   `json:"host_id"`
+	Prize        string   `json:"prize"`
+	WinnersCount int      `json:"winners_count"`
+	EndTime      int64    `json:"end_time"`
+	Ended        bool     `json:"ended"`
+	Cancelled    bool     `json:"cancelled"`
+	Participants int      `json:"participants"`
+	Winners      []string `json:"winners"`
+}
+
+// HistoryFilter narrows a guild's giveaway history. Zero values match everything.
+type HistoryFilter struct {
+	HostID string
+	Status string // HistoryStatus*
+	From   int64  // end_time bounds (ms)
+	To     int64
+}
+
+// History statuses
+const (
+	HistoryStatusActive    = "active"
+	HistoryStatusEnded     = "ended" // Ended with a draw
+	HistoryStatusCancelled = "cancelled"
+)
+
+// GiveawayStats are a guild's giveaway totals, optionally since some time
+type GiveawayStats struct {
+	Giveaways     int   `json:"giveaways"`
+	Active        int   `json:"active"`
+	Cancelled     int   `json:"cancelled"`
+	Entries       int   `json:"entries"`  // Participant rows across all giveaways
+	Entrants      int   `json:"entrants"` // Distinct users who entered
+	Winners       int   `json:"winners"`
+	FeesCollected int64 `json:"fees_collected"`
+	FeesKept      int64 `json:"fees_kept"` // Revenue: fees that weren't refunded or paid out
+	Joins         int   `json:"joins"`
+	Leaves        int   `json:"leaves"`
+}
+
+// StatCount is one row of a top-N ranking
+type StatCount struct {
+	UserID string `json:"user_id"`
+	Count  int    `json:"count"`
+}
+
+// Participant events, logged for churn stats
+const (
+	ParticipantJoined = "join"
+	ParticipantLeft   = "leave"
+)
+
+// Entry fee modes. Fees (and bought entries) are held in escrow until the
+// giveaway ends; the mode decides where they go then. Cancelled giveaways
+// always refund everyone.
+const (
+	FeeModeKeep         = "keep"          // Fees are spent (default)
+	FeeModeRefundLosers = "refund_losers" // Everyone but the winners gets their fees back
+	FeeModePot          = "pot"           // Collected fees are split between the winners
+)
+
+// EscrowEntry is one movement in a giveaway's fee escrow. Deposits are
+// positive and everything leaving the escrow is negative, so what's still
+// held is the sum of the entries.
+type EscrowEntry struct {
 	ID         int64  `json:"id"`
 	GiveawayID int64  `json:"giveaway_id"`
 	UserID     string `json:"user_id"`
-	JoinedAt   int64  `json:"joined_at"`
+	Kind       string `json:"kind"` // Escrow*
+	Amount     int64  `json:"amount"`
+	CreatedAt  int64  `json:"created_at"`
 }
 
-type Winner struct {
+// Escrow movements
+const (
+	EscrowDeposit = "deposit" // Entry fee or bought entries
+	EscrowRefund  = "refund"  // Returned to the participant
+	EscrowKept    = "kept"    // Spent: the fee isn't coming back
+	EscrowPayout  = "payout"  // Pot share paid to a winner
+	EscrowForfeit = "forfeit" // No longer the participant's, but left in the pot for the winners
+)
+
+// PrizeDelivery records one part of a reward delivered (or not) to a winner
+type PrizeDelivery struct {
 	ID         int64  `json:"id"`
 	GiveawayID int64  `json:"giveaway_id"`
 	UserID     string `json:"user_id"`
-	WonAt      int64  `json:"won_at"`
+	Kind       string `json:"kind"`   // PrizeKind*
+	Status     string `json:"status"` // DeliveryStatus*
+	Detail     string `json:"detail"` // What was delivered, or why it failed
+	ExpiresAt  int64  `json:"expires_at"`
+	CreatedAt  int64  `json:"created_at"`
+	// The winner had the prize role before winning, so it isn't taken back when it expires
+	AlreadyHeld bool `json:"already_held"`
+}
+
+// Reward parts
+const (
+	PrizeKindCoins = "coins"
+	PrizeKindItem  = "item"
+	PrizeKindRole  = "role"
+)
+
+// Delivery statuses
+const (
+	DeliveryStatusDelivered = "delivered"
+	DeliveryStatusFailed    = "failed"
+	DeliveryStatusExpired   = "expired" // Temporary role removed again
+)
+
+// BonusRules grants extra entries (weight) on top of the base entry.
+// Role bonuses stack; booster and tenure bonuses are added on top.
+type BonusRules struct {
+	Roles          []RoleBonus `json:"roles,omitempty"`
+	BoosterEntries int         `json:"booster_entries,omitempty"`
+	TenureDays     int         `json:"tenure_days,omitempty"`
+	TenureEntries  int         `json:"tenure_entries,omitempty"`
+	EntryPrice     int         `json:"entry_price,omitempty"` // Coins per bought entry (0 = disabled)
+	MaxBought      int         `json:"max_bought,omitempty"`  // Max entries a user may buy
+}
+
+type RoleBonus struct {
+	RoleID  string `json:"role_id"`
+	Entries int    `json:"entries"`
+}
+
+// Empty reports whether no bonus entries are configured
+func (b BonusRules) Empty() bool {
+	return len(b.Roles) == 0 && b.BoosterEntries == 0 && b.TenureEntries == 0 && b.EntryPrice == 0
+}
+
+// RequirementGroup combines requirements and nested groups: all must pass
+// (RequirementModeAll) or at least one must (RequirementModeAny)
+type RequirementGroup struct {
+	Mode         string             `json:"mode"`
+	Requirements []RequirementSpec  `json:"requirements,omitempty"`
+	Groups       []RequirementGroup `json:"groups,omitempty"`
+}
+
+// RequirementSpec is one stored requirement; which fields are used depends on Type
+type RequirementSpec struct {
+	Type  string   `json:"type"`            // RequirementType*
+	Roles []string `json:"roles,omitempty"` // Role requirements
+	Value int64    `json:"value,omitempty"` // Thresholds (days, messages, minutes, coins, level...)
+	// Window limits activity requirements to recent activity: days, or
+	// RequirementWindowGiveaway for since the giveaway started. 0 is lifetime.
+	// Windows start at the first full activity bucket inside them.
+	Window int `json:"window,omitempty"`
+}
+
+// RequirementWindowGiveaway counts activity since the giveaway started
+const RequirementWindowGiveaway = -1
+
+// Empty reports whether the group holds no requirements at all
+func (g *RequirementGroup) Empty() bool {
+	if g == nil {
+		return true
+	}
+	for i := range g.Groups {
+		if !g.Groups[i].Empty() {
+			return false
+		}
+	}
+	return len(g.Requirements) == 0
 }
 
+// Requirement group modes
+const (
+	RequirementModeAll = "all"
+	RequirementModeAny = "any"
+)
+
+// Requirement types
+const (
+	RequirementTypeRole        = "role"
+	RequirementTypeAnyRoles    = "any_roles"
+	RequirementTypeAllRoles    = "all_roles"
+	RequirementTypeNoRoles     = "blacklist_roles"
+	RequirementTypeAccountAge  = "account_age"
+	RequirementTypeServerAge   = "server_age"
+	RequirementTypeMessages    = "messages"
+	RequirementTypeVoice       = "voice"
+	RequirementTypeInvites     = "invites"
+	RequirementTypeBalance     = "balance"
+	RequirementTypeMessageTier = "message_tier"
+	RequirementTypeCaptcha     = "captcha"
+
+	// RequirementTypeLevelLegacy is what message_tier was stored as before it
+	// was renamed; it isn't a real level
+	RequirementTypeLevelLegacy = "level"
+)
+
+// Giveaway entry modes
+const (
+	EntryModeReaction = "reaction"
+	EntryModeButton   = "button"
+)
+
+// GiveawayTemplate holds the settings needed to create a giveaway again.
+// Prize may contain {date}, replaced with the start date.
+type GiveawayTemplate struct {
+	Prize                 string            `json:"prize"`
+	WinnersCount          int               `json:"winners_count"`
+	Duration              int64             `json:"duration"` // Milliseconds
+	CustomMessage         string            `json:"custom_message,omitempty"`
+	RoleRequirement       string            `json:"role_requirement,omitempty"`
+	InviteRequirement     int               `json:"invite_requirement,omitempty"`
+	AccountAgeRequirement int               `json:"account_age_requirement,omitempty"`
+	ServerAgeRequirement  int               `json:"server_age_requirement,omitempty"`
+	CaptchaRequirement    bool              `json:"captcha_requirement,omitempty"`
+	MessageRequired       int               `json:"message_required,omitempty"`
+	VoiceRequirement      int               `json:"voice_requirement,omitempty"`
+	EntryFee              int               `json:"entry_fee,omitempty"`
+	FeeMode               string            `json:"fee_mode,omitempty"`
+	AssignRole            string            `json:"assign_role,omitempty"`
+	Thumbnail             string            `json:"thumbnail,omitempty"`
+	Emoji                 string            `json:"emoji,omitempty"`
+	EntryMode             string            `json:"entry_mode,omitempty"`
+	Bonus                 BonusRules        `json:"bonus,omitempty"`
+	ClaimHours            int               `json:"claim_hours,omitempty"`
+	Requirements          *RequirementGroup `json:"requirements,omitempty"`
+	BypassRoles           []string          `json:"bypass_roles,omitempty"`
+	Reward                *Reward           `json:"reward,omitempty"`
+}
+
+// NewGiveaway builds a giveaway from the template starting at start (ms)
+func (t GiveawayTemplate) NewGiveaway(guildID, channelID, hostID string, start int64) *Giveaway {
+	return &Giveaway{
+		ChannelID:             channelID,
+		GuildID:               guildID,
+		HostID:                hostID,
+		Prize:                 strings.ReplaceAll(t.Prize, "{date}", time.UnixMilli(start).UTC().Format("Jan 2")),
+		WinnersCount:          t.WinnersCount,
+		EndTime:               start + t.Duration,
+		CreatedAt:             Now(),
+		CustomMessage:         t.CustomMessage,
+		RoleRequirement:       t.RoleRequirement,
+		InviteRequirement:     t.InviteRequirement,
+		AccountAgeRequirement: t.AccountAgeRequirement,
+		ServerAgeRequirement:  t.ServerAgeRequirement,
+		CaptchaRequirement:    t.CaptchaRequirement,
+		MessageRequired:       t.MessageRequired,
+		VoiceRequirement:      t.VoiceRequirement,
+		EntryFee:              t.EntryFee,
+		FeeMode:               t.FeeMode,
+		AssignRole:            t.AssignRole,
+		Thumbnail:             t.Thumbnail,
+		Emoji:                 t.Emoji,
+		EntryMode:             t.EntryMode,
+		Bonus:                 t.Bonus,
+		ClaimHours:            t.ClaimHours,
+		Requirements:          t.Requirements,
+		BypassRoles:           t.BypassRoles,
+		Reward:                t.Reward,
+		StartTime:             start,
+	}
+}
+
+// SavedTemplate is a named per-guild giveaway template
+type SavedTemplate struct {
+	ID        int64            `json:"id"`
+	GuildID   string           `json:"guild_id"`
+	Name      string           `json:"name"`
+	Template  GiveawayTemplate `json:"template"`
+	CreatedBy string           `json:"created_by"`
+	CreatedAt int64            `json:"created_at"`
+}
+
+// RecurringGiveaway re-creates a giveaway from its template on a schedule
+type RecurringGiveaway struct {
+	ID             int64            `json:"id"`
+	GuildID        string           `json:"guild_id"`
+	ChannelID      string           `json:"channel_id"`
+	HostID         string           `json:"host_id"`
+	Schedule       string           `json:"schedule"` // "daily", "weekly", "every <duration>" or a 5-field cron expression (UTC)
+	Template       GiveawayTemplate `json:"template"`
+	PingRoleID     string           `json:"ping_role_id"`
+	MaxOccurrences int              `json:"max_occurrences"` // 0 = unlimited
+	EndDate        int64            `json:"end_date"`        // No occurrences start after this (ms, 0 = never)
+	Occurrences    int              `json:"occurrences"`
+	Anchor         int64            `json:"anchor"` // First start; interval schedules repeat from here (ms)
+	Active         bool             `json:"active"`
+	CreatedAt      int64            `json:"created_at"`
+}
+
+type Participant struct {
+	ID            int64  `json:"id"`
+	GiveawayID    int64  `json:"giveaway_id"`
+	UserID        string `json:"user_id"`
+	JoinedAt      int64  `json:"joined_at"`
+	Weight        int    `json:"weight"`         // Total entries (1 + bonuses + bought)
+	BoughtEntries int    `json:"bought_entries"` // Entries bought with coins
+}
+
+// GiveawayDraw records everything needed to recompute a draw.
+// DrawNumber 0 is the end-of-giveaway draw, later draws count up from 1.
+type GiveawayDraw struct {
+	GiveawayID       int64    `json:"giveaway_id"`
+	DrawNumber       int      `json:"draw_number"`
+	Kind             string   `json:"kind"` // DrawKind*
+	Seed             string   `json:"seed"`
+	ParticipantsHash string   `json:"participants_hash"`
+	Snapshot         string   `json:"snapshot"` // Ordered "user_id:weight" lines
+	WinnerCount      int      `json:"winner_count"`
+	Winners          []string `json:"winners"`
+	CreatedAt        int64    `json:"created_at"`
+	Secret           string   `json:"secret"` // Fresh secret of a reroll; empty when the giveaway's committed secret was used
+}
+
+// Draw kinds
+const (
+	DrawKindInitial     = "initial"     // The draw when the giveaway ends
+	DrawKindReplacement = "replacement" // Redrawn for winners disqualified in the draw before
+	DrawKindReroll      = "reroll"      // A reroll after the giveaway ended
+)
+
+// GiveawayEdit is one entry in a giveaway's edit history
+type GiveawayEdit struct {
+	ID         int64    `json:"id"`
+	GiveawayID int64    `json:"giveaway_id"`
+	EditorID   string   `json:"editor_id"`
+	Changes    []string `json:"changes"` // Human readable "Field: old → new"
+	CreatedAt  int64    `json:"created_at"`
+}
+
+// Reroll is one entry in a giveaway's reroll history
+type Reroll struct {
+	ID           int64    `json:"id"`
+	GiveawayID   int64    `json:"giveaway_id"`
+	RerolledBy   string   `json:"rerolled_by"` // Empty for automatic rerolls
+	Reason       string   `json:"reason"`      // RerollReason*
+	Count        int      `json:"count"`
+	ExcludedUser string   `json:"excluded_user"`
+	Winners      []string `json:"winners"`
+	CreatedAt    int64    `json:"created_at"`
+}
+
+// Reroll reasons
+const (
+	RerollReasonManual    = "manual"
+	RerollReasonUnclaimed = "unclaimed"
+)
+
+// Disqualification is a drawn winner who was replaced because they no longer qualified
+type Disqualification struct {
+	ID         int64  `json:"id"`
+	GiveawayID int64  `json:"giveaway_id"`
+	UserID     string `json:"user_id"`
+	Reason     string `json:"reason"`
+	CreatedAt  int64  `json:"created_at"`
+}
+
+type Winner struct {
+	ID            int64  `json:"id"`
+	GiveawayID    int64  `json:"giveaway_id"`
+	UserID        string `json:"user_id"`
+	WonAt         int64  `json:"won_at"`
+	Status        string `json:"status"`         // WinnerStatus*
+	ClaimDeadline int64  `json:"claim_deadline"` // Unix ms, 0 without a claim window
+	ClaimedAt     int64  `json:"claimed_at"`
+}
+
+// Winner statuses
+const (
+	WinnerStatusWon     = "won"     // No claim window
+	WinnerStatusPending = "pending" // Waiting for the winner to claim
+	WinnerStatusClaimed = "claimed"
+	WinnerStatusExpired = "expired" // Didn't claim in time, replaced by a reroll
+)
+
 type UserStats struct {
 	ID           int64  `json:"id"`
 	GuildID      string `json:"guild_id"`
@@ -53,6 +523,18 @@ type UserStats struct {
 	VoiceMinutes int    `json:"voice_minutes"`
 }
 
+// Activity is bucketed by the hour so requirements can look at a time window.
+// Buckets older than ActivityRetentionDays are pruned.
+const (
+	ActivityBucketMs      = int64(60 * 60 * 1000)
+	ActivityRetentionDays = 90
+)
+
+// ActivityBucket returns the start of the bucket holding ts (ms)
+func ActivityBucket(ts int64) int64 {
+	return ts - ts%ActivityBucketMs
+}
+
 type CaptchaSession struct {
 	ID         int64  `json:"id"`
 	UserID     string `json:"user_id"`
@@ -80,6 +562,7 @@ type EconomyConfig struct {
 	WeeklyReward    int    `json:"weekly_reward"`
 	HourlyReward    int    `json:"hourly_reward"`
 	InviteReward    int    `json:"invite_reward"`
+	InviteFakeDays  int    `json:"invite_fake_days"` // Joins from younger accounts are fakes
 	ReactReward     int    `json:"react_reward"`
 	PollReward      int    `json:"poll_reward"`
 	EventReward     int    `json:"event_reward"`
@@ -90,6 +573,37 @@ type EconomyConfig struct {
 	CurrencyEmoji   string `json:"currency_emoji"`
 }
 
+// DefaultInviteFakeDays is the account age below which a join counts as fake
+const DefaultInviteFakeDays = 7
+
+// InviteJoin is a member join attributed to the invite they used
+type InviteJoin struct {
+	ID        int64  `json:"id"`
+	GuildID   string `json:"guild_id"`
+	UserID    string `json:"user_id"`
+	InviterID string `json:"inviter_id"` // Empty when it couldn't be attributed (vanity URL, discovery)
+	Code      string `json:"code"`
+	Fake      bool   `json:"fake"`   // Account younger than the guild's fake threshold
+	Rejoin    bool   `json:"rejoin"` // The member had joined before
+	LeftAt    int64  `json:"left_at"`
+	JoinedAt  int64  `json:"joined_at"`
+}
+
+// Counts reports whether the join counts as a regular invite for the inviter
+func (j *InviteJoin) Counts() bool {
+	return j.InviterID != "" && !j.Fake && !j.Rejoin && j.LeftAt == 0
+}
+
+// InviteStats summarises an inviter's joins. Regular is what the invite
+// requirement and leaderboard use.
+type InviteStats struct {
+	InviterID string `json:"inviter_id"`
+	Regular   int    `json:"regular"`
+	Left      int    `json:"left"`
+	Fake      int    `json:"fake"`
+	Rejoins   int    `json:"rejoins"`
+}
+
 type ShopItem struct {
 	ID              int64  `json:"id"`
 	Name            string `json:"name"`
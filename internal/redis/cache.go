@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -143,6 +144,56 @@ func (c *Client) GetDueGiveaways(now int64) ([]string, error) {
 	return results, nil
 }
 
+// Giveaway Start Queue (ZSET of scheduled giveaway IDs scored by start time)
+
+func (c *Client) AddToStartQueue(giveawayID int64, startTime int64) error {
+	return c.ZAdd("giveaways:starting", float64(startTime), strconv.FormatInt(giveawayID, 10))
+}
+
+func (c *Client) RemoveFromStartQueue(giveawayID int64) error {
+	return c.ZRem("giveaways:starting", strconv.FormatInt(giveawayID, 10))
+}
+
+// GetDueStarts returns the IDs of scheduled giveaways whose start time has passed
+func (c *Client) GetDueStarts(now int64) ([]string, error) {
+	return c.ZRangeByScore("giveaways:starting", "-inf", fmt.Sprintf("%d", now))
+}
+
+// Winner Claim Queue (ZSET of "giveawayID:userID" scored by claim deadline)
+
+func claimMember(giveawayID int64, userID string) string {
+	return fmt.Sprintf("%d:%s", giveawayID, userID)
+}
+
+func (c *Client) AddToClaimQueue(giveawayID int64, userID string, deadline int64) error {
+	return c.ZAdd("giveaways:claims", float64(deadline), claimMember(giveawayID, userID))
+}
+
+func (c *Client) RemoveFromClaimQueue(giveawayID int64, userID string) error {
+	return c.ZRem("giveaways:claims", claimMember(giveawayID, userID))
+}
+
+// GetDueClaims returns "giveawayID:userID" members whose claim deadline has passed
+func (c *Client) GetDueClaims(now int64) ([]string, error) {
+	return c.ZRangeByScore("giveaways:claims", "-inf", fmt.Sprintf("%d", now))
+}
+
+// Temporary Prize Role Queue (ZSET of delivery IDs scored by expiry)
+
+func (c *Client) AddToPrizeRoleQueue(deliveryID, expiresAt int64) error {
+	return c.ZAdd("prizes:roles", float64(expiresAt), strconv.FormatInt(deliveryID, 10))
+}
+
+// GetDuePrizeRoles returns the delivery IDs of temporary prize roles that have run out
+func (c *Client) GetDuePrizeRoles(now int64) ([]string, error) {
+	return c.ZRangeByScore("prizes:roles", "-inf", fmt.Sprintf("%d", now))
+}
+
+// GetEndingQueueSize returns the number of giveaways waiting to end
+func (c *Client) GetEndingQueueSize() (int64, error) {
+	return c.ZCard("giveaways:ending")
+}
+
 // Message Counting
 
 func (c *Client) IncrementMessageCount(guildID, userID string) error {
@@ -159,12 +210,15 @@ func (c *Client) GetAndClearMessageCounts(guildID string) (map[string]int64, err
 	return nil, nil
 }
 
-func (c *Client) IncrementMessageCountHash(guildID, userID string) error {
+// IncrementMessageCountHash counts a message in the activity bucket it was sent in
+func (c *Client) IncrementMessageCountHash(guildID, userID string, bucket int64) error {
 	key := fmt.Sprintf("msg_counts:%s", guildID)
-	return c.client.HIncrBy(ctx, key, userID, 1).Err()
+	return c.client.HIncrBy(ctx, key, fmt.Sprintf("%s:%d", userID, bucket), 1).Err()
 }
 
-func (c *Client) GetAndClearGuildMessageCounts(guildID string) (map[string]int64, error) {
+// GetAndClearGuildMessageCounts returns the pending counts per user and
+// bucket. Counts from before buckets were tracked come back under bucket 0.
+func (c *Client) GetAndClearGuildMessageCounts(guildID string) (map[string]map[int64]int64, error) {
 	key := fmt.Sprintf("msg_counts:%s", guildID)
 
 	// Rename key to process it safely (atomic)
@@ -186,10 +240,15 @@ func (c *Client) GetAndClearGuildMessageCounts(guildID string) (map[string]int64
 	// Delete temp key
 	c.client.Del(ctx, tempKey)
 
-	counts := make(map[string]int64)
-	for userID, countStr := range results {
+	counts := make(map[string]map[int64]int64)
+	for field, countStr := range results {
 		count, _ := strconv.ParseInt(countStr, 10, 64)
-		counts[userID] = count
+		userID, bucketStr, _ := strings.Cut(field, ":")
+		bucket, _ := strconv.ParseInt(bucketStr, 10, 64)
+		if counts[userID] == nil {
+			counts[userID] = make(map[int64]int64)
+		}
+		counts[userID][bucket] += count
 	}
 	return counts, nil
 }
@@ -73,6 +73,11 @@ func (c *Client) Close() error {
 	return c.client.Close()
 }
 
+// PoolStats returns connection pool statistics
+func (c *Client) PoolStats() *redis.PoolStats {
+	return c.client.PoolStats()
+}
+
 func (c *Client) Ping() error {
 	// Do actual ping
 	return c.client.Ping(ctx).Err()
@@ -125,6 +130,10 @@ func (c *Client) ZScore(key string, member string) (float64, error) {
 	return c.client.ZScore(ctx, key, member).Result()
 }
 
+func (c *Client) ZCard(key string) (int64, error) {
+	return c.client.ZCard(ctx, key).Result()
+}
+
 func (c *Client) ZRem(key string, members ...interface{}) error {
 	return c.client.ZRem(ctx, key, members...).Err()
 }
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"discord-giveaway-bot/internal/models"
+	"discord-giveaway-bot/internal/utils"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Winner claim windows. Deadlines live in the winners table (source of truth)
+// and are mirrored into the giveaways:claims zset, which the bot ticker polls,
+// so pending claims survive restarts via SyncClaimQueue.
+
+// claimDeadline returns the claim deadline for a winner drawn now, or 0 without a claim window
+func (s *GiveawayService) claimDeadline(g *models.Giveaway) int64 {
+	if g.ClaimHours <= 0 {
+		return 0
+	}
+	return models.Now() + int64(g.ClaimHours)*int64(time.Hour/time.Millisecond)
+}
+
+// recordWinner persists a winner and schedules their claim expiry
+func (s *GiveawayService) recordWinner(g *models.Giveaway, userID string, deadline int64) {
+	if err := s.DB.AddWinnerWithDeadline(g.ID, userID, deadline); err != nil {
+		log.Printf("Failed to save winner %s for giveaway %d: %v", userID, g.ID, err)
+		return
+	}
+	if deadline > 0 {
+		if err := s.Redis.AddToClaimQueue(g.ID, userID, deadline); err != nil {
+			log.Printf("Failed to queue claim for %s in giveaway %d: %v", userID, g.ID, err)
+		}
+	}
+}
+
+// RefreshEndedMessage re-renders the ended embed with the current winner/claim status
+func (s *GiveawayService) RefreshEndedMessage(g *models.Giveaway) {
+	winners, err := s.DB.GetWinnerRecords(g.ID)
+	if err != nil {
+		log.Printf("Error loading winners for giveaway %d: %v", g.ID, err)
+		return
+	}
+	deliveries, err := s.DB.GetPrizeDeliveries(g.ID)
+	if err != nil {
+		log.Printf("Error loading prize deliveries for giveaway %d: %v", g.ID, err)
+	}
+	embed := utils.GiveawayEndedEmbed(g, winners, deliveries)
+	if err := s.editGiveawayMessage(g, embed, []discordgo.MessageComponent{}); err != nil {
+		log.Printf("Error updating giveaway message: %v", err)
+	}
+}
+
+// ClaimPrize claims a pending prize and returns the message to show the winner
+func (s *GiveawayService) ClaimPrize(giveawayID int64, userID string) (string, error) {
+	g, err := s.DB.GetGiveawayByID(giveawayID)
+	if err != nil || g == nil {
+		return "", fmt.Errorf("giveaway not found")
+	}
+
+	claimed, err := s.DB.ClaimWinner(g.ID, userID, models.Now())
+	if err != nil {
+		return "", err
+	}
+	if !claimed {
+		return s.claimRejection(g, userID), nil
+	}
+
+	s.Redis.RemoveFromClaimQueue(g.ID, userID)
+
+	content := fmt.Sprintf("🎉 You claimed **%s**! The host has been notified.", g.Prize)
+	if delivered := s.deliverPrize(g, userID); delivered != "" {
+		content += fmt.Sprintf("\n📦 %s delivered!", delivered)
+	}
+
+	go s.notifyHost(g, fmt.Sprintf("🎁 <@%s> claimed their prize **%s**.", userID, g.Prize))
+	go s.RefreshEndedMessage(g)
+
+	return content, nil
+}
+
+// claimRejection explains why a claim didn't go through
+func (s *GiveawayService) claimRejection(g *models.Giveaway, userID string) string {
+	winners, _ := s.DB.GetWinnerRecords(g.ID)
+	for _, w := range winners {
+		if w.UserID != userID {
+			continue
+		}
+		switch w.Status {
+		case models.WinnerStatusClaimed:
+			return "✅ You've already claimed this prize."
+		case models.WinnerStatusWon:
+			return "✅ This prize doesn't need to be claimed."
+		default:
+			return "⌛ Your claim window has closed and the prize was rerolled."
+		}
+	}
+	return "❌ You didn't win this giveaway."
+}
+
+// ExpireClaim replaces a winner who didn't claim in time
+func (s *GiveawayService) ExpireClaim(giveawayID int64, userID string) {
+	expired, err := s.DB.ExpireWinner(giveawayID, userID)
+	if err != nil {
+		log.Printf("Failed to expire claim for %s in giveaway %d: %v", userID, giveawayID, err)
+		return
+	}
+	if !expired {
+		return // Claimed just in time
+	}
+
+	g, err := s.DB.GetGiveawayByID(giveawayID)
+	if err != nil || g == nil {
+		return
+	}
+
+	if dm, err := s.Session.UserChannelCreate(userID); err == nil {
+		s.Session.ChannelMessageSendEmbed(dm.ID, &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("⌛ You didn't claim **%s** in time, so the prize was rerolled.", g.Prize),
+			Color:       0xFF0000,
+		})
+	}
+
+	note := fmt.Sprintf("⌛ <@%s> didn't claim **%s** within %d hour(s).", userID, g.Prize, g.ClaimHours)
+	winners, err := s.RerollGiveaway(g.MessageID, RerollOptions{Count: 1, Reason: models.RerollReasonUnclaimed})
+	if err != nil {
+		log.Printf("Failed to reroll unclaimed prize for giveaway %d: %v", g.ID, err)
+		note += fmt.Sprintf(" The automatic reroll failed: %s.", err.Error())
+		go s.RefreshEndedMessage(g)
+	} else {
+		note += fmt.Sprintf(" Rerolled to <@%s>.", winners[0])
+	}
+	s.notifyHost(g, note)
+}
+
+// SyncClaimQueue rebuilds the claim queue from the database after a restart
+func (s *GiveawayService) SyncClaimQueue() error {
+	pending, err := s.DB.GetPendingClaims()
+	if err != nil {
+		return err
+	}
+	for _, w := range pending {
+		if err := s.Redis.AddToClaimQueue(w.GiveawayID, w.UserID, w.ClaimDeadline); err != nil {
+			log.Printf("Failed to queue claim for %s in giveaway %d: %v", w.UserID, w.GiveawayID, err)
+		}
+	}
+	return nil
+}
+
+// ParseClaimMember splits a claim queue member ("giveawayID:userID")
+func ParseClaimMember(member string) (int64, string, bool) {
+	idStr, userID, ok := strings.Cut(member, ":")
+	if !ok {
+		return 0, "", false
+	}
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return 0, "", false
+	}
+	return id, userID, true
+}
+
+// sendClaimDM DMs a winner the claim button
+func (s *GiveawayService) sendClaimDM(g *models.Giveaway, userID string, deadline int64) {
+	dm, err := s.Session.UserChannelCreate(userID)
+	if err != nil {
+		return
+	}
+	_, err = s.Session.ChannelMessageSendComplex(dm.ID, &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{{
+			Title:       "🎉 You won a giveaway!",
+			Description: fmt.Sprintf("You won **%s**!\nClaim it <t:%d:R> or the prize will be rerolled.\n\n[Jump to giveaway](%s)", g.Prize, deadline/1000, giveawayLink(g)),
+			Color:       0x00FF00,
+		}},
+		Components: utils.ClaimComponents(g),
+	})
+	if err != nil {
+		log.Printf("Failed to DM claim prompt to %s: %v", userID, err)
+	}
+}
+
+// notifyHost DMs the giveaway host about claim activity
+func (s *GiveawayService) notifyHost(g *models.Giveaway, content string) {
+	dm, err := s.Session.UserChannelCreate(g.HostID)
+	if err != nil {
+		return
+	}
+	s.Session.ChannelMessageSendEmbed(dm.ID, &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("%s\n\n[Jump to giveaway](%s)", content, giveawayLink(g)),
+		Color:       0x2f3136,
+	})
+}
+
+func giveawayLink(g *models.Giveaway) string {
+	return fmt.Sprintf("https://discord.com/channels/%s/%s/%s", g.GuildID, g.ChannelID, g.MessageID)
+}
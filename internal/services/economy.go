@@ -67,6 +67,34 @@ func (s *EconomyService) RemoveCoins(guildID, userID string, amount int64) error
 	return s.redis.SetBalance(guildID, userID, newBalance)
 }
 
+// RefundCoins returns coins the user spent, lowering their total spent instead of raising total earned
+func (s *EconomyService) RefundCoins(guildID, userID string, amount int64) error {
+	newBalance, err := s.db.RefundUserBalance(guildID, userID, amount)
+	if err != nil {
+		return err
+	}
+
+	// Update cache with new balance
+	return s.redis.SetBalance(guildID, userID, newBalance)
+}
+
+// MoveEscrow moves coins between a user's balance and a giveaway's escrow,
+// committing the balance change together with its ledger entry
+func (s *EconomyService) MoveEscrow(guildID string, e *models.EscrowEntry) error {
+	newBalance, err := s.db.MoveEscrowCoins(guildID, e)
+	if err != nil {
+		// A refused charge means the cached balance was stale
+		_ = s.redis.InvalidateBalance(guildID, e.UserID)
+		return err
+	}
+
+	// Update cache with new balance
+	if err := s.redis.SetBalance(guildID, e.UserID, newBalance); err != nil {
+		_ = s.redis.InvalidateBalance(guildID, e.UserID)
+	}
+	return nil
+}
+
 func (s *EconomyService) SetCoins(guildID, userID string, amount int64) error {
 	user, err := s.db.GetEconomyUser(guildID, userID)
 	if err != nil {
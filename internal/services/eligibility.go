@@ -0,0 +1,118 @@
+package services
+
+import (
+	"discord-giveaway-bot/internal/models"
+	"discord-giveaway-bot/internal/utils"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// drawEligibleWinners draws winners and re-checks each one before they're
+// announced: members can leave, get banned or lose a required role after
+// entering, activity can fall out of a "last N days" window, and activity
+// during the giveaway is only known at the draw. Winners who no longer
+// qualify are logged for the host and replaced by drawing again from the rest.
+func (s *GiveawayService) drawEligibleWinners(g *models.Giveaway, participants []models.Participant, count int, rerollSecret string) ([]string, error) {
+	winners := []string{}
+	var disqualified []*models.Disqualification
+
+	kind := models.DrawKindInitial
+	if rerollSecret != "" {
+		kind = models.DrawKindReroll
+	}
+
+	remaining := participants
+	for len(winners) < count && len(remaining) > 0 {
+		drawn, err := s.drawWinners(g, remaining, count-len(winners), kind, rerollSecret)
+		if err != nil {
+			return nil, err
+		}
+		if len(drawn) == 0 {
+			break
+		}
+
+		picked := make(map[string]bool, len(drawn))
+		for _, userID := range drawn {
+			picked[userID] = true
+
+			if reason := s.winnerIneligibility(g, userID); reason != "" {
+				log.Printf("Disqualified winner %s in giveaway %d: %s", userID, g.ID, reason)
+				dq := &models.Disqualification{GiveawayID: g.ID, UserID: userID, Reason: reason, CreatedAt: models.Now()}
+				if err := s.DB.AddDisqualification(dq); err != nil {
+					log.Printf("Failed to record disqualification for giveaway %d: %v", g.ID, err)
+				}
+				disqualified = append(disqualified, dq)
+				continue
+			}
+			winners = append(winners, userID)
+		}
+
+		rest := make([]models.Participant, 0, len(remaining))
+		for _, p := range remaining {
+			if !picked[p.UserID] {
+				rest = append(rest, p)
+			}
+		}
+		remaining = rest
+		// Any further round replaces winners disqualified in this one
+		kind = models.DrawKindReplacement
+	}
+
+	if len(disqualified) > 0 {
+		go s.notifyHostDisqualified(g, disqualified)
+	}
+	return winners, nil
+}
+
+// winnerIneligibility returns why a drawn winner can't win, or "" if they can.
+// When Discord can't be reached the winner is kept rather than disqualified
+// on an API error.
+func (s *GiveawayService) winnerIneligibility(g *models.Giveaway, userID string) string {
+	member, err := s.Session.GuildMember(g.GuildID, userID)
+	if err != nil {
+		if !isRESTCode(err, discordgo.ErrCodeUnknownMember) {
+			log.Printf("Error fetching winner %s in giveaway %d: %v", userID, g.ID, err)
+			return ""
+		}
+		if _, err := s.Session.GuildBan(g.GuildID, userID); err == nil {
+			return "banned from the server"
+		}
+		return "no longer in the server"
+	}
+
+	res, err := utils.CheckRequirementsAtDraw(s.Session, s.DB, g, member)
+	if err != nil {
+		log.Printf("Error re-checking requirements for %s in giveaway %d: %v", userID, g.ID, err)
+		return ""
+	}
+	if !res.Passed {
+		return res.Reason
+	}
+	return ""
+}
+
+func isRESTCode(err error, code int) bool {
+	var restErr *discordgo.RESTError
+	if !errors.As(err, &restErr) {
+		return false
+	}
+	if restErr.Message != nil {
+		return restErr.Message.Code == code
+	}
+	return restErr.Response != nil && restErr.Response.StatusCode == http.StatusNotFound
+}
+
+// notifyHostDisqualified tells the host which drawn winners were replaced and why
+func (s *GiveawayService) notifyHostDisqualified(g *models.Giveaway, disqualified []*models.Disqualification) {
+	lines := make([]string, len(disqualified))
+	for i, dq := range disqualified {
+		lines[i] = fmt.Sprintf("• <@%s>: %s", dq.UserID, dq.Reason)
+	}
+	s.notifyHost(g, fmt.Sprintf("🚫 %d drawn winner(s) of **%s** no longer qualified and were replaced:\n%s",
+		len(disqualified), g.Prize, strings.Join(lines, "\n")))
+}
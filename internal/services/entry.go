@@ -0,0 +1,410 @@
+package services
+
+import (
+	"context"
+	"discord-giveaway-bot/internal/models"
+	"discord-giveaway-bot/internal/utils"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Entry service shared by reaction and button entry.
+// Transports (reaction handlers, button handlers) call Enter/CompleteEntry/Leave
+// and only decide how to present the result: DMs + reaction removal for
+// reactions, ephemeral replies for buttons.
+
+// EntryStatus is the outcome of an entry or leave attempt
+type EntryStatus int
+
+const (
+	EntryAdded EntryStatus = iota
+	EntryAlreadyEntered
+	EntryClosed
+	EntryRequirementsFailed
+	EntryCaptchaRequired
+	EntryInsufficientFunds
+	EntryInProgress
+	EntryFailed
+	EntryPaused
+
+	LeaveRemoved
+	LeaveNotEntered
+)
+
+// messageUpdateDebounce coalesces participant count edits on busy giveaways
+const messageUpdateDebounce = 2 * time.Second
+
+// EntryResult describes what happened and what to tell the user
+type EntryResult struct {
+	Status  EntryStatus
+	Message string         // User-facing text (may be empty for silent outcomes)
+	Captcha *utils.Captcha // Set when Status == EntryCaptchaRequired
+	Fee     int64          // Coins charged (entry) or refunded (leave)
+}
+
+var (
+	entryLocks        sync.Map // "giveawayID:userID" -> struct{}, guards double clicks / duplicate events
+	pendingUpdates    sync.Map // giveawayID -> struct{}, debounced message edits
+	reactionGiveaways sync.Map // messageID -> struct{}, running reaction-mode giveaways
+)
+
+// TrackReactionGiveaway lets reactions on a running giveaway's message reach
+// the entry service. Button giveaways aren't entered by reaction.
+func (s *GiveawayService) TrackReactionGiveaway(g *models.Giveaway) {
+	if g.EntryMode != models.EntryModeButton {
+		reactionGiveaways.Store(g.MessageID, struct{}{})
+	}
+}
+
+// UntrackReactionGiveaway stops reactions on an ended giveaway's message reaching the entry service
+func (s *GiveawayService) UntrackReactionGiveaway(messageID string) {
+	reactionGiveaways.Delete(messageID)
+}
+
+// IsReactionGiveaway reports whether a message is a running reaction-mode
+// giveaway. The bot sees every reaction in every server, so reaction events
+// check this before going to the database.
+func (s *GiveawayService) IsReactionGiveaway(messageID string) bool {
+	_, ok := reactionGiveaways.Load(messageID)
+	return ok
+}
+
+// pausedResult rejects entries while a giveaway is paused
+func pausedResult(g *models.Giveaway) *EntryResult {
+	return &EntryResult{Status: EntryPaused, Message: fmt.Sprintf("⏸️ The giveaway for **%s** is paused. Entries are closed until it resumes.", g.Prize)}
+}
+
+func entryKey(g *models.Giveaway, userID string) string {
+	return fmt.Sprintf("%d:%s", g.ID, userID)
+}
+
+// Enter runs the full entry pipeline: state, duplicate, requirements, captcha, fee, insert.
+func (s *GiveawayService) Enter(g *models.Giveaway, guildID, userID string) *EntryResult {
+	if g.Ended {
+		return &EntryResult{Status: EntryClosed, Message: "❌ This giveaway has already ended."}
+	}
+	if g.Paused() {
+		return pausedResult(g)
+	}
+
+	key := entryKey(g, userID)
+	if _, busy := entryLocks.LoadOrStore(key, struct{}{}); busy {
+		return &EntryResult{Status: EntryInProgress, Message: "⏳ Your entry is already being processed."}
+	}
+	defer entryLocks.Delete(key)
+
+	isParticipant, _ := s.DB.IsParticipantFast(context.Background(), g.ID, userID)
+	if isParticipant {
+		return &EntryResult{Status: EntryAlreadyEntered, Message: fmt.Sprintf("✅ You're already entered in the giveaway for **%s**.", g.Prize)}
+	}
+
+	res, err := utils.CheckRequirements(s.Session, s.DB, guildID, userID, g)
+	if err != nil {
+		log.Printf("Error checking requirements: %v", err)
+		return &EntryResult{Status: EntryFailed, Message: "❌ Couldn't check the requirements right now. Please try again."}
+	}
+	if !res.Passed {
+		return &EntryResult{Status: EntryRequirementsFailed, Message: fmt.Sprintf("❌ You cannot enter the giveaway for **%s**: %s", g.Prize, res.Reason)}
+	}
+
+	if utils.RequiresCaptcha(g) {
+		captcha, err := utils.GenerateCaptcha()
+		if err != nil {
+			log.Printf("Error generating captcha: %v", err)
+			return &EntryResult{Status: EntryFailed, Message: "❌ Couldn't generate a captcha. Please try again."}
+		}
+		if err := s.DB.CreateCaptchaSession(userID, g.ID, captcha.Code); err != nil {
+			log.Printf("Error creating captcha session: %v", err)
+			return &EntryResult{Status: EntryFailed, Message: "❌ Couldn't start captcha verification. Please try again."}
+		}
+		return &EntryResult{
+			Status:  EntryCaptchaRequired,
+			Message: fmt.Sprintf("Please solve the captcha to enter the giveaway for **%s**.\nYou have **1 minute**.", g.Prize),
+			Captcha: captcha,
+		}
+	}
+
+	return s.completeEntry(g, guildID, userID)
+}
+
+// CompleteEntry finishes an entry after captcha verification (fee + insert)
+func (s *GiveawayService) CompleteEntry(g *models.Giveaway, guildID, userID string) *EntryResult {
+	if g.Ended {
+		return &EntryResult{Status: EntryClosed, Message: "❌ Giveaway not found or ended."}
+	}
+	if g.Paused() {
+		return pausedResult(g)
+	}
+
+	key := entryKey(g, userID)
+	if _, busy := entryLocks.LoadOrStore(key, struct{}{}); busy {
+		return &EntryResult{Status: EntryInProgress, Message: "⏳ Your entry is already being processed."}
+	}
+	defer entryLocks.Delete(key)
+
+	return s.completeEntry(g, guildID, userID)
+}
+
+func (s *GiveawayService) completeEntry(g *models.Giveaway, guildID, userID string) *EntryResult {
+	var fee int64
+	if g.EntryFee > 0 {
+		fee = int64(g.EntryFee)
+		balance, err := s.EconomyService.GetUserBalance(guildID, userID)
+		if err != nil {
+			log.Printf("Error getting economy user: %v", err)
+			return &EntryResult{Status: EntryFailed, Message: "❌ Couldn't check your balance. Please try again."}
+		}
+		if balance < fee {
+			return &EntryResult{
+				Status:  EntryInsufficientFunds,
+				Message: fmt.Sprintf("❌ You need **%d** %s to enter this giveaway. You have **%d**.", g.EntryFee, s.currencyEmoji(guildID), balance),
+			}
+		}
+		if err := s.chargeEscrow(g, userID, fee); err != nil {
+			log.Printf("Failed to deduct fee: %v", err)
+			return &EntryResult{Status: EntryInsufficientFunds, Message: "❌ Failed to deduct the entry fee."}
+		}
+	}
+
+	added, err := s.DB.InsertParticipant(g.ID, userID, s.entryWeight(g, guildID, userID))
+	if err != nil || !added {
+		// Never keep a fee for an entry that didn't happen
+		if fee > 0 {
+			if rerr := s.refundEscrow(g, userID, fee); rerr != nil {
+				log.Printf("Failed to return entry fee to %s: %v", userID, rerr)
+			}
+		}
+		if err != nil {
+			log.Printf("Failed to add participant: %v", err)
+			return &EntryResult{Status: EntryFailed, Message: "❌ Failed to enter the giveaway. Please try again."}
+		}
+		return &EntryResult{Status: EntryAlreadyEntered, Message: fmt.Sprintf("✅ You're already entered in the giveaway for **%s**.", g.Prize)}
+	}
+
+	s.recordParticipantEvent(g, guildID, userID, models.ParticipantJoined)
+
+	if g.AssignRole != "" {
+		if err := s.Session.GuildMemberRoleAdd(guildID, userID, g.AssignRole); err != nil {
+			log.Printf("Failed to assign role %s to user %s: %v", g.AssignRole, userID, err)
+		}
+	}
+
+	s.QueueGiveawayMessageUpdate(g)
+
+	msg := fmt.Sprintf("🎉 You've entered the giveaway for **%s**!", g.Prize)
+	if fee > 0 {
+		msg = fmt.Sprintf("✅ **%d** %s have been deducted for entering the giveaway **%s**.", g.EntryFee, s.currencyEmoji(guildID), g.Prize)
+	}
+	return &EntryResult{Status: EntryAdded, Message: msg, Fee: fee}
+}
+
+// Leave removes a participant, their assigned role and handles the fee refund
+func (s *GiveawayService) Leave(g *models.Giveaway, guildID, userID string) *EntryResult {
+	if g.Ended {
+		return &EntryResult{Status: EntryClosed, Message: "❌ This giveaway has already ended."}
+	}
+
+	key := entryKey(g, userID)
+	if _, busy := entryLocks.LoadOrStore(key, struct{}{}); busy {
+		return &EntryResult{Status: EntryInProgress, Message: "⏳ Your entry is already being processed."}
+	}
+	defer entryLocks.Delete(key)
+
+	ctx := context.Background()
+	participant, err := s.DB.GetParticipant(g.ID, userID)
+	if err != nil {
+		log.Printf("Error checking participant status: %v", err)
+		return &EntryResult{Status: EntryFailed, Message: "❌ Something went wrong. Please try again."}
+	}
+	if participant == nil {
+		return &EntryResult{Status: LeaveNotEntered, Message: "You're not entered in this giveaway."}
+	}
+
+	if err := s.DB.RemoveParticipantFast(ctx, g.ID, userID); err != nil {
+		log.Printf("Error removing participant: %v", err)
+		return &EntryResult{Status: EntryFailed, Message: "❌ Failed to leave the giveaway. Please try again."}
+	}
+	s.recordParticipantEvent(g, guildID, userID, models.ParticipantLeft)
+
+	if g.AssignRole != "" {
+		if err := s.Session.GuildMemberRoleRemove(guildID, userID, g.AssignRole); err != nil {
+			log.Printf("Failed to remove role %s from user %s: %v", g.AssignRole, userID, err)
+		}
+	}
+
+	s.QueueGiveawayMessageUpdate(g)
+
+	result := &EntryResult{Status: LeaveRemoved}
+	// Bought entries are refunded together with the entry fee
+	held, err := s.DB.GetEscrowHeldFor(g.ID, userID)
+	if err != nil {
+		log.Printf("Error getting escrowed fees: %v", err)
+		return result
+	}
+	// Entries from before fees were escrowed have nothing in the ledger
+	legacy := held == 0
+	if legacy {
+		held = int64(g.EntryFee + participant.BoughtEntries*g.Bonus.EntryPrice)
+	}
+	if held == 0 {
+		return result
+	}
+
+	refundCount, err := s.DB.GetRefundCount(g.ID, userID)
+	if err != nil {
+		log.Printf("Error getting refund count: %v", err)
+	}
+
+	if refundCount >= 3 {
+		if !legacy {
+			s.forfeitEscrow(g, userID, held)
+		}
+		result.Message = fmt.Sprintf("⚠️ You left the giveaway for **%s**, but you have exceeded the refund limit (3/3). No coins refunded.", g.Prize)
+		return result
+	}
+
+	// 80% Refund Logic, the rest is kept (or stays in the pot)
+	refundAmount := int64(float64(held) * 0.8)
+	if legacy {
+		err = s.EconomyService.RefundCoins(guildID, userID, refundAmount)
+	} else {
+		err = s.refundEscrow(g, userID, refundAmount)
+	}
+	if err != nil {
+		log.Printf("Failed to refund %s for leaving giveaway %d: %v", userID, g.ID, err)
+		result.Message = fmt.Sprintf("⚠️ You left the giveaway for **%s**, but the refund failed. Please contact a server admin.", g.Prize)
+		return result
+	}
+	if !legacy {
+		s.forfeitEscrow(g, userID, held-refundAmount)
+	}
+	s.DB.IncrementRefundCount(g.ID, userID)
+
+	result.Fee = refundAmount
+	result.Message = fmt.Sprintf("✅ You left the giveaway for **%s**. **%d** %s (80%%) have been refunded. (%d/3 refunds used)", g.Prize, refundAmount, s.currencyEmoji(guildID), refundCount+1)
+	if refundCount+1 == 3 {
+		result.Message += "\n⚠️ **Warning:** This was your last refund. No refund will be provided next time."
+	}
+	return result
+}
+
+// BuyEntries spends coins on extra entries for an existing participant
+func (s *GiveawayService) BuyEntries(g *models.Giveaway, guildID, userID string, amount int) *EntryResult {
+	if g.Ended {
+		return &EntryResult{Status: EntryClosed, Message: "❌ This giveaway has already ended."}
+	}
+	if g.Paused() {
+		return pausedResult(g)
+	}
+	if g.Bonus.EntryPrice <= 0 {
+		return &EntryResult{Status: EntryFailed, Message: "❌ This giveaway doesn't sell extra entries."}
+	}
+
+	key := entryKey(g, userID)
+	if _, busy := entryLocks.LoadOrStore(key, struct{}{}); busy {
+		return &EntryResult{Status: EntryInProgress, Message: "⏳ Your entry is already being processed."}
+	}
+	defer entryLocks.Delete(key)
+
+	participant, err := s.DB.GetParticipant(g.ID, userID)
+	if err != nil {
+		log.Printf("Error checking participant status: %v", err)
+		return &EntryResult{Status: EntryFailed, Message: "❌ Something went wrong. Please try again."}
+	}
+	if participant == nil {
+		return &EntryResult{Status: LeaveNotEntered, Message: "❌ You need to enter the giveaway before buying extra entries."}
+	}
+	if left := g.Bonus.MaxBought - participant.BoughtEntries; amount > left {
+		return &EntryResult{Status: EntryFailed, Message: fmt.Sprintf("❌ You can only buy **%d** more %s for this giveaway.", left, pluralEntries(left))}
+	}
+
+	cost := int64(amount * g.Bonus.EntryPrice)
+	balance, err := s.EconomyService.GetUserBalance(guildID, userID)
+	if err != nil {
+		log.Printf("Error getting economy user: %v", err)
+		return &EntryResult{Status: EntryFailed, Message: "❌ Couldn't check your balance. Please try again."}
+	}
+	if balance < cost {
+		return &EntryResult{
+			Status:  EntryInsufficientFunds,
+			Message: fmt.Sprintf("❌ You need **%d** %s to buy %d %s. You have **%d**.", cost, s.currencyEmoji(guildID), amount, pluralEntries(amount), balance),
+		}
+	}
+	if err := s.chargeEscrow(g, userID, cost); err != nil {
+		log.Printf("Failed to charge for entries: %v", err)
+		return &EntryResult{Status: EntryInsufficientFunds, Message: "❌ Failed to deduct the coins."}
+	}
+
+	added, err := s.DB.AddBoughtEntries(g.ID, userID, amount, g.Bonus.MaxBought)
+	if err != nil || !added {
+		if rerr := s.refundEscrow(g, userID, cost); rerr != nil {
+			log.Printf("Failed to return entry purchase to %s: %v", userID, rerr)
+		}
+		if err != nil {
+			log.Printf("Failed to add bought entries: %v", err)
+		}
+		return &EntryResult{Status: EntryFailed, Message: "❌ Failed to buy entries. You have not been charged."}
+	}
+
+	return &EntryResult{
+		Status:  EntryAdded,
+		Fee:     cost,
+		Message: fmt.Sprintf("🎟️ Bought **%d** extra %s for **%d** %s. You now have **%d** entries in **%s**.", amount, pluralEntries(amount), cost, s.currencyEmoji(guildID), participant.Weight+amount, g.Prize),
+	}
+}
+
+// entryWeight is 1 plus any bonus entries the member earns from the giveaway's rules
+func (s *GiveawayService) entryWeight(g *models.Giveaway, guildID, userID string) int {
+	if g.Bonus.Empty() {
+		return 1
+	}
+	member, err := s.Session.State.Member(guildID, userID)
+	if err != nil {
+		member, err = s.Session.GuildMember(guildID, userID)
+		if err != nil {
+			log.Printf("Failed to fetch member %s for bonus entries: %v", userID, err)
+			return 1
+		}
+	}
+	return 1 + utils.BonusEntries(g.Bonus, member)
+}
+
+// recordParticipantEvent logs an entry or leave for /gstats churn
+func (s *GiveawayService) recordParticipantEvent(g *models.Giveaway, guildID, userID, kind string) {
+	if err := s.DB.AddParticipantEvent(g.ID, guildID, userID, kind); err != nil {
+		log.Printf("Failed to record %s of %s in giveaway %d: %v", kind, userID, g.ID, err)
+	}
+}
+
+func pluralEntries(n int) string {
+	if n == 1 {
+		return "entry"
+	}
+	return "entries"
+}
+
+// QueueGiveawayMessageUpdate refreshes the participant count, coalescing bursts of entries into one edit
+func (s *GiveawayService) QueueGiveawayMessageUpdate(g *models.Giveaway) {
+	if _, pending := pendingUpdates.LoadOrStore(g.ID, struct{}{}); pending {
+		return
+	}
+	time.AfterFunc(messageUpdateDebounce, func() {
+		pendingUpdates.Delete(g.ID)
+		// Re-read so an edit never overwrites the ended/cancelled embed
+		fresh, err := s.DB.GetGiveawayByID(g.ID)
+		if err != nil || fresh.Ended {
+			return
+		}
+		s.UpdateGiveawayMessage(fresh)
+	})
+}
+
+func (s *GiveawayService) currencyEmoji(guildID string) string {
+	config, err := s.EconomyService.GetConfig(guildID)
+	if err != nil || config == nil || config.CurrencyEmoji == "" {
+		return "<:Cash:1443554334670327848>"
+	}
+	return config.CurrencyEmoji
+}
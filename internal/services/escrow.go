@@ -0,0 +1,179 @@
+package services
+
+import (
+	"discord-giveaway-bot/internal/models"
+	"log"
+)
+
+// Entry fee escrow. Fees and bought entries are moved into a per-giveaway
+// ledger as they're paid and settled by the giveaway's FeeMode once winners
+// are drawn. Balance changes and their ledger entries are written in one
+// transaction by EconomyService, which also keeps the cached balance fresh.
+
+// chargeEscrow takes coins from a participant into the giveaway's escrow
+func (s *GiveawayService) chargeEscrow(g *models.Giveaway, userID string, amount int64) error {
+	return s.moveEscrow(g, userID, models.EscrowDeposit, amount)
+}
+
+// refundEscrow returns held coins to a participant
+func (s *GiveawayService) refundEscrow(g *models.Giveaway, userID string, amount int64) error {
+	return s.moveEscrow(g, userID, models.EscrowRefund, -amount)
+}
+
+// forfeitEscrow keeps held coins that won't be refunded. In a pot giveaway
+// they stay in the pot for the winners, but are no longer held for the
+// participant, so leaving again can't refund them.
+func (s *GiveawayService) forfeitEscrow(g *models.Giveaway, userID string, amount int64) {
+	if amount <= 0 {
+		return
+	}
+	kind := models.EscrowKept
+	if g.FeeMode == models.FeeModePot {
+		kind = models.EscrowForfeit
+	}
+	if err := s.recordEscrow(g, userID, kind, -amount); err != nil {
+		log.Printf("Failed to keep %d from %s in giveaway %d: %v", amount, userID, g.ID, err)
+	}
+}
+
+// moveEscrow moves coins between a participant's balance and the escrow. The
+// balance change and its ledger entry are committed together, so a coin is
+// never charged without being held or paid out twice.
+func (s *GiveawayService) moveEscrow(g *models.Giveaway, userID, kind string, amount int64) error {
+	return s.EconomyService.MoveEscrow(g.GuildID, newEscrowEntry(g, userID, kind, amount))
+}
+
+// recordEscrow records a movement that leaves balances alone
+func (s *GiveawayService) recordEscrow(g *models.Giveaway, userID, kind string, amount int64) error {
+	return s.DB.AddEscrowEntry(newEscrowEntry(g, userID, kind, amount))
+}
+
+func newEscrowEntry(g *models.Giveaway, userID, kind string, amount int64) *models.EscrowEntry {
+	return &models.EscrowEntry{
+		GiveawayID: g.ID,
+		UserID:     userID,
+		Kind:       kind,
+		Amount:     amount,
+		CreatedAt:  models.Now(),
+	}
+}
+
+// refundAllEscrow returns everything still held, returning how many
+// participants were refunded and the total
+func (s *GiveawayService) refundAllEscrow(g *models.Giveaway) (int, int64) {
+	held, err := s.DB.GetEscrowHeld(g.ID)
+	if err != nil {
+		log.Printf("Error loading escrow for giveaway %d: %v", g.ID, err)
+		return 0, 0
+	}
+
+	var refunded int
+	var total int64
+	for userID, amount := range held {
+		if err := s.refundEscrow(g, userID, amount); err != nil {
+			log.Printf("Failed to refund %d to %s in giveaway %d: %v", amount, userID, g.ID, err)
+			continue
+		}
+		refunded++
+		total += amount
+	}
+	return refunded, total
+}
+
+// settleEscrow releases the escrow once the winners are drawn
+func (s *GiveawayService) settleEscrow(g *models.Giveaway, winners []string) {
+	held, err := s.DB.GetEscrowHeld(g.ID)
+	if err != nil {
+		log.Printf("Error loading escrow for giveaway %d: %v", g.ID, err)
+		return
+	}
+	if len(held) == 0 {
+		return
+	}
+
+	won := make(map[string]bool, len(winners))
+	for _, id := range winners {
+		won[id] = true
+	}
+
+	switch g.FeeMode {
+	case models.FeeModePot:
+		// Winners are paid their share with the rest of their prize (see potShare);
+		// with nobody to pay, everyone gets their fees back
+		if len(winners) == 0 {
+			s.refundAllEscrow(g)
+			return
+		}
+		s.keepPotRemainder(g, len(winners))
+	case models.FeeModeRefundLosers:
+		for userID, amount := range held {
+			if won[userID] {
+				s.forfeitEscrow(g, userID, amount)
+				continue
+			}
+			if err := s.refundEscrow(g, userID, amount); err != nil {
+				log.Printf("Failed to refund %d to %s in giveaway %d: %v", amount, userID, g.ID, err)
+			}
+		}
+	default:
+		for userID, amount := range held {
+			s.forfeitEscrow(g, userID, amount)
+		}
+	}
+}
+
+// keepPotRemainder keeps whatever the drawn winners' shares leave of the pot.
+// When disqualifications leave fewer winners than there are shares, the
+// unclaimed shares (and any rounding) would otherwise sit in the escrow.
+// The kept remainder belongs to nobody, so it's recorded without a user.
+func (s *GiveawayService) keepPotRemainder(g *models.Giveaway, winners int) {
+	pot, remaining, err := s.DB.GetEscrowPot(g.ID)
+	if err != nil {
+		log.Printf("Error loading pot for giveaway %d: %v", g.ID, err)
+		return
+	}
+	keep := remaining - pot/int64(s.potSplits(g))*int64(winners)
+	if keep <= 0 {
+		return
+	}
+	if err := s.recordEscrow(g, "", models.EscrowKept, -keep); err != nil {
+		log.Printf("Failed to keep the pot remainder of %d in giveaway %d: %v", keep, g.ID, err)
+	}
+}
+
+// potShare is each winner's cut of a pot giveaway's fees, capped at what's
+// left of the pot. The pot and participant count are fixed once the giveaway
+// ends, so winners paid later (claims, rerolls) get the same share.
+func (s *GiveawayService) potShare(g *models.Giveaway) int64 {
+	pot, remaining, err := s.DB.GetEscrowPot(g.ID)
+	if err != nil {
+		log.Printf("Error loading pot for giveaway %d: %v", g.ID, err)
+		return 0
+	}
+	if remaining <= 0 {
+		return 0
+	}
+
+	share := pot / int64(s.potSplits(g))
+	if share > remaining {
+		share = remaining
+	}
+	return share
+}
+
+// potSplits is how many shares the pot is split into
+func (s *GiveawayService) potSplits(g *models.Giveaway) int {
+	splits := g.WinnersCount
+	if count, err := s.DB.GetParticipantCount(g.ID); err == nil && count < splits {
+		splits = count
+	}
+	if splits < 1 {
+		splits = 1
+	}
+	return splits
+}
+
+// payPot pays a winner's pot share out of the escrow
+func (s *GiveawayService) payPot(g *models.Giveaway, userID string, amount int64) error {
+	return s.moveEscrow(g, userID, models.EscrowPayout, -amount)
+}
@@ -0,0 +1,274 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"discord-giveaway-bot/internal/models"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Provably fair draws.
+//
+//	commitment = sha256(secret)                         published when the giveaway starts
+//	snapshot   = "user_id:weight\n" lines sorted by user_id
+//	seed       = sha256(secret ":" sha256(snapshot) ":" draw_number)
+//
+// The end-of-giveaway draw uses the committed secret. Once that's revealed
+// anyone could predict a draw made with it, and the host decides who a reroll
+// excludes, so every reroll draws with a fresh secret stored on its record.
+//
+// Each participant (in snapshot order) takes the next value u from the seed
+// stream, gets the key ln(1-u)/weight, and the largest keys win. The stream's
+// n-th value is the first 8 bytes of sha256(seed || uint64be(n)) >> 11 / 2^53.
+// Anyone with the revealed secret and the snapshot can recompute the winners.
+
+// NewDrawCommitment generates a fresh draw secret and its public commitment
+func NewDrawCommitment() (secret, commitment string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	secret = hex.EncodeToString(buf)
+	return secret, hashHex(secret), nil
+}
+
+// CommitmentMatches reports whether the revealed secret matches the published commitment
+func CommitmentMatches(secret, commitment string) bool {
+	return secret != "" && hashHex(secret) == commitment
+}
+
+// BuildSnapshot orders participants canonically and returns them with the snapshot text and its hash
+func BuildSnapshot(participants []models.Participant) ([]models.Participant, string, string) {
+	ordered := make([]models.Participant, len(participants))
+	copy(ordered, participants)
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].UserID < ordered[j].UserID })
+
+	var sb strings.Builder
+	for i := range ordered {
+		if ordered[i].Weight < 1 {
+			ordered[i].Weight = 1
+		}
+		sb.WriteString(fmt.Sprintf("%s:%d\n", ordered[i].UserID, ordered[i].Weight))
+	}
+	snapshot := sb.String()
+	return ordered, snapshot, hashHex(snapshot)
+}
+
+// ParseSnapshot turns a stored snapshot back into ordered participants
+func ParseSnapshot(snapshot string) ([]models.Participant, error) {
+	var participants []models.Participant
+	for _, line := range strings.Split(strings.TrimSpace(snapshot), "\n") {
+		if line == "" {
+			continue
+		}
+		userID, weight, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("malformed snapshot line %q", line)
+		}
+		w, err := strconv.Atoi(weight)
+		if err != nil {
+			return nil, fmt.Errorf("malformed snapshot line %q", line)
+		}
+		participants = append(participants, models.Participant{UserID: userID, Weight: w})
+	}
+	return participants, nil
+}
+
+// DrawSeed derives the seed for a draw from the committed secret and the public participant hash
+func DrawSeed(secret, participantsHash string, drawNumber int) string {
+	return hashHex(fmt.Sprintf("%s:%s:%d", secret, participantsHash, drawNumber))
+}
+
+// DrawVerification is the result of recomputing a stored draw
+type DrawVerification struct {
+	Draw        *models.GiveawayDraw
+	SnapshotOK  bool // Stored hash matches the stored snapshot
+	SeedOK      bool // Stored seed matches secret + snapshot hash + draw number
+	Recomputed  []string
+	WinnersOK   bool // Recomputed winners match the announced winners
+	ParseFailed bool
+}
+
+// VerifyDraw recomputes a stored draw from the revealed secret, or the
+// draw's own secret for rerolls
+func VerifyDraw(secret string, draw *models.GiveawayDraw) *DrawVerification {
+	v := &DrawVerification{Draw: draw}
+	if draw.Secret != "" {
+		secret = draw.Secret
+	}
+	v.SnapshotOK = hashHex(draw.Snapshot) == draw.ParticipantsHash
+	v.SeedOK = DrawSeed(secret, draw.ParticipantsHash, draw.DrawNumber) == draw.Seed
+
+	participants, err := ParseSnapshot(draw.Snapshot)
+	if err != nil {
+		v.ParseFailed = true
+		return v
+	}
+	v.Recomputed = selectFromSeed(participants, draw.WinnerCount, draw.Seed)
+	v.WinnersOK = equalStrings(v.Recomputed, draw.Winners)
+	return v
+}
+
+// Valid reports whether every check passed
+func (v *DrawVerification) Valid() bool {
+	return v.SnapshotOK && v.SeedOK && v.WinnersOK && !v.ParseFailed
+}
+
+// drawAttempts is how often a draw is retried when a concurrent draw takes its number
+const drawAttempts = 3
+
+// drawWinners runs and records a verifiable draw over the given participants.
+// rerollSecret is empty for the end-of-giveaway draw, which uses the committed
+// secret. Winners that can't be verified later aren't announced: if the draw
+// can't be recorded, it fails.
+func (s *GiveawayService) drawWinners(g *models.Giveaway, participants []models.Participant, count int, kind, rerollSecret string) ([]string, error) {
+	if g.DrawSecret == "" && rerollSecret == "" {
+		// Giveaways created before commitments existed: still record a reproducible draw
+		secret, commitment, err := NewDrawCommitment()
+		if err != nil {
+			return nil, fmt.Errorf("generating draw secret: %w", err)
+		}
+		if err := s.DB.SetDrawCommitment(g.ID, secret, commitment); err != nil {
+			return nil, fmt.Errorf("storing draw secret: %w", err)
+		}
+		g.DrawSecret, g.DrawCommitment, g.CommitmentAtDraw = secret, commitment, true
+	}
+
+	// Members on win cooldown aren't part of the draw at all
+	participants = s.excludeLimitedWinners(g, participants)
+	if len(participants) == 0 {
+		return []string{}, nil
+	}
+
+	ordered, snapshot, participantsHash := BuildSnapshot(participants)
+	secret := g.DrawSecret
+	if rerollSecret != "" {
+		secret = rerollSecret
+	}
+
+	// The draw number is part of the seed, so a draw that loses its number to
+	// a concurrent one is redone with the next
+	for attempt := 1; ; attempt++ {
+		drawNumber, err := s.DB.GetNextDrawNumber(g.ID)
+		if err != nil {
+			return nil, fmt.Errorf("numbering draw: %w", err)
+		}
+
+		seed := DrawSeed(secret, participantsHash, drawNumber)
+		winners := s.SelectWinners(ordered, count, seed)
+
+		draw := &models.GiveawayDraw{
+			GiveawayID:       g.ID,
+			DrawNumber:       drawNumber,
+			Kind:             kind,
+			Seed:             seed,
+			ParticipantsHash: participantsHash,
+			Snapshot:         snapshot,
+			WinnerCount:      count,
+			Winners:          winners,
+			CreatedAt:        models.Now(),
+			Secret:           rerollSecret,
+		}
+		saved, err := s.DB.SaveGiveawayDraw(draw)
+		if err != nil {
+			return nil, fmt.Errorf("recording draw %d: %w", drawNumber, err)
+		}
+		if saved {
+			return winners, nil
+		}
+		if attempt == drawAttempts {
+			return nil, fmt.Errorf("draw %d was taken by another draw", drawNumber)
+		}
+	}
+}
+
+// SelectWinners draws up to count distinct winners from participants (in the
+// given order), each participant's odds proportional to their entry weight.
+// The result is fully determined by the seed. Members on win cooldown are
+// skipped by drawWinners before the snapshot, so they never reach it.
+func (s *GiveawayService) SelectWinners(participants []models.Participant, count int, seed string) []string {
+	if len(participants) == 0 {
+		return []string{}
+	}
+	return selectFromSeed(participants, count, seed)
+}
+
+func selectFromSeed(participants []models.Participant, count int, seed string) []string {
+	seedBytes, err := hex.DecodeString(seed)
+	if err != nil {
+		seedBytes = []byte(seed)
+	}
+	return weightedSample(participants, count, &seedStream{seed: seedBytes})
+}
+
+// weightedSample implements Efraimidis-Spirakis weighted sampling without
+// replacement: each participant gets the key u^(1/w) (as ln(u)/w) and the
+// count largest keys win.
+func weightedSample(participants []models.Participant, count int, rng *seedStream) []string {
+	type keyed struct {
+		userID string
+		key    float64
+	}
+
+	keys := make([]keyed, len(participants))
+	for i, p := range participants {
+		w := p.Weight
+		if w < 1 {
+			w = 1
+		}
+		u := 1 - rng.Float64() // (0, 1], avoids ln(0)
+		keys[i] = keyed{userID: p.UserID, key: math.Log(u) / float64(w)}
+	}
+	sort.SliceStable(keys, func(i, j int) bool { return keys[i].key > keys[j].key })
+
+	limit := count
+	if len(keys) < limit {
+		limit = len(keys)
+	}
+
+	winners := make([]string, limit)
+	for i := 0; i < limit; i++ {
+		winners[i] = keys[i].userID
+	}
+
+	return winners
+}
+
+// seedStream is a deterministic stream of floats in [0, 1) derived from a seed
+type seedStream struct {
+	seed    []byte
+	counter uint64
+}
+
+func (r *seedStream) Float64() float64 {
+	buf := make([]byte, len(r.seed)+8)
+	copy(buf, r.seed)
+	binary.BigEndian.PutUint64(buf[len(r.seed):], r.counter)
+	r.counter++
+
+	sum := sha256.Sum256(buf)
+	return float64(binary.BigEndian.Uint64(sum[:8])>>11) / (1 << 53)
+}
+
+func hashHex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
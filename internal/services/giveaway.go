@@ -7,9 +7,6 @@ import (
 	"discord-giveaway-bot/internal/utils"
 	"fmt"
 	"log"
-	"math/rand"
-	"regexp"
-	"strconv"
 	"strings"
 	"time"
 
@@ -39,58 +36,59 @@ func (s *GiveawayService) EndGiveaway(messageID string) error {
 		return fmt.Errorf("giveaway not found")
 	}
 
-	if g.Ended {
+	if g.Ended || g.Paused() || g.Scheduled {
 		return nil
 	}
 
-	// Mark as ended in DB
-	if err := s.DB.EndGiveaway(messageID); err != nil {
+	// Mark as ended in DB; only the caller that flips it goes on to draw
+	ended, err := s.DB.EndGiveaway(messageID)
+	if err != nil {
 		log.Printf("Error marking giveaway as ended: %v", err)
 		return err
 	}
+	if !ended {
+		return nil // Paused or already ended since we loaded it
+	}
 
 	// Invalidate cache
 	s.Redis.InvalidateActiveGiveaways(g.GuildID)
+	s.UntrackReactionGiveaway(g.MessageID)
 
-	// Get participants
-	participants, err := s.DB.GetParticipants(g.ID)
+	// Get participants and select winners
+	participants, err := s.DB.GetWeightedParticipants(g.ID)
 	if err != nil {
 		log.Printf("Error getting participants: %v", err)
+		s.retryEnd(g)
 		return err
 	}
+	winners, err := s.drawEligibleWinners(g, participants, g.WinnersCount, "")
+	if err != nil {
+		log.Printf("Error drawing winners for giveaway %d: %v", g.ID, err)
+		s.retryEnd(g)
+		return err
+	}
+
+	if g.RecurringID > 0 {
+		go s.scheduleNextOccurrence(g)
+	}
 
-	// Select winners
-	winners := s.SelectWinners(participants, g.WinnersCount)
+	// Winners with a claim window must claim before the deadline or get rerolled
+	deadline := s.claimDeadline(g)
+	for _, winnerID := range winners {
+		s.recordWinner(g, winnerID, deadline)
+	}
 
-	// Save winners concurrently
-	if len(winners) > 0 {
-		for _, winnerID := range winners {
-			go s.DB.AddWinner(g.ID, winnerID)
-		}
-		// Small delay to ensure all saves complete
-		time.Sleep(100 * time.Millisecond)
-	}
-
-	// Check for auto-coin distribution
-	var coinReward int64
-	// Regex to match "100 coins", "500 exe coins", etc. case insensitive
-	// Matches number followed by optional space and "coins" or "exe coins"
-	re := regexp.MustCompile(`(?i)(\d+)\s*(?:exe\s*)?coins`)
-	matches := re.FindStringSubmatch(g.Prize)
-	if len(matches) > 1 {
-		amount, err := strconv.ParseInt(matches[1], 10, 64)
-		if err == nil && amount > 0 {
-			coinReward = amount
-		}
+	// Release the entry fees held for this giveaway
+	s.settleEscrow(g, winners)
+
+	// Deliver prizes now (on claim when there's a claim window)
+	var delivered string
+	if deadline == 0 {
+		delivered = s.deliverPrizes(g, winners)
 	}
 
 	// Update message and announce winners concurrently
-	go func() {
-		embed := utils.GiveawayEndedEmbed(g, winners)
-		if _, err := s.Session.ChannelMessageEditEmbed(g.ChannelID, g.MessageID, embed); err != nil {
-			log.Printf("Error updating giveaway message: %v", err)
-		}
-	}()
+	go s.RefreshEndedMessage(g)
 
 	go func() {
 		// Announce winners
@@ -98,30 +96,19 @@ func (s *GiveawayService) EndGiveaway(messageID string) error {
 			var mentions []string
 			for _, id := range winners {
 				mentions = append(mentions, fmt.Sprintf("<@%s>", id))
-
-				// Distribute coins if applicable
-				if coinReward > 0 {
-					err := s.EconomyService.AddCoins(g.GuildID, id, coinReward)
-					if err != nil {
-						log.Printf("Failed to add auto-coins to winner %s: %v", id, err)
-					} else {
-						log.Printf("Added %d auto-coins to winner %s", coinReward, id)
-					}
-				}
 			}
 			content := fmt.Sprintf("Congrats, %s you have won **%s**\nhosted by <@%s>", strings.Join(mentions, ", "), g.Prize, g.HostID)
 
-			if coinReward > 0 {
-				config, _ := s.EconomyService.GetConfig(g.GuildID)
-				emoji := "<:Cash:1443554334670327848>"
-				if config != nil {
-					emoji = config.CurrencyEmoji
-				}
-				content += fmt.Sprintf("\n\n💰 **%d** %s have been automatically added to your balance!", coinReward, emoji)
+			if delivered != "" {
+				content += "\n\n" + delivered
+			}
+			if deadline > 0 {
+				content += fmt.Sprintf("\n\n🎁 Click **Claim Prize** <t:%d:R> or the prize will be rerolled.", deadline/1000)
 			}
 
 			msg := &discordgo.MessageSend{
-				Content: content,
+				Content:    content,
+				Components: utils.ClaimComponents(g),
 				Reference: &discordgo.MessageReference{
 					MessageID: g.MessageID,
 					ChannelID: g.ChannelID,
@@ -129,6 +116,12 @@ func (s *GiveawayService) EndGiveaway(messageID string) error {
 				},
 			}
 			s.Session.ChannelMessageSendComplex(g.ChannelID, msg)
+
+			if deadline > 0 {
+				for _, id := range winners {
+					s.sendClaimDM(g, id, deadline)
+				}
+			}
 		} else {
 			content := fmt.Sprintf("No valid participants for the giveaway: **%s**", g.Prize)
 			s.Session.ChannelMessageSend(g.ChannelID, content)
@@ -138,7 +131,34 @@ func (s *GiveawayService) EndGiveaway(messageID string) error {
 	return nil
 }
 
-func (s *GiveawayService) RerollGiveaway(messageID string) ([]string, error) {
+// endRetryDelay is how long a giveaway whose draw failed waits to be ended again
+const endRetryDelay = 30 * time.Second
+
+// retryEnd reopens a giveaway whose draw failed and queues it to end again,
+// rather than leaving it ended without winners
+func (s *GiveawayService) retryEnd(g *models.Giveaway) {
+	if err := s.DB.ReopenGiveaway(g.MessageID); err != nil {
+		log.Printf("Failed to reopen giveaway %d after a failed draw: %v", g.ID, err)
+		return
+	}
+	s.Redis.InvalidateActiveGiveaways(g.GuildID)
+	s.TrackReactionGiveaway(g)
+	if err := s.Redis.AddToEndingQueue(g.MessageID, time.Now().Add(endRetryDelay).UnixMilli()); err != nil {
+		log.Printf("Failed to requeue giveaway %d: %v", g.ID, err)
+	}
+}
+
+// RerollOptions controls a reroll
+type RerollOptions struct {
+	Count      int    // Winners to draw (default 1)
+	Exclude    string // Optional user who must not win
+	RerolledBy string // Empty for automatic rerolls
+	Reason     string // models.RerollReason*
+}
+
+// RerollGiveaway draws new winners, never re-picking anyone who already won
+// (from GetWinners), persists them and records the reroll in the history.
+func (s *GiveawayService) RerollGiveaway(messageID string, opts RerollOptions) ([]string, error) {
 	g, err := s.DB.GetGiveaway(messageID)
 	if err != nil {
 		return nil, err
@@ -149,60 +169,210 @@ func (s *GiveawayService) RerollGiveaway(messageID string) ([]string, error) {
 	if !g.Ended {
 		return nil, fmt.Errorf("giveaway has not ended yet")
 	}
+	if opts.Count < 1 {
+		opts.Count = 1
+	}
+	if opts.Reason == "" {
+		opts.Reason = models.RerollReasonManual
+	}
 
-	participants, err := s.DB.GetParticipants(g.ID)
+	participants, err := s.DB.GetWeightedParticipants(g.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	winners := s.SelectWinners(participants, 1)
-
-	if len(winners) > 0 {
-		content := fmt.Sprintf("🎉 New winner: <@%s>! You won **%s**!", winners[0], g.Prize)
-
-		// Check for auto-coin distribution on reroll too
-		re := regexp.MustCompile(`(?i)(\d+)\s*(?:exe\s*)?coins`)
-		matches := re.FindStringSubmatch(g.Prize)
-		if len(matches) > 1 {
-			amount, err := strconv.ParseInt(matches[1], 10, 64)
-			if err == nil && amount > 0 {
-				err := s.EconomyService.AddCoins(g.GuildID, winners[0], amount)
-				if err == nil {
-					config, _ := s.EconomyService.GetConfig(g.GuildID)
-					emoji := "<:Cash:1443554334670327848>"
-					if config != nil {
-						emoji = config.CurrencyEmoji
-					}
-					content += fmt.Sprintf("\n💰 **%d** %s have been automatically added to your balance!", amount, emoji)
-				}
-			}
+	// Anyone who already won (including unclaimed winners) can't be drawn again
+	previous, err := s.DB.GetWinners(g.ID)
+	if err != nil {
+		return nil, err
+	}
+	excluded := make(map[string]bool, len(previous)+1)
+	for _, id := range previous {
+		excluded[id] = true
+	}
+	if opts.Exclude != "" {
+		excluded[opts.Exclude] = true
+	}
+	eligible := participants[:0]
+	for _, p := range participants {
+		if !excluded[p.UserID] {
+			eligible = append(eligible, p)
 		}
+	}
+	if len(eligible) == 0 {
+		return nil, fmt.Errorf("no eligible participants left to reroll")
+	}
+
+	// The committed secret is public by now, so a reroll can't reuse it
+	secret, _, err := NewDrawCommitment()
+	if err != nil {
+		return nil, err
+	}
+	winners, err := s.drawEligibleWinners(g, eligible, opts.Count, secret)
+	if err != nil {
+		return nil, err
+	}
+	if len(winners) == 0 {
+		return nil, fmt.Errorf("no remaining participants meet the requirements")
+	}
+
+	reroll := &models.Reroll{
+		GiveawayID:   g.ID,
+		RerolledBy:   opts.RerolledBy,
+		Reason:       opts.Reason,
+		Count:        opts.Count,
+		ExcludedUser: opts.Exclude,
+		Winners:      winners,
+		CreatedAt:    models.Now(),
+	}
+	if err := s.DB.AddRerollRecord(reroll); err != nil {
+		log.Printf("Failed to record reroll for giveaway %d: %v", g.ID, err)
+	}
+
+	deadline := s.claimDeadline(g)
+	var mentions []string
+	for _, id := range winners {
+		s.recordWinner(g, id, deadline)
+		mentions = append(mentions, fmt.Sprintf("<@%s>", id))
+	}
+
+	// Rerolled winners get their prizes delivered too
+	var delivered string
+	if deadline == 0 {
+		delivered = s.deliverPrizes(g, winners)
+	}
 
-		s.Session.ChannelMessageSend(g.ChannelID, content)
+	label := "New winner"
+	if len(winners) > 1 {
+		label = "New winners"
+	}
+	content := fmt.Sprintf("🎉 %s: %s! You won **%s**!", label, strings.Join(mentions, ", "), g.Prize)
+	if delivered != "" {
+		content += "\n" + delivered
+	}
+	if deadline > 0 {
+		content += fmt.Sprintf("\n🎁 Click **Claim Prize** <t:%d:R> or the prize will be rerolled.", deadline/1000)
 	}
 
+	s.Session.ChannelMessageSendComplex(g.ChannelID, &discordgo.MessageSend{
+		Content:    content,
+		Components: utils.ClaimComponents(g),
+		Reference: &discordgo.MessageReference{
+			MessageID: g.MessageID,
+			ChannelID: g.ChannelID,
+			GuildID:   g.GuildID,
+		},
+	})
+	if deadline > 0 {
+		for _, id := range winners {
+			s.sendClaimDM(g, id, deadline)
+		}
+	}
+	go s.RefreshEndedMessage(g)
+
 	return winners, nil
 }
 
-func (s *GiveawayService) SelectWinners(participants []string, count int) []string {
-	if len(participants) == 0 {
-		return []string{}
+// EditGiveaway saves an edited running giveaway, re-scores the ending queue,
+// re-renders the message and records the changes in the edit history.
+func (s *GiveawayService) EditGiveaway(g *models.Giveaway, editorID string, changes []string) error {
+	updated, err := s.DB.UpdateGiveaway(g)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return fmt.Errorf("giveaway already ended")
+	}
+
+	edit := &models.GiveawayEdit{
+		GiveawayID: g.ID,
+		EditorID:   editorID,
+		Changes:    changes,
+		CreatedAt:  models.Now(),
+	}
+	if err := s.DB.AddGiveawayEdit(edit); err != nil {
+		log.Printf("Failed to record edit for giveaway %d: %v", g.ID, err)
+	}
+
+	s.Redis.InvalidateActiveGiveaways(g.GuildID)
+	if !g.Paused() {
+		if err := s.Redis.AddToEndingQueue(g.MessageID, g.EndTime); err != nil {
+			log.Printf("Failed to re-score giveaway %s in ending queue: %v", g.MessageID, err)
+		}
+	}
+	s.UpdateGiveawayMessage(g)
+
+	// Edits are announced under the giveaway so participants can see what changed
+	s.Session.ChannelMessageSendComplex(g.ChannelID, &discordgo.MessageSend{
+		Content: fmt.Sprintf("✏️ This giveaway was edited by <@%s>:\n%s", editorID, "• "+strings.Join(changes, "\n• ")),
+		Reference: &discordgo.MessageReference{
+			MessageID: g.MessageID,
+			ChannelID: g.ChannelID,
+			GuildID:   g.GuildID,
+		},
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	})
+
+	return nil
+}
+
+// PauseGiveaway stops the end timer and closes entries, keeping the remaining time
+func (s *GiveawayService) PauseGiveaway(g *models.Giveaway) error {
+	if g.Ended {
+		return fmt.Errorf("giveaway already ended")
+	}
+	if g.Paused() {
+		return fmt.Errorf("giveaway is already paused")
+	}
+
+	now := models.Now()
+	remaining := g.EndTime - now
+	if remaining <= 0 {
+		return fmt.Errorf("giveaway is about to end")
+	}
+
+	paused, err := s.DB.PauseGiveaway(g.ID, now, remaining)
+	if err != nil {
+		return err
+	}
+	if !paused {
+		return fmt.Errorf("giveaway already ended or paused")
 	}
+	g.PausedAt, g.PausedRemaining = now, remaining
 
-	rand.Seed(time.Now().UnixNano())
-	perm := rand.Perm(len(participants))
+	if err := s.Redis.RemoveFromEndingQueue(g.MessageID); err != nil {
+		log.Printf("Failed to remove paused giveaway %s from ending queue: %v", g.MessageID, err)
+	}
+	s.Redis.InvalidateActiveGiveaways(g.GuildID)
+	s.UpdateGiveawayMessage(g)
+	return nil
+}
 
-	limit := count
-	if len(participants) < limit {
-		limit = len(participants)
+// ResumeGiveaway reopens entries and re-queues the giveaway with its remaining time
+func (s *GiveawayService) ResumeGiveaway(g *models.Giveaway) error {
+	if g.Ended {
+		return fmt.Errorf("giveaway already ended")
+	}
+	if !g.Paused() {
+		return fmt.Errorf("giveaway is not paused")
 	}
 
-	winners := make([]string, limit)
-	for i := 0; i < limit; i++ {
-		winners[i] = participants[perm[i]]
+	endTime := models.Now() + g.PausedRemaining
+	resumed, err := s.DB.ResumeGiveaway(g.ID, endTime)
+	if err != nil {
+		return err
+	}
+	if !resumed {
+		return fmt.Errorf("giveaway already ended or resumed")
 	}
+	g.EndTime, g.PausedAt, g.PausedRemaining = endTime, 0, 0
 
-	return winners
+	if err := s.Redis.AddToEndingQueue(g.MessageID, g.EndTime); err != nil {
+		log.Printf("Failed to re-queue resumed giveaway %s: %v", g.MessageID, err)
+	}
+	s.Redis.InvalidateActiveGiveaways(g.GuildID)
+	s.UpdateGiveawayMessage(g)
+	return nil
 }
 
 func (s *GiveawayService) CancelGiveaway(messageID string) error {
@@ -217,14 +387,29 @@ func (s *GiveawayService) CancelGiveaway(messageID string) error {
 		return fmt.Errorf("giveaway already ended")
 	}
 
-	// Mark as ended in DB
-	if err := s.DB.EndGiveaway(messageID); err != nil {
+	// Mark as ended (and cancelled) in DB, unless it ended in the meantime
+	cancelled, err := s.DB.CancelGiveaway(messageID)
+	if err != nil {
 		return err
 	}
+	if !cancelled {
+		return fmt.Errorf("giveaway already ended")
+	}
+	s.UntrackReactionGiveaway(g.MessageID)
+
+	// Nobody can win a cancelled giveaway, so every fee goes back
+	if refunded, total := s.refundAllEscrow(g); refunded > 0 {
+		log.Printf("Refunded %d coins to %d participants of cancelled giveaway %d", total, refunded, g.ID)
+	}
+
+	// Cancelling one occurrence doesn't stop the series, /grecurring stop does
+	if g.RecurringID > 0 {
+		go s.scheduleNextOccurrence(g)
+	}
 
 	// Update message to show cancelled
 	embed := utils.GiveawayCancelledEmbed(g)
-	_, err = s.Session.ChannelMessageEditEmbed(g.ChannelID, g.MessageID, embed)
+	err = s.editGiveawayMessage(g, embed, []discordgo.MessageComponent{})
 	if err != nil {
 		log.Printf("Error updating giveaway message: %v", err)
 	}
@@ -235,9 +420,22 @@ func (s *GiveawayService) CancelGiveaway(messageID string) error {
 func (s *GiveawayService) UpdateGiveawayMessage(g *models.Giveaway) {
 	count, _ := s.DB.GetParticipantCount(g.ID)
 	embed := utils.GiveawayEmbed(g, count)
+	if g.EntryMode == models.EntryModeButton {
+		s.editGiveawayMessage(g, embed, utils.GiveawayComponents(g, count))
+		return
+	}
 	s.Session.ChannelMessageEditEmbed(g.ChannelID, g.MessageID, embed)
 }
 
+// editGiveawayMessage replaces the embed and components of the giveaway message.
+// An empty components slice removes the Enter/Leave buttons.
+func (s *GiveawayService) editGiveawayMessage(g *models.Giveaway, embed *discordgo.MessageEmbed, components []discordgo.MessageComponent) error {
+	edit := discordgo.NewMessageEdit(g.ChannelID, g.MessageID).SetEmbed(embed)
+	edit.Components = &components
+	_, err := s.Session.ChannelMessageEditComplex(edit)
+	return err
+}
+
 func (s *GiveawayService) GetActiveGiveaways(guildID string) ([]*models.Giveaway, error) {
 	// Try Redis first
 	if giveaways, ok := s.Redis.GetActiveGiveaways(guildID); ok {
@@ -263,6 +461,11 @@ func (s *GiveawayService) SyncGiveawayQueue() error {
 	}
 
 	for _, g := range giveaways {
+		// Paused giveaways still take entries (to tell members they're paused)
+		s.TrackReactionGiveaway(g)
+		if g.Paused() {
+			continue
+		}
 		if err := s.Redis.AddToEndingQueue(g.MessageID, g.EndTime); err != nil {
 			log.Printf("Failed to add giveaway %s to ending queue: %v", g.MessageID, err)
 		}
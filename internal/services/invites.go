@@ -0,0 +1,188 @@
+package services
+
+import (
+	"discord-giveaway-bot/internal/database"
+	"discord-giveaway-bot/internal/models"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// InviteService attributes member joins to the invite they used. Discord
+// doesn't say which invite a member joined with, so each guild's invite use
+// counts are cached and diffed against a fresh fetch on every join.
+type InviteService struct {
+	Session        *discordgo.Session
+	DB             *database.Database
+	EconomyService *EconomyService
+
+	guilds sync.Map // guildID -> *guildInvites
+}
+
+type guildInvites struct {
+	mu      sync.Mutex
+	loaded  bool
+	invites map[string]cachedInvite // code -> invite
+}
+
+type cachedInvite struct {
+	inviterID string
+	uses      int
+	maxUses   int
+}
+
+func NewInviteService(s *discordgo.Session, db *database.Database, economyService *EconomyService) *InviteService {
+	return &InviteService{
+		Session:        s,
+		DB:             db,
+		EconomyService: economyService,
+	}
+}
+
+func (s *InviteService) guild(guildID string) *guildInvites {
+	gi, _ := s.guilds.LoadOrStore(guildID, &guildInvites{invites: make(map[string]cachedInvite)})
+	return gi.(*guildInvites)
+}
+
+func snapshotInvites(invites []*discordgo.Invite) map[string]cachedInvite {
+	snapshot := make(map[string]cachedInvite, len(invites))
+	for _, inv := range invites {
+		c := cachedInvite{uses: inv.Uses, maxUses: inv.MaxUses}
+		if inv.Inviter != nil {
+			c.inviterID = inv.Inviter.ID
+		}
+		snapshot[inv.Code] = c
+	}
+	return snapshot
+}
+
+// LoadGuild fills the invite cache for a guild (needs Manage Server)
+func (s *InviteService) LoadGuild(guildID string) error {
+	invites, err := s.Session.GuildInvites(guildID)
+	if err != nil {
+		return err
+	}
+
+	gi := s.guild(guildID)
+	gi.mu.Lock()
+	defer gi.mu.Unlock()
+	gi.invites = snapshotInvites(invites)
+	gi.loaded = true
+	return nil
+}
+
+// InviteCreated caches a new invite so its first use is diffed from zero
+func (s *InviteService) InviteCreated(e *discordgo.InviteCreate) {
+	gi := s.guild(e.GuildID)
+	gi.mu.Lock()
+	defer gi.mu.Unlock()
+
+	c := cachedInvite{uses: e.Uses, maxUses: e.MaxUses}
+	if e.Inviter != nil {
+		c.inviterID = e.Inviter.ID
+	}
+	gi.invites[e.Code] = c
+}
+
+// MemberJoined attributes a join to an invite, records it and pays the
+// inviter's InviteReward when the join counts
+func (s *InviteService) MemberJoined(guildID string, user *discordgo.User) {
+	if user.Bot {
+		return
+	}
+
+	code, inviterID := s.attribute(guildID)
+	join := &models.InviteJoin{
+		GuildID:   guildID,
+		UserID:    user.ID,
+		InviterID: inviterID,
+		Code:      code,
+		JoinedAt:  models.Now(),
+	}
+
+	config, err := s.EconomyService.GetConfig(guildID)
+	if err != nil {
+		config = &models.EconomyConfig{InviteFakeDays: models.DefaultInviteFakeDays}
+	}
+	if created, err := discordgo.SnowflakeTimestamp(user.ID); err == nil && config.InviteFakeDays > 0 {
+		join.Fake = time.Since(created) < time.Duration(config.InviteFakeDays)*24*time.Hour
+	}
+
+	if err := s.DB.RecordInviteJoin(join); err != nil {
+		log.Printf("Failed to record invite join for %s in guild %s: %v", user.ID, guildID, err)
+		return
+	}
+
+	if join.Counts() && config.InviteReward > 0 {
+		if err := s.EconomyService.AddCoins(guildID, inviterID, int64(config.InviteReward)); err != nil {
+			log.Printf("Failed to pay invite reward to %s: %v", inviterID, err)
+		}
+	}
+}
+
+// attribute works out which invite was just used by diffing use counts.
+// Returns empty strings when the join can't be attributed: vanity URLs,
+// discovery, missing permissions, or several invites used at once.
+func (s *InviteService) attribute(guildID string) (code, inviterID string) {
+	gi := s.guild(guildID)
+	gi.mu.Lock()
+	defer gi.mu.Unlock()
+
+	invites, err := s.Session.GuildInvites(guildID)
+	if err != nil {
+		log.Printf("Failed to fetch invites for guild %s: %v", guildID, err)
+		return "", ""
+	}
+	fresh := snapshotInvites(invites)
+	previous, loaded := gi.invites, gi.loaded
+	gi.invites, gi.loaded = fresh, true
+	if !loaded {
+		// Nothing to diff against yet
+		return "", ""
+	}
+
+	var candidates []string
+	for code, inv := range fresh {
+		if inv.uses > previous[code].uses {
+			candidates = append(candidates, code)
+		}
+	}
+	if len(candidates) == 0 {
+		// Discord deletes a max-uses invite as its last use is spent
+		for code, inv := range previous {
+			if _, ok := fresh[code]; !ok && inv.maxUses > 0 && inv.uses+1 >= inv.maxUses {
+				candidates = append(candidates, code)
+			}
+		}
+	}
+	if len(candidates) != 1 {
+		return "", ""
+	}
+
+	code = candidates[0]
+	if inv, ok := fresh[code]; ok {
+		return code, inv.inviterID
+	}
+	return code, previous[code].inviterID
+}
+
+// MemberLeft stops the member's join counting towards their inviter
+func (s *InviteService) MemberLeft(guildID, userID string) {
+	if err := s.DB.MarkInviteLeft(guildID, userID, models.Now()); err != nil {
+		log.Printf("Failed to record invite leave for %s in guild %s: %v", userID, guildID, err)
+	}
+}
+
+func (s *InviteService) GetStats(guildID, userID string) (*models.InviteStats, error) {
+	return s.DB.GetInviteStats(guildID, userID)
+}
+
+func (s *InviteService) GetInviter(guildID, userID string) (string, error) {
+	return s.DB.GetInviter(guildID, userID)
+}
+
+func (s *InviteService) GetLeaderboard(guildID string, limit int) ([]*models.InviteStats, error) {
+	return s.DB.GetInviteLeaderboard(guildID, limit)
+}
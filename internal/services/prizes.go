@@ -0,0 +1,224 @@
+package services
+
+import (
+	"discord-giveaway-bot/internal/models"
+	"discord-giveaway-bot/internal/utils"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Structured prizes. Each part of a giveaway's reward is delivered to every
+// winner as they're drawn (or when they claim, with a claim window), and each
+// delivery is recorded so the ended embed can show what actually arrived.
+// Temporary roles are mirrored into the prizes:roles zset and taken back by
+// the bot's PrizeRoleTicker.
+
+// prizeReward returns what each winner is given automatically. Giveaways
+// without a structured reward fall back to "500 coins" in the prize text.
+func prizeReward(g *models.Giveaway) *models.Reward {
+	if g.Reward != nil {
+		return g.Reward
+	}
+	return utils.LegacyCoinReward(g.Prize)
+}
+
+// deliverPrize gives a winner every part of the reward and records each delivery.
+// Returns a summary of what was delivered, or "" if nothing was.
+func (s *GiveawayService) deliverPrize(g *models.Giveaway, userID string) string {
+	reward := prizeReward(g)
+	if reward.Empty() && g.FeeMode != models.FeeModePot {
+		return ""
+	}
+
+	var delivered, failed []string
+	record := func(kind, detail string, err error, expiresAt int64, alreadyHeld bool) {
+		p := &models.PrizeDelivery{
+			GiveawayID:  g.ID,
+			UserID:      userID,
+			Kind:        kind,
+			Status:      models.DeliveryStatusDelivered,
+			Detail:      detail,
+			CreatedAt:   models.Now(),
+			AlreadyHeld: alreadyHeld,
+		}
+		if err != nil {
+			log.Printf("Failed to deliver %s to winner %s of giveaway %d: %v", kind, userID, g.ID, err)
+			p.Status = models.DeliveryStatusFailed
+			p.Detail = fmt.Sprintf("%s: %s", detail, err.Error())
+			failed = append(failed, p.Detail)
+		} else {
+			p.ExpiresAt = expiresAt
+			delivered = append(delivered, detail)
+		}
+
+		if err := s.DB.AddPrizeDelivery(p); err != nil {
+			log.Printf("Failed to record prize delivery for giveaway %d: %v", g.ID, err)
+			return
+		}
+		if p.Status == models.DeliveryStatusDelivered && p.ExpiresAt > 0 {
+			if err := s.Redis.AddToPrizeRoleQueue(p.ID, p.ExpiresAt); err != nil {
+				log.Printf("Failed to queue prize role %d: %v", p.ID, err)
+			}
+		}
+	}
+
+	if g.FeeMode == models.FeeModePot {
+		if share := s.potShare(g); share > 0 {
+			err := s.payPot(g, userID, share)
+			record(models.PrizeKindCoins, fmt.Sprintf("**%d** %s from the entry fee pot", share, s.currencyEmoji(g.GuildID)), err, 0, false)
+		}
+	}
+	if reward.Coins > 0 {
+		err := s.EconomyService.AddCoins(g.GuildID, userID, reward.Coins)
+		record(models.PrizeKindCoins, fmt.Sprintf("**%d** %s", reward.Coins, s.currencyEmoji(g.GuildID)), err, 0, false)
+	}
+	if reward.ItemID != 0 {
+		err := s.DB.GiveItem(userID, g.GuildID, reward.ItemID, reward.ItemQuantity)
+		record(models.PrizeKindItem, fmt.Sprintf("**%dx %s**", reward.ItemQuantity, reward.ItemName), err, 0, false)
+	}
+	if reward.RoleID != "" {
+		var expiresAt int64
+		detail := fmt.Sprintf("<@&%s>", reward.RoleID)
+		if reward.RoleDuration > 0 {
+			expiresAt = models.Now() + reward.RoleDuration
+			detail += " for " + utils.FormatDays(reward.RoleDuration)
+		}
+		// Hosts can't hand out roles they couldn't assign themselves, and may
+		// have lost the right to since the giveaway was created
+		err := s.CheckRewardRole(g.GuildID, g.HostID, reward)
+		var alreadyHeld bool
+		if err == nil {
+			alreadyHeld = s.memberHasRole(g.GuildID, userID, reward.RoleID)
+			err = s.Session.GuildMemberRoleAdd(g.GuildID, userID, reward.RoleID)
+		}
+		record(models.PrizeKindRole, detail, err, expiresAt, alreadyHeld)
+	}
+
+	if len(failed) > 0 {
+		go s.notifyHost(g, fmt.Sprintf("⚠️ Part of the prize for <@%s> in **%s** couldn't be delivered and needs to be given manually:\n• %s",
+			userID, g.Prize, strings.Join(failed, "\n• ")))
+	}
+	return strings.Join(delivered, ", ")
+}
+
+// deliverPrizes delivers the reward to each winner and returns the announcement line
+func (s *GiveawayService) deliverPrizes(g *models.Giveaway, winners []string) string {
+	var summary string
+	for _, id := range winners {
+		if delivered := s.deliverPrize(g, id); delivered != "" {
+			summary = delivered
+		}
+	}
+	if summary == "" {
+		return ""
+	}
+	return fmt.Sprintf("📦 %s delivered automatically!", summary)
+}
+
+// ExpirePrizeRole takes back a temporary prize role once it runs out
+func (s *GiveawayService) ExpirePrizeRole(deliveryID int64) {
+	p, err := s.DB.GetPrizeDelivery(deliveryID)
+	if err != nil || p == nil {
+		return
+	}
+	expired, err := s.DB.ExpirePrizeDelivery(p.ID)
+	if err != nil {
+		log.Printf("Failed to expire prize role delivery %d: %v", p.ID, err)
+		return
+	}
+	if !expired {
+		return
+	}
+
+	g, err := s.DB.GetGiveawayByID(p.GiveawayID)
+	if err != nil || g == nil || g.Reward == nil || g.Reward.RoleID == "" {
+		return
+	}
+	// A role the winner had before winning wasn't ours to give, so it stays
+	if !p.AlreadyHeld {
+		if err := s.Session.GuildMemberRoleRemove(g.GuildID, p.UserID, g.Reward.RoleID); err != nil {
+			log.Printf("Failed to remove prize role from %s in giveaway %d: %v", p.UserID, g.ID, err)
+		}
+	}
+	go s.RefreshEndedMessage(g)
+}
+
+// CheckRewardRole makes sure the host could assign a reward role themselves:
+// Discord requires Manage Roles and a higher role for that, and a giveaway
+// mustn't let a host grant roles above their own.
+func (s *GiveawayService) CheckRewardRole(guildID, hostID string, reward *models.Reward) error {
+	if reward == nil || reward.RoleID == "" {
+		return nil
+	}
+
+	guild, err := s.Session.State.Guild(guildID)
+	if err != nil {
+		if guild, err = s.Session.Guild(guildID); err != nil {
+			return fmt.Errorf("couldn't load the server to check the reward role: %w", err)
+		}
+	}
+	if guild.OwnerID == hostID {
+		return nil
+	}
+	member, err := s.Session.GuildMember(guildID, hostID)
+	if err != nil {
+		return fmt.Errorf("couldn't load the host to check the reward role: %w", err)
+	}
+
+	roles := make(map[string]*discordgo.Role, len(guild.Roles))
+	for _, r := range guild.Roles {
+		roles[r.ID] = r
+	}
+	target, ok := roles[reward.RoleID]
+	if !ok {
+		return errors.New("the reward role no longer exists")
+	}
+
+	var permissions int64
+	if everyone, ok := roles[guildID]; ok {
+		permissions = everyone.Permissions
+	}
+	highest := 0
+	for _, id := range member.Roles {
+		if r, ok := roles[id]; ok {
+			permissions |= r.Permissions
+			highest = max(highest, r.Position)
+		}
+	}
+	if permissions&(discordgo.PermissionManageRoles|discordgo.PermissionAdministrator) == 0 {
+		return errors.New("the host needs Manage Roles to give away a role")
+	}
+	if target.Position >= highest {
+		return fmt.Errorf("<@&%s> must be below the host's highest role", reward.RoleID)
+	}
+	return nil
+}
+
+// memberHasRole reports whether a member already has a role. If the member
+// can't be loaded they're assumed not to, as before this was checked.
+func (s *GiveawayService) memberHasRole(guildID, userID, roleID string) bool {
+	member, err := s.Session.GuildMember(guildID, userID)
+	if err != nil {
+		return false
+	}
+	return slices.Contains(member.Roles, roleID)
+}
+
+// SyncPrizeRoleQueue rebuilds the temporary prize role queue from the database after a restart
+func (s *GiveawayService) SyncPrizeRoleQueue() error {
+	deliveries, err := s.DB.GetTemporaryRoleDeliveries()
+	if err != nil {
+		return err
+	}
+	for _, p := range deliveries {
+		if err := s.Redis.AddToPrizeRoleQueue(p.ID, p.ExpiresAt); err != nil {
+			log.Printf("Failed to queue prize role %d: %v", p.ID, err)
+		}
+	}
+	return nil
+}
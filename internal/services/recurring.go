@@ -0,0 +1,155 @@
+package services
+
+import (
+	"discord-giveaway-bot/internal/models"
+	"discord-giveaway-bot/internal/utils"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Recurring giveaways. Each occurrence is a scheduled giveaway (see
+// scheduled.go) tagged with its recurring_id; when one ends the next is
+// scheduled at the first start time after it, until the series is stopped
+// or runs out of occurrences. Only occurrences that actually start count
+// towards MaxOccurrences; skipped or dropped ones are replaced by the next.
+
+// CreateRecurringGiveaway stores a recurring giveaway and schedules its first occurrence
+func (s *GiveawayService) CreateRecurringGiveaway(r *models.RecurringGiveaway) (time.Time, error) {
+	recurrence, err := utils.ParseRecurrence(r.Schedule)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	now := time.Now()
+	first := recurrence.Next(now, now)
+	if first.IsZero() {
+		return time.Time{}, fmt.Errorf("that schedule never runs")
+	}
+	if r.EndDate > 0 && first.UnixMilli() > r.EndDate {
+		return time.Time{}, fmt.Errorf("the end date is before the first occurrence (<t:%d:f>)", first.Unix())
+	}
+
+	r.Anchor = first.UnixMilli()
+	r.Active = true
+	r.CreatedAt = models.Now()
+	if err := s.DB.CreateRecurringGiveaway(r); err != nil {
+		return time.Time{}, err
+	}
+
+	if err := s.scheduleOccurrence(r, first); err != nil {
+		s.DB.StopRecurringGiveaway(r.ID, "")
+		return time.Time{}, err
+	}
+	return first, nil
+}
+
+// StopRecurringGiveaway ends a series and removes its not-yet-posted occurrence;
+// a running occurrence finishes normally
+func (s *GiveawayService) StopRecurringGiveaway(guildID string, recurringID int64) error {
+	stopped, err := s.DB.StopRecurringGiveaway(recurringID, guildID)
+	if err != nil {
+		return err
+	}
+	if !stopped {
+		return fmt.Errorf("no active recurring giveaway with ID %d", recurringID)
+	}
+
+	ids, err := s.DB.DeletePendingOccurrences(recurringID)
+	if err != nil {
+		log.Printf("Failed to remove pending occurrences of recurring giveaway %d: %v", recurringID, err)
+	}
+	for _, id := range ids {
+		s.Redis.RemoveFromStartQueue(id)
+	}
+	return nil
+}
+
+// NextRecurringStart returns when the series' next occurrence would start after now
+func (s *GiveawayService) NextRecurringStart(r *models.RecurringGiveaway) time.Time {
+	recurrence, err := utils.ParseRecurrence(r.Schedule)
+	if err != nil {
+		return time.Time{}
+	}
+	return recurrence.Next(time.UnixMilli(r.Anchor), time.Now())
+}
+
+// scheduleNextOccurrence continues the series of a giveaway that just ended
+func (s *GiveawayService) scheduleNextOccurrence(g *models.Giveaway) {
+	r, err := s.DB.GetRecurringGiveaway(g.RecurringID)
+	if err != nil || r == nil || !r.Active {
+		return
+	}
+
+	recurrence, err := utils.ParseRecurrence(r.Schedule)
+	if err != nil {
+		log.Printf("Recurring giveaway %d has an invalid schedule %q: %v", r.ID, r.Schedule, err)
+		return
+	}
+
+	// Never overlap the previous occurrence
+	after := time.Now()
+	if end := time.UnixMilli(g.EndTime); end.After(after) {
+		after = end
+	}
+	next := recurrence.Next(time.UnixMilli(r.Anchor), after)
+	if next.IsZero() {
+		s.finishRecurring(r, "its schedule has no more start times")
+		return
+	}
+
+	if err := s.scheduleOccurrence(r, next); err != nil {
+		log.Printf("Failed to schedule next occurrence of recurring giveaway %d: %v", r.ID, err)
+	}
+}
+
+// scheduleOccurrence creates the series' occurrence starting at start, or
+// finishes the series once it hits its occurrence limit or end date
+func (s *GiveawayService) scheduleOccurrence(r *models.RecurringGiveaway, start time.Time) error {
+	if r.MaxOccurrences > 0 && r.Occurrences >= r.MaxOccurrences {
+		s.finishRecurring(r, fmt.Sprintf("all %d occurrences have run", r.MaxOccurrences))
+		return nil
+	}
+	if r.EndDate > 0 && start.UnixMilli() > r.EndDate {
+		s.finishRecurring(r, "its end date has passed")
+		return nil
+	}
+
+	g := r.Template.NewGiveaway(r.GuildID, r.ChannelID, r.HostID, start.UnixMilli())
+	g.RecurringID = r.ID
+
+	var err error
+	g.DrawSecret, g.DrawCommitment, err = NewDrawCommitment()
+	if err != nil {
+		return err
+	}
+	return s.ScheduleGiveaway(g)
+}
+
+// finishRecurring deactivates a series that has run its course and tells the host
+func (s *GiveawayService) finishRecurring(r *models.RecurringGiveaway, reason string) {
+	if _, err := s.DB.StopRecurringGiveaway(r.ID, ""); err != nil {
+		log.Printf("Failed to stop recurring giveaway %d: %v", r.ID, err)
+		return
+	}
+	if dm, err := s.Session.UserChannelCreate(r.HostID); err == nil {
+		s.Session.ChannelMessageSendEmbed(dm.ID, &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("🔁 The recurring giveaway **%s** (`%d`) has finished: %s.", r.Template.Prize, r.ID, reason),
+			Color:       0x2f3136,
+		})
+	}
+}
+
+// occurrencePing returns the announcement ping for a recurring occurrence, if any
+func (s *GiveawayService) occurrencePing(g *models.Giveaway) (string, *discordgo.MessageAllowedMentions) {
+	if g.RecurringID == 0 {
+		return "", nil
+	}
+	r, err := s.DB.GetRecurringGiveaway(g.RecurringID)
+	if err != nil || r == nil || r.PingRoleID == "" {
+		return "", nil
+	}
+	return fmt.Sprintf("<@&%s>", r.PingRoleID), &discordgo.MessageAllowedMentions{Roles: []string{r.PingRoleID}}
+}
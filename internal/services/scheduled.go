@@ -0,0 +1,172 @@
+package services
+
+import (
+	"discord-giveaway-bot/internal/models"
+	"discord-giveaway-bot/internal/utils"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Scheduled giveaways. They are stored with scheduled = 1 and a placeholder
+// message ID, and mirrored into the giveaways:starting zset, which the bot
+// ticker polls. Posting swaps in the real message and hands the giveaway
+// over to the ending queue.
+
+// scheduleRetryWindow is how long a start that failed to post keeps being retried
+const scheduleRetryWindow = time.Hour
+
+// ScheduleGiveaway stores a giveaway to be posted at g.StartTime
+func (s *GiveawayService) ScheduleGiveaway(g *models.Giveaway) error {
+	g.Scheduled = true
+	g.MessageID = fmt.Sprintf("scheduled-%d", time.Now().UnixNano())
+
+	id, err := s.DB.CreateGiveaway(g)
+	if err != nil {
+		return err
+	}
+	g.ID = id
+
+	return s.Redis.AddToStartQueue(g.ID, g.StartTime)
+}
+
+// StartScheduledGiveaway posts a scheduled giveaway to its channel and starts its timer
+func (s *GiveawayService) StartScheduledGiveaway(giveawayID int64) error {
+	g, err := s.DB.GetGiveawayByID(giveawayID)
+	if err != nil {
+		// It's already off the queue; put it back rather than lose it
+		if qerr := s.Redis.AddToStartQueue(giveawayID, models.Now()+time.Minute.Milliseconds()); qerr != nil {
+			log.Printf("Failed to re-queue scheduled giveaway %d: %v", giveawayID, qerr)
+		}
+		return err
+	}
+	if g == nil {
+		return fmt.Errorf("giveaway not found")
+	}
+	if !g.Scheduled {
+		return nil // Cancelled or already posted
+	}
+
+	// Keep the full duration if the bot was offline at the start time
+	now := models.Now()
+	if late := now - g.StartTime; late > 0 {
+		g.EndTime += late
+	}
+
+	content, mentions := s.occurrencePing(g)
+	msg, err := s.Session.ChannelMessageSendComplex(g.ChannelID, &discordgo.MessageSend{
+		Content:         content,
+		Embeds:          []*discordgo.MessageEmbed{utils.CreateGiveawayEmbed(g, 0)},
+		Components:      utils.GiveawayComponents(g, 0),
+		AllowedMentions: mentions,
+	})
+	if err != nil {
+		s.retryScheduledStart(g, err)
+		return err
+	}
+
+	started, err := s.DB.StartScheduledGiveaway(g.ID, msg.ID, g.EndTime)
+	if err != nil {
+		// Nothing points at the posted message yet, so take it down and post
+		// again on the retry
+		s.Session.ChannelMessageDelete(g.ChannelID, msg.ID)
+		s.retryScheduledStart(g, err)
+		return err
+	}
+	if !started {
+		// Cancelled while we were posting
+		s.Session.ChannelMessageDelete(g.ChannelID, msg.ID)
+		return nil
+	}
+	g.MessageID, g.Scheduled = msg.ID, false
+	s.TrackReactionGiveaway(g)
+
+	if g.RecurringID > 0 {
+		if err := s.DB.IncrementRecurringOccurrences(g.RecurringID); err != nil {
+			log.Printf("Failed to count occurrence of recurring giveaway %d: %v", g.RecurringID, err)
+		}
+	}
+
+	s.Redis.InvalidateActiveGiveaways(g.GuildID)
+	if err := s.Redis.AddToEndingQueue(g.MessageID, g.EndTime); err != nil {
+		log.Printf("Failed to queue scheduled giveaway %d for ending: %v", g.ID, err)
+	}
+
+	if g.EntryMode != models.EntryModeButton {
+		if err := s.Session.MessageReactionAdd(g.ChannelID, msg.ID, utils.GiveawayEmojiAPIName(g.Emoji)); err != nil {
+			log.Printf("Failed to add reaction: %v", err)
+		}
+	}
+	return nil
+}
+
+// retryScheduledStart re-queues a start that failed to post, giving up after scheduleRetryWindow
+func (s *GiveawayService) retryScheduledStart(g *models.Giveaway, cause error) {
+	if time.Duration(models.Now()-g.StartTime)*time.Millisecond < scheduleRetryWindow {
+		if err := s.Redis.AddToStartQueue(g.ID, models.Now()+time.Minute.Milliseconds()); err != nil {
+			log.Printf("Failed to re-queue scheduled giveaway %d: %v", g.ID, err)
+		}
+		return
+	}
+
+	if _, err := s.DB.DeleteScheduledGiveaway(g.ID, g.GuildID); err != nil {
+		log.Printf("Failed to drop scheduled giveaway %d: %v", g.ID, err)
+	}
+	if dm, err := s.Session.UserChannelCreate(g.HostID); err == nil {
+		s.Session.ChannelMessageSendEmbed(dm.ID, &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("❌ Your scheduled giveaway **%s** couldn't be posted in <#%s> and was cancelled: %s", g.Prize, g.ChannelID, cause.Error()),
+			Color:       0xFF0000,
+		})
+	}
+
+	// A dropped occurrence doesn't end the series
+	if g.RecurringID > 0 {
+		go s.scheduleNextOccurrence(g)
+	}
+}
+
+// CancelScheduledGiveaway removes a giveaway that hasn't started yet
+// (for recurring giveaways this skips one occurrence)
+func (s *GiveawayService) CancelScheduledGiveaway(guildID string, giveawayID int64) error {
+	g, err := s.DB.GetGiveawayByID(giveawayID)
+	if err != nil || g == nil {
+		return fmt.Errorf("no scheduled giveaway with ID %d", giveawayID)
+	}
+
+	deleted, err := s.DB.DeleteScheduledGiveaway(giveawayID, guildID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("no scheduled giveaway with ID %d", giveawayID)
+	}
+	s.Redis.RemoveFromStartQueue(giveawayID)
+
+	if g.RecurringID > 0 {
+		go s.scheduleNextOccurrence(g)
+	}
+	return nil
+}
+
+// SyncStartQueue rebuilds the start queue from the database after a restart
+func (s *GiveawayService) SyncStartQueue() error {
+	giveaways, err := s.DB.GetScheduledGiveaways("")
+	if err != nil {
+		return err
+	}
+	for _, g := range giveaways {
+		if err := s.Redis.AddToStartQueue(g.ID, g.StartTime); err != nil {
+			log.Printf("Failed to queue scheduled giveaway %d: %v", g.ID, err)
+		}
+	}
+	return nil
+}
+
+// ParseStartMember parses a start queue member (the giveaway ID)
+func ParseStartMember(member string) (int64, bool) {
+	id, err := strconv.ParseInt(member, 10, 64)
+	return id, err == nil
+}
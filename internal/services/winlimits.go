@@ -0,0 +1,64 @@
+package services
+
+import (
+	"discord-giveaway-bot/internal/models"
+	"log"
+)
+
+// Win limits. Members on cooldown or at their win limit are left out of the
+// draw pool before the snapshot is taken, so verified draws still recompute.
+
+// WinLimits returns the limits a giveaway draws with: its own override, or the guild's
+func (s *GiveawayService) WinLimits(g *models.Giveaway) *models.WinLimits {
+	if g.WinLimits != nil {
+		return g.WinLimits
+	}
+	limits, err := s.DB.GetWinLimits(g.GuildID)
+	if err != nil {
+		log.Printf("Error loading win limits for guild %s: %v", g.GuildID, err)
+		return &models.WinLimits{}
+	}
+	return limits
+}
+
+// excludeLimitedWinners drops participants who can't win right now
+func (s *GiveawayService) excludeLimitedWinners(g *models.Giveaway, participants []models.Participant) []models.Participant {
+	limits := s.WinLimits(g)
+	if limits.Empty() {
+		return participants
+	}
+
+	now := models.Now()
+	wins, err := s.DB.GetWinTimes(g.GuildID, now-limits.Lookback())
+	if err != nil {
+		// Better an unlimited draw than no draw
+		log.Printf("Error loading recent wins for giveaway %d: %v", g.ID, err)
+		return participants
+	}
+
+	eligible := make([]models.Participant, 0, len(participants))
+	for _, p := range participants {
+		if limits.BlockedUntil(wins[p.UserID], now) > 0 {
+			continue
+		}
+		eligible = append(eligible, p)
+	}
+	if skipped := len(participants) - len(eligible); skipped > 0 {
+		log.Printf("Skipped %d participant(s) on win cooldown in giveaway %d", skipped, g.ID)
+	}
+	return eligible
+}
+
+// WinCooldown returns when a user can win again under the guild's limits, or 0 if they can now
+func (s *GiveawayService) WinCooldown(guildID, userID string) (int64, error) {
+	limits, err := s.DB.GetWinLimits(guildID)
+	if err != nil || limits.Empty() {
+		return 0, err
+	}
+	now := models.Now()
+	wins, err := s.DB.GetWinTimes(guildID, now-limits.Lookback())
+	if err != nil {
+		return 0, err
+	}
+	return limits.BlockedUntil(wins[userID], now), nil
+}
@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"discord-giveaway-bot/internal/models"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Limits for bonus entries so a single rule can't make a giveaway meaningless
+const (
+	MaxBonusEntries  = 50
+	MaxBoughtEntries = 100
+)
+
+var roleBonusRegex = regexp.MustCompile(`^(?:<@&)?(\d{15,21})>?[:=x](\d+)$`)
+
+// ParseBonusRoles parses "@Role:2 @Other:1" (mentions or raw IDs, separated by spaces or commas)
+func ParseBonusRoles(input string) ([]models.RoleBonus, error) {
+	var bonuses []models.RoleBonus
+	for _, token := range strings.FieldsFunc(input, func(r rune) bool { return r == ' ' || r == ',' }) {
+		m := roleBonusRegex.FindStringSubmatch(token)
+		if m == nil {
+			return nil, fmt.Errorf("invalid bonus role `%s` (use `@Role:2`)", token)
+		}
+		entries, _ := strconv.Atoi(m[2])
+		if entries < 1 || entries > MaxBonusEntries {
+			return nil, fmt.Errorf("bonus entries for <@&%s> must be between 1 and %d", m[1], MaxBonusEntries)
+		}
+		bonuses = append(bonuses, models.RoleBonus{RoleID: m[1], Entries: entries})
+	}
+	return bonuses, nil
+}
+
+// ParseBonusRules parses the non-role bonus rules into rules:
+//
+//	booster:N          boosters get N extra entries
+//	tenure:DAYS:N      members for DAYS+ days get N extra entries
+//	buy:PRICE:MAX      up to MAX extra entries can be bought for PRICE coins each
+func ParseBonusRules(input string, rules *models.BonusRules) error {
+	for _, token := range strings.FieldsFunc(input, func(r rune) bool { return r == ' ' || r == ',' }) {
+		parts := strings.Split(strings.ToLower(token), ":")
+		nums := make([]int, 0, 2)
+		for _, p := range parts[1:] {
+			n, err := strconv.Atoi(p)
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid bonus rule `%s`", token)
+			}
+			nums = append(nums, n)
+		}
+
+		switch {
+		case parts[0] == "booster" && len(nums) == 1:
+			if nums[0] > MaxBonusEntries {
+				return fmt.Errorf("booster bonus can't exceed %d entries", MaxBonusEntries)
+			}
+			rules.BoosterEntries = nums[0]
+		case parts[0] == "tenure" && len(nums) == 2:
+			if nums[1] > MaxBonusEntries {
+				return fmt.Errorf("tenure bonus can't exceed %d entries", MaxBonusEntries)
+			}
+			rules.TenureDays, rules.TenureEntries = nums[0], nums[1]
+		case parts[0] == "buy" && len(nums) == 2:
+			if nums[1] > MaxBoughtEntries {
+				return fmt.Errorf("at most %d entries can be bought", MaxBoughtEntries)
+			}
+			rules.EntryPrice, rules.MaxBought = nums[0], nums[1]
+		default:
+			return fmt.Errorf("invalid bonus rule `%s` (use `booster:N`, `tenure:DAYS:N` or `buy:PRICE:MAX`)", token)
+		}
+	}
+	return nil
+}
+
+// BonusEntries returns the extra entries a member earns from roles, boosting and tenure
+func BonusEntries(rules models.BonusRules, member *discordgo.Member) int {
+	if member == nil {
+		return 0
+	}
+
+	bonus := 0
+	for _, rb := range rules.Roles {
+		for _, roleID := range member.Roles {
+			if roleID == rb.RoleID {
+				bonus += rb.Entries
+				break
+			}
+		}
+	}
+	if rules.BoosterEntries > 0 && member.PremiumSince != nil {
+		bonus += rules.BoosterEntries
+	}
+	if rules.TenureEntries > 0 && !member.JoinedAt.IsZero() &&
+		time.Since(member.JoinedAt) >= time.Duration(rules.TenureDays)*24*time.Hour {
+		bonus += rules.TenureEntries
+	}
+	return bonus
+}
+
+// BonusRuleLines renders the bonus rules for the giveaway embed
+func BonusRuleLines(rules models.BonusRules) []string {
+	var lines []string
+	for _, rb := range rules.Roles {
+		lines = append(lines, fmt.Sprintf("• <@&%s>: **+%d** %s", rb.RoleID, rb.Entries, pluralEntries(rb.Entries)))
+	}
+	if rules.BoosterEntries > 0 {
+		lines = append(lines, fmt.Sprintf("• Server Boosters: **+%d** %s", rules.BoosterEntries, pluralEntries(rules.BoosterEntries)))
+	}
+	if rules.TenureEntries > 0 {
+		lines = append(lines, fmt.Sprintf("• Members for %d+ days: **+%d** %s", rules.TenureDays, rules.TenureEntries, pluralEntries(rules.TenureEntries)))
+	}
+	if rules.EntryPrice > 0 {
+		lines = append(lines, fmt.Sprintf("• Buy up to **%d** extra %s for **%d** coins each (`/gbuy`)", rules.MaxBought, pluralEntries(rules.MaxBought), rules.EntryPrice))
+	}
+	return lines
+}
+
+func pluralEntries(n int) string {
+	if n == 1 {
+		return "entry"
+	}
+	return "entries"
+}
@@ -13,43 +13,53 @@ func CreateGiveawayEmbed(g *models.Giveaway, participantCount int) *discordgo.Me
 	endTime := time.Unix(0, g.EndTime*int64(time.Millisecond))
 
 	description := fmt.Sprintf("**Winners:** %d\n**Hosted By:** <@%s>\n\nEnds in: <t:%d:R> (<t:%d:f>)\n", g.WinnersCount, g.HostID, endTime.Unix(), endTime.Unix())
+	if g.Paused() {
+		description = fmt.Sprintf("**Winners:** %d\n**Hosted By:** <@%s>\n\n⏸️ **Paused** since <t:%d:R>, **%s** left once resumed\n", g.WinnersCount, g.HostID, g.PausedAt/1000, FormatRemaining(g.PausedRemaining))
+	}
+
+	if !g.Reward.Empty() {
+		description = fmt.Sprintf("**Rewards:** %s\n", RewardSummary(g.Reward, "coins")) + description
+	}
 
 	var reqs []string
 	if g.EntryFee > 0 {
-		reqs = append(reqs, fmt.Sprintf("• **Entry Fee:** %d coins", g.EntryFee))
-	}
-	if g.RoleRequirement != "" {
-		reqs = append(reqs, fmt.Sprintf("• **Required Role:** <@&%s>", g.RoleRequirement))
-	}
-	if g.InviteRequirement > 0 {
-		reqs = append(reqs, fmt.Sprintf("• **Invites:** %d+", g.InviteRequirement))
-	}
-	if g.AccountAgeRequirement > 0 {
-		reqs = append(reqs, fmt.Sprintf("• **Account Age:** %d+ days", g.AccountAgeRequirement))
-	}
-	if g.ServerAgeRequirement > 0 {
-		reqs = append(reqs, fmt.Sprintf("• **Server Age:** %d+ days", g.ServerAgeRequirement))
+		fee := fmt.Sprintf("• **Entry Fee:** %d coins", g.EntryFee)
+		if label := FeeModeLabel(g.FeeMode); label != "" {
+			fee += " (" + label + ")"
+		}
+		reqs = append(reqs, fee)
 	}
-	if g.MessageRequired > 0 {
-		reqs = append(reqs, fmt.Sprintf("• **Messages:** %d+", g.MessageRequired))
+	reqs = append(reqs, RequirementLines(g)...)
+
+	if len(reqs) > 0 {
+		description += "\n**Requirements:**\n" + strings.Join(reqs, "\n")
 	}
-	if g.VoiceRequirement > 0 {
-		reqs = append(reqs, fmt.Sprintf("• **Voice Time:** %d+ mins", g.VoiceRequirement))
+
+	if bonus := BonusRuleLines(g.Bonus); len(bonus) > 0 {
+		description += "\n\n**Bonus Entries:**\n" + strings.Join(bonus, "\n")
 	}
-	if g.CaptchaRequirement {
-		reqs = append(reqs, "• **Captcha Verification**")
+
+	if g.Paused() {
+		description += "\n\n⏸️ Entries are closed while the giveaway is paused."
+	} else if g.EntryMode == models.EntryModeButton {
+		description += "\n\nClick **Enter** below to join!"
+	} else {
+		description += fmt.Sprintf("\n\nReact with %s to enter!", GiveawayEmojiMention(g.Emoji))
 	}
 
-	if len(reqs) > 0 {
-		description += "\n**Requirements:**\n" + strings.Join(reqs, "\n")
+	if g.DrawCommitment != "" {
+		description += fmt.Sprintf("\n\n🔒 **Draw Commitment:** `%s`", g.DrawCommitment)
 	}
 
-	description += "\n\nReact with 🎉 to enter!"
+	color := 0x2f3136 // Dark embed color like Giveaway Boat
+	if g.Paused() {
+		color = 0xFFA500
+	}
 
 	embed := &discordgo.MessageEmbed{
 		Title:       g.Prize,
 		Description: description,
-		Color:       0x2f3136, // Dark embed color like Giveaway Boat
+		Color:       color,
 		Footer: &discordgo.MessageEmbedFooter{
 			Text: fmt.Sprintf("%d Participants", participantCount),
 		},
@@ -65,13 +75,67 @@ func CreateGiveawayEmbed(g *models.Giveaway, participantCount int) *discordgo.Me
 	return embed
 }
 
-func CreateGiveawayButton(giveawayID string) *discordgo.Button {
+// FormatRemaining renders a millisecond duration as e.g. "1d 4h", "2h 30m" or "45s"
+func FormatRemaining(ms int64) string {
+	d := time.Duration(ms) * time.Millisecond
+	days := int(d.Hours() / 24)
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	case minutes > 0:
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%ds", int(d.Seconds()))
+}
+
+func CreateGiveawayButton(giveawayID string, emoji string) *discordgo.Button {
+	name, id, animated := ParseGiveawayEmoji(emoji)
 	return &discordgo.Button{
-		Label:    "Enter Giveaway",
+		Label:    "Enter",
 		Style:    discordgo.SuccessButton,
 		CustomID: "enter_giveaway_" + giveawayID,
 		Emoji: &discordgo.ComponentEmoji{
-			Name: "🎉",
+			Name:     name,
+			ID:       id,
+			Animated: animated,
+		},
+	}
+}
+
+func CreateLeaveGiveawayButton(giveawayID string) *discordgo.Button {
+	return &discordgo.Button{
+		Label:    "Leave",
+		Style:    discordgo.SecondaryButton,
+		CustomID: "leave_giveaway_" + giveawayID,
+	}
+}
+
+// GiveawayComponents returns the Enter/Leave row (with live participant count) for button-mode giveaways
+func GiveawayComponents(g *models.Giveaway, participantCount int) []discordgo.MessageComponent {
+	if g.EntryMode != models.EntryModeButton || g.ID == 0 {
+		return []discordgo.MessageComponent{}
+	}
+	id := fmt.Sprintf("%d", g.ID)
+	enter := CreateGiveawayButton(id, g.Emoji)
+	enter.Disabled = g.Paused()
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				enter,
+				CreateLeaveGiveawayButton(id),
+				discordgo.Button{
+					Label:    fmt.Sprintf("%d", participantCount),
+					Style:    discordgo.SecondaryButton,
+					CustomID: "giveaway_count_" + id,
+					Emoji:    &discordgo.ComponentEmoji{Name: "👥"},
+					Disabled: true,
+				},
+			},
 		},
 	}
 }
@@ -83,19 +147,36 @@ func GiveawayEmbed(g *models.Giveaway, participantCount int) *discordgo.MessageE
 	return CreateGiveawayEmbed(g, participantCount)
 }
 
-func GiveawayEndedEmbed(g *models.Giveaway, winners []string) *discordgo.MessageEmbed {
+func GiveawayEndedEmbed(g *models.Giveaway, winners []*models.Winner, deliveries []*models.PrizeDelivery) *discordgo.MessageEmbed {
 	winnerMentions := "No valid entrants"
 	if len(winners) > 0 {
 		var mentions []string
-		for _, id := range winners {
-			mentions = append(mentions, fmt.Sprintf("<@%s>", id))
+		for _, w := range winners {
+			mentions = append(mentions, winnerMention(w)+deliveryMark(w.UserID, deliveries))
 		}
 		winnerMentions = strings.Join(mentions, ", ")
 	}
 
+	description := fmt.Sprintf("**Prize:** %s", g.Prize)
+	if !g.Reward.Empty() {
+		description += fmt.Sprintf("\n**Rewards:** %s", RewardSummary(g.Reward, "coins"))
+	}
+	description += fmt.Sprintf("\n**Winners:** %s\n**Hosted By:** <@%s>", winnerMentions, g.HostID)
+	if g.ClaimHours > 0 && len(winners) > 0 {
+		description += fmt.Sprintf("\n\n🎁 Winners have **%d hour(s)** to claim. ✅ claimed • ⏳ waiting • ~~struck~~ unclaimed and rerolled", g.ClaimHours)
+	}
+	if len(deliveries) > 0 {
+		description += "\n\n📦 prize delivered • ⚠️ delivery failed, the host will hand it out"
+	}
+	if g.CommitmentAtDraw {
+		description += fmt.Sprintf("\n\n🎲 Reproducible draw, no commitment was published before it. Check with `/gverify %s`", g.MessageID)
+	} else if g.DrawCommitment != "" {
+		description += fmt.Sprintf("\n\n🔒 Provably fair draw. Verify with `/gverify %s`", g.MessageID)
+	}
+
 	return &discordgo.MessageEmbed{
 		Title:       "Giveaway Ended",
-		Description: fmt.Sprintf("**Prize:** %s\n**Winners:** %s\n**Hosted By:** <@%s>", g.Prize, winnerMentions, g.HostID),
+		Description: description,
 		Color:       0x000000, // Black/Dark
 		Footer: &discordgo.MessageEmbedFooter{
 			Text: "Ended",
@@ -104,6 +185,53 @@ func GiveawayEndedEmbed(g *models.Giveaway, winners []string) *discordgo.Message
 	}
 }
 
+func winnerMention(w *models.Winner) string {
+	switch w.Status {
+	case models.WinnerStatusClaimed:
+		return fmt.Sprintf("<@%s> ✅", w.UserID)
+	case models.WinnerStatusPending:
+		return fmt.Sprintf("<@%s> ⏳ (<t:%d:R>)", w.UserID, w.ClaimDeadline/1000)
+	case models.WinnerStatusExpired:
+		return fmt.Sprintf("~~<@%s>~~", w.UserID)
+	default:
+		return fmt.Sprintf("<@%s>", w.UserID)
+	}
+}
+
+// deliveryMark shows whether a winner's prize was delivered, "" if nothing was attempted
+func deliveryMark(userID string, deliveries []*models.PrizeDelivery) string {
+	mark := ""
+	for _, p := range deliveries {
+		if p.UserID != userID {
+			continue
+		}
+		if p.Status == models.DeliveryStatusFailed {
+			return " ⚠️"
+		}
+		mark = " 📦"
+	}
+	return mark
+}
+
+// ClaimComponents returns the "Claim Prize" button for giveaways with a claim window
+func ClaimComponents(g *models.Giveaway) []discordgo.MessageComponent {
+	if g.ClaimHours <= 0 {
+		return nil
+	}
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Claim Prize",
+					Style:    discordgo.SuccessButton,
+					CustomID: fmt.Sprintf("claim_prize_%d", g.ID),
+					Emoji:    &discordgo.ComponentEmoji{Name: "🎁"},
+				},
+			},
+		},
+	}
+}
+
 func GiveawayCancelledEmbed(g *models.Giveaway) *discordgo.MessageEmbed {
 	return &discordgo.MessageEmbed{
 		Title:       "Giveaway Cancelled",
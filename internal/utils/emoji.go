@@ -101,3 +101,54 @@ func StealEmoji(s *discordgo.Session, guildID string, name string, emojiID strin
 	}
 	return fmt.Sprintf("%s:%s", newEmoji.Name, newEmoji.ID), nil
 }
+
+// ParseGiveawayEmoji splits a stored giveaway emoji into its parts.
+// Accepts "🎉", "name:id", "a:name:id" and "<:name:id>"/"<a:name:id>".
+// An empty value is the default 🎉.
+func ParseGiveawayEmoji(stored string) (name, id string, animated bool) {
+	stored = strings.TrimSpace(stored)
+	if stored == "" {
+		return EmojiGiveaway, "", false
+	}
+
+	trimmed := strings.TrimSuffix(strings.TrimPrefix(stored, "<"), ">")
+	parts := strings.Split(trimmed, ":")
+	switch {
+	case len(parts) == 3 && (parts[0] == "a" || parts[0] == ""):
+		return parts[1], parts[2], parts[0] == "a"
+	case len(parts) == 2 && parts[1] != "":
+		return parts[0], parts[1], false
+	}
+	return stored, "", false
+}
+
+// GiveawayEmojiAPIName returns the emoji in the form the reactions API expects ("🎉" or "name:id")
+func GiveawayEmojiAPIName(stored string) string {
+	name, id, _ := ParseGiveawayEmoji(stored)
+	if id == "" {
+		return name
+	}
+	return name + ":" + id
+}
+
+// GiveawayEmojiMention returns the emoji as it should render in message text
+func GiveawayEmojiMention(stored string) string {
+	name, id, animated := ParseGiveawayEmoji(stored)
+	if id == "" {
+		return name
+	}
+	if animated {
+		return fmt.Sprintf("<a:%s:%s>", name, id)
+	}
+	return fmt.Sprintf("<:%s:%s>", name, id)
+}
+
+// GiveawayEmojiMatches reports whether a reaction emoji is the giveaway's entry emoji.
+// Custom emoji are matched by ID (names can be changed), unicode emoji by name.
+func GiveawayEmojiMatches(stored string, e discordgo.Emoji) bool {
+	name, id, _ := ParseGiveawayEmoji(stored)
+	if id != "" {
+		return e.ID == id
+	}
+	return e.ID == "" && e.Name == name
+}
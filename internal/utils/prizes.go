@@ -0,0 +1,191 @@
+package utils
+
+import (
+	"discord-giveaway-bot/internal/models"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxRewardQuantity caps how many of a shop item each winner can be given
+const MaxRewardQuantity = 1000
+
+var (
+	rewardCoinsRegex    = regexp.MustCompile(`(?i)^(\d+)\s*(?:exe\s*)?coins?$`)
+	rewardRoleRegex     = regexp.MustCompile(`(?i)^(?:<@&)?(\d{15,21})>?(?:\s+for\s+(\S+))?$`)
+	rewardQtyLeftRegex  = regexp.MustCompile(`(?i)^(\d+)\s*x\s+(.+)$`)
+	rewardQtyRightRegex = regexp.MustCompile(`(?i)^(.+?)\s+x\s*(\d+)$`)
+	legacyCoinsRegex    = regexp.MustCompile(`(?i)(\d+)\s*(?:exe\s*)?coins`)
+)
+
+// ItemLookup finds a shop item by name or ID, returning nil if there's no such item
+type ItemLookup func(nameOrID string) (*models.ShopItem, error)
+
+// ParseRewards parses a comma separated reward list, at most one of each kind:
+//
+//	500 coins              coins added to the winner's balance
+//	2x VIP Pass / VIP x2   a shop item (by name or ID) added to the inventory
+//	@Role [for 7d]         a role, optionally taken back after the duration
+func ParseRewards(input string, lookup ItemLookup) (*models.Reward, error) {
+	reward := &models.Reward{}
+	for _, term := range strings.Split(input, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		if m := rewardCoinsRegex.FindStringSubmatch(term); m != nil {
+			coins, err := strconv.ParseInt(m[1], 10, 64)
+			if err != nil || coins < 1 {
+				return nil, fmt.Errorf("invalid coin amount `%s`", term)
+			}
+			if reward.Coins > 0 {
+				return nil, fmt.Errorf("only one coin reward is allowed")
+			}
+			reward.Coins = coins
+			continue
+		}
+
+		if m := rewardRoleRegex.FindStringSubmatch(term); m != nil {
+			if reward.RoleID != "" {
+				return nil, fmt.Errorf("only one role reward is allowed")
+			}
+			reward.RoleID = m[1]
+			if m[2] != "" {
+				d, err := ParseDays(m[2])
+				if err != nil || d <= 0 {
+					return nil, fmt.Errorf("invalid role duration `%s` (use e.g. `7d` or `12h`)", m[2])
+				}
+				reward.RoleDuration = d.Milliseconds()
+			}
+			continue
+		}
+
+		name, qty := term, 1
+		if m := rewardQtyLeftRegex.FindStringSubmatch(term); m != nil {
+			name, qty = m[2], atoiOr(m[1], 0)
+		} else if m := rewardQtyRightRegex.FindStringSubmatch(term); m != nil {
+			name, qty = m[1], atoiOr(m[2], 0)
+		}
+		if qty < 1 || qty > MaxRewardQuantity {
+			return nil, fmt.Errorf("item quantity must be between 1 and %d", MaxRewardQuantity)
+		}
+		if reward.ItemID != 0 {
+			return nil, fmt.Errorf("only one shop item reward is allowed")
+		}
+		item, err := lookup(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		if item == nil {
+			return nil, fmt.Errorf("unknown reward `%s` (use `500 coins`, `2x Item Name` or `@Role for 7d`)", term)
+		}
+		reward.ItemID, reward.ItemName, reward.ItemQuantity = item.ID, item.Name, qty
+	}
+
+	if reward.Empty() {
+		return nil, fmt.Errorf("no rewards given")
+	}
+	return reward, nil
+}
+
+func atoiOr(s string, fallback int) int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return fallback
+	}
+	return n
+}
+
+// ParseEntryFee parses an entry fee with an optional mode for the collected fees:
+// "100" (kept), "100 refund" (losers get theirs back) or "100 pot" (split between the winners)
+func ParseEntryFee(input string) (int, string, error) {
+	fields := strings.Fields(strings.ToLower(input))
+	if len(fields) == 0 || len(fields) > 2 {
+		return 0, "", fmt.Errorf("invalid entry fee `%s` (use `100`, `100 refund` or `100 pot`)", input)
+	}
+	fee, err := strconv.Atoi(fields[0])
+	if err != nil || fee < 0 {
+		return 0, "", fmt.Errorf("invalid entry fee `%s` (use `100`, `100 refund` or `100 pot`)", input)
+	}
+	mode := models.FeeModeKeep
+	if len(fields) == 2 {
+		switch fields[1] {
+		case "keep":
+		case "refund", "refund_losers":
+			mode = models.FeeModeRefundLosers
+		case "pot":
+			mode = models.FeeModePot
+		default:
+			return 0, "", fmt.Errorf("unknown fee mode `%s` (use `refund` or `pot`)", fields[1])
+		}
+	}
+	return fee, mode, nil
+}
+
+// FeeModeLabel explains what happens to a giveaway's fees, "" for the default
+func FeeModeLabel(mode string) string {
+	switch mode {
+	case models.FeeModeRefundLosers:
+		return "refunded if you don't win"
+	case models.FeeModePot:
+		return "split between the winners"
+	default:
+		return ""
+	}
+}
+
+// LegacyCoinReward matches "100 coins", "500 exe coins", etc. (case insensitive)
+// in a free-text prize, for giveaways without a structured reward
+func LegacyCoinReward(prize string) *models.Reward {
+	matches := legacyCoinsRegex.FindStringSubmatch(prize)
+	if len(matches) > 1 {
+		amount, err := strconv.ParseInt(matches[1], 10, 64)
+		if err == nil && amount > 0 {
+			return &models.Reward{Coins: amount}
+		}
+	}
+	return nil
+}
+
+// RewardSummary renders a reward for embeds and announcements
+func RewardSummary(r *models.Reward, currencyEmoji string) string {
+	if r.Empty() {
+		return ""
+	}
+	var parts []string
+	if r.Coins > 0 {
+		parts = append(parts, fmt.Sprintf("**%d** %s", r.Coins, currencyEmoji))
+	}
+	if r.ItemID != 0 {
+		parts = append(parts, fmt.Sprintf("**%dx %s**", r.ItemQuantity, r.ItemName))
+	}
+	if r.RoleID != "" {
+		role := fmt.Sprintf("<@&%s>", r.RoleID)
+		if r.RoleDuration > 0 {
+			role += " for " + FormatDays(r.RoleDuration)
+		}
+		parts = append(parts, role)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatDays renders a millisecond duration as "7d", "12h" or "1d12h" (see ParseDays)
+func FormatDays(ms int64) string {
+	d := time.Duration(ms) * time.Millisecond
+	days := d / (24 * time.Hour)
+	rest := d % (24 * time.Hour)
+	if rest == 0 {
+		return fmt.Sprintf("%dd", days)
+	}
+	s := strings.TrimSuffix(rest.String(), "0s")
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	if days > 0 {
+		return fmt.Sprintf("%dd%s", days, s)
+	}
+	return s
+}
@@ -4,116 +4,848 @@ import (
 	"discord-giveaway-bot/internal/database"
 	"discord-giveaway-bot/internal/models"
 	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
 
+// Requirement engine. Every requirement type implements Requirement and is
+// built from its stored models.RequirementSpec by a registered factory.
+// Requirements compose into AND/OR groups (models.RequirementGroup); the
+// giveaway's single-valued requirement fields are folded into the root AND
+// group so old and new requirements are checked the same way.
+
 type RequirementResult struct {
 	Passed bool
 	Reason string
 }
 
-func CheckAllRequirements(s *discordgo.Session, db *database.Database, guildID, userID string, g *models.Giveaway) (*RequirementResult, error) {
+// RequirementContext is what a requirement can look at for one member.
+// Stats are loaded on first use and shared by every check.
+type RequirementContext struct {
+	Session  *discordgo.Session
+	DB       *database.Database
+	GuildID  string
+	UserID   string
+	Member   *discordgo.Member
+	Giveaway *models.Giveaway
+	// Drawing is set when re-validating at draw time rather than on entry
+	Drawing bool
+
+	stats       *models.UserStats
+	statsLoaded bool
+	activity    map[int]*models.UserStats
+}
+
+// Stats returns the member's activity stats, or nil if they couldn't be loaded
+func (rc *RequirementContext) Stats() *models.UserStats {
+	if !rc.statsLoaded {
+		rc.statsLoaded = true
+		stats, err := rc.DB.GetUserStats(rc.GuildID, rc.UserID)
+		if err != nil {
+			log.Printf("Error loading stats for %s: %v", rc.UserID, err)
+		}
+		rc.stats = stats
+	}
+	return rc.stats
+}
+
+// Activity returns the member's activity within a window (see
+// models.RequirementSpec.Window), or nil if it couldn't be loaded
+func (rc *RequirementContext) Activity(window int) *models.UserStats {
+	if window == 0 {
+		return rc.Stats()
+	}
+	if stats, ok := rc.activity[window]; ok {
+		return stats
+	}
+
+	since := time.Now().AddDate(0, 0, -window).UnixMilli()
+	if window == models.RequirementWindowGiveaway {
+		since = GiveawayStart(rc.Giveaway)
+	}
+	stats, err := rc.DB.GetActivitySince(rc.GuildID, rc.UserID, since)
+	if err != nil {
+		log.Printf("Error loading activity for %s: %v", rc.UserID, err)
+		stats = nil
+	}
+	if rc.activity == nil {
+		rc.activity = make(map[int]*models.UserStats)
+	}
+	rc.activity[window] = stats
+	return stats
+}
+
+// GiveawayStart is when the giveaway opened for entries (ms)
+func GiveawayStart(g *models.Giveaway) int64 {
+	if g.StartTime > 0 {
+		return g.StartTime
+	}
+	return g.CreatedAt
+}
+
+// HasRole reports whether the member has the role
+func (rc *RequirementContext) HasRole(roleID string) bool {
+	for _, id := range rc.Member.Roles {
+		if id == roleID {
+			return true
+		}
+	}
+	return false
+}
+
+// Requirement is one entry condition
+type Requirement interface {
+	// Check reports whether the member passes, with the reason shown when they don't
+	Check(rc *RequirementContext) (bool, string)
+	// Describe renders the requirement for the giveaway embed
+	Describe() string
+}
+
+// RequirementFactory builds a requirement from its stored spec
+type RequirementFactory func(spec models.RequirementSpec) (Requirement, error)
+
+var requirementTypes = map[string]RequirementFactory{}
+
+// RegisterRequirement makes a requirement type available to groups and the parser
+func RegisterRequirement(kind string, factory RequirementFactory) {
+	requirementTypes[kind] = factory
+}
+
+// NewRequirement builds the requirement for a stored spec
+func NewRequirement(spec models.RequirementSpec) (Requirement, error) {
+	factory, ok := requirementTypes[spec.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown requirement type `%s`", spec.Type)
+	}
+	return factory(spec)
+}
+
+func init() {
+	RegisterRequirement(models.RequirementTypeRole, func(spec models.RequirementSpec) (Requirement, error) {
+		if len(spec.Roles) != 1 {
+			return nil, fmt.Errorf("`role` takes exactly one role")
+		}
+		return roleRequirement{roleID: spec.Roles[0]}, nil
+	})
+	RegisterRequirement(models.RequirementTypeAnyRoles, rolesFactory(func(roles []string) Requirement { return anyRolesRequirement{roles} }))
+	RegisterRequirement(models.RequirementTypeAllRoles, rolesFactory(func(roles []string) Requirement { return allRolesRequirement{roles} }))
+	RegisterRequirement(models.RequirementTypeNoRoles, rolesFactory(func(roles []string) Requirement { return blacklistRolesRequirement{roles} }))
+	RegisterRequirement(models.RequirementTypeAccountAge, thresholdFactory(func(n int) Requirement { return accountAgeRequirement{n} }))
+	RegisterRequirement(models.RequirementTypeServerAge, thresholdFactory(func(n int) Requirement { return serverAgeRequirement{n} }))
+	RegisterRequirement(models.RequirementTypeMessages, activityFactory(func(n, window int) Requirement { return messagesRequirement{n, window} }))
+	RegisterRequirement(models.RequirementTypeVoice, activityFactory(func(n, window int) Requirement { return voiceRequirement{n, window} }))
+	RegisterRequirement(models.RequirementTypeInvites, thresholdFactory(func(n int) Requirement { return invitesRequirement{n} }))
+	RegisterRequirement(models.RequirementTypeBalance, thresholdFactory(func(n int) Requirement { return balanceRequirement{n} }))
+	RegisterRequirement(models.RequirementTypeMessageTier, thresholdFactory(func(n int) Requirement { return messageTierRequirement{n} }))
+	RegisterRequirement(models.RequirementTypeLevelLegacy, thresholdFactory(func(n int) Requirement { return messageTierRequirement{n} }))
+	RegisterRequirement(models.RequirementTypeCaptcha, func(models.RequirementSpec) (Requirement, error) {
+		return captchaRequirement{}, nil
+	})
+}
+
+func rolesFactory(build func(roles []string) Requirement) RequirementFactory {
+	return func(spec models.RequirementSpec) (Requirement, error) {
+		if len(spec.Roles) == 0 {
+			return nil, fmt.Errorf("`%s` needs at least one role", spec.Type)
+		}
+		return build(spec.Roles), nil
+	}
+}
+
+func thresholdFactory(build func(n int) Requirement) RequirementFactory {
+	return func(spec models.RequirementSpec) (Requirement, error) {
+		if spec.Value < 1 || spec.Value > math.MaxInt32 {
+			return nil, fmt.Errorf("`%s` needs a positive number", spec.Type)
+		}
+		return build(int(spec.Value)), nil
+	}
+}
+
+func activityFactory(build func(n, window int) Requirement) RequirementFactory {
+	threshold := thresholdFactory(func(int) Requirement { return nil })
+	return func(spec models.RequirementSpec) (Requirement, error) {
+		if _, err := threshold(spec); err != nil {
+			return nil, err
+		}
+		if spec.Window < models.RequirementWindowGiveaway || spec.Window > models.ActivityRetentionDays {
+			return nil, fmt.Errorf("activity windows can be at most %d days", models.ActivityRetentionDays)
+		}
+		return build(int(spec.Value), spec.Window), nil
+	}
+}
+
+// Role requirements
+
+type roleRequirement struct{ roleID string }
+
+func (r roleRequirement) Check(rc *RequirementContext) (bool, string) {
+	return rc.HasRole(r.roleID), fmt.Sprintf("You need the <@&%s> role to enter", r.roleID)
+}
+
+func (r roleRequirement) Describe() string {
+	return fmt.Sprintf("**Required Role:** <@&%s>", r.roleID)
+}
+
+type anyRolesRequirement struct{ roles []string }
+
+func (r anyRolesRequirement) Check(rc *RequirementContext) (bool, string) {
+	for _, id := range r.roles {
+		if rc.HasRole(id) {
+			return true, ""
+		}
+	}
+	return false, fmt.Sprintf("You need one of these roles: %s", roleMentions(r.roles, ", "))
+}
+
+func (r anyRolesRequirement) Describe() string {
+	return "**Any Role:** " + roleMentions(r.roles, " / ")
+}
+
+type allRolesRequirement struct{ roles []string }
+
+func (r allRolesRequirement) Check(rc *RequirementContext) (bool, string) {
+	var missing []string
+	for _, id := range r.roles {
+		if !rc.HasRole(id) {
+			missing = append(missing, id)
+		}
+	}
+	return len(missing) == 0, fmt.Sprintf("You're missing these roles: %s", roleMentions(missing, ", "))
+}
+
+func (r allRolesRequirement) Describe() string {
+	return "**All Roles:** " + roleMentions(r.roles, " + ")
+}
+
+type blacklistRolesRequirement struct{ roles []string }
+
+func (r blacklistRolesRequirement) Check(rc *RequirementContext) (bool, string) {
+	for _, id := range r.roles {
+		if rc.HasRole(id) {
+			return false, fmt.Sprintf("Members with the <@&%s> role can't enter", id)
+		}
+	}
+	return true, ""
+}
+
+func (r blacklistRolesRequirement) Describe() string {
+	return "**Blacklisted Roles:** " + roleMentions(r.roles, ", ")
+}
+
+// Age requirements
+
+type accountAgeRequirement struct{ days int }
+
+func (r accountAgeRequirement) Check(rc *RequirementContext) (bool, string) {
+	// Discord IDs are snowflakes, so the creation time is in the ID
+	creationTime, err := discordgo.SnowflakeTimestamp(rc.UserID)
+	if err != nil {
+		return true, ""
+	}
+	ageDays := int(time.Since(creationTime).Hours() / 24)
+	return ageDays >= r.days, fmt.Sprintf("Your account must be at least %d days old (yours is %d days)", r.days, ageDays)
+}
+
+func (r accountAgeRequirement) Describe() string {
+	return fmt.Sprintf("**Account Age:** %d+ days", r.days)
+}
+
+type serverAgeRequirement struct{ days int }
+
+func (r serverAgeRequirement) Check(rc *RequirementContext) (bool, string) {
+	ageDays := int(time.Since(rc.Member.JoinedAt).Hours() / 24)
+	return ageDays >= r.days, fmt.Sprintf("You must be a member for at least %d days (you've been here %d days)", r.days, ageDays)
+}
+
+func (r serverAgeRequirement) Describe() string {
+	return fmt.Sprintf("**Server Age:** %d+ days", r.days)
+}
+
+// Activity requirements (pass when stats are unavailable rather than lock everyone out)
+
+type messagesRequirement struct{ count, window int }
+
+func (r messagesRequirement) Check(rc *RequirementContext) (bool, string) {
+	if deferredUntilDraw(rc, r.window) {
+		return true, ""
+	}
+	stats := rc.Activity(r.window)
+	if stats == nil {
+		return true, ""
+	}
+	return stats.MessageCount >= r.count, fmt.Sprintf("You need at least %d messages%s (you have %d)", r.count, windowLabel(r.window), stats.MessageCount)
+}
+
+func (r messagesRequirement) Describe() string {
+	return fmt.Sprintf("**Messages:** %d+%s", r.count, windowLabel(r.window))
+}
+
+type voiceRequirement struct{ minutes, window int }
+
+func (r voiceRequirement) Check(rc *RequirementContext) (bool, string) {
+	if deferredUntilDraw(rc, r.window) {
+		return true, ""
+	}
+	stats := rc.Activity(r.window)
+	if stats == nil {
+		return true, ""
+	}
+	return stats.VoiceMinutes >= r.minutes, fmt.Sprintf("You need at least %d minutes in voice chat%s (you have %d)", r.minutes, windowLabel(r.window), stats.VoiceMinutes)
+}
+
+func (r voiceRequirement) Describe() string {
+	return fmt.Sprintf("**Voice Time:** %d+ mins%s", r.minutes, windowLabel(r.window))
+}
+
+// deferredUntilDraw reports whether a requirement on activity during the
+// giveaway should wait for the draw: nobody has any when they enter.
+func deferredUntilDraw(rc *RequirementContext, window int) bool {
+	return window == models.RequirementWindowGiveaway && !rc.Drawing
+}
+
+func windowLabel(window int) string {
+	switch {
+	case window == models.RequirementWindowGiveaway:
+		return " during the giveaway (from its first full hour)"
+	case window == 1:
+		return " in the last day"
+	case window > 1:
+		return fmt.Sprintf(" in the last %d days", window)
+	}
+	return ""
+}
+
+// MessageTier buckets a member's tracked messages into tiers: tier n takes
+// 10·n² messages. It's derived from message counts only, not a leveling system.
+func MessageTier(messages int) int {
+	return int(math.Sqrt(float64(messages) / 10))
+}
+
+type messageTierRequirement struct{ tier int }
+
+func (r messageTierRequirement) Check(rc *RequirementContext) (bool, string) {
+	stats := rc.Stats()
+	if stats == nil {
+		return true, ""
+	}
+	return MessageTier(stats.MessageCount) >= r.tier,
+		fmt.Sprintf("You need message tier %d (%d messages, you have %d)", r.tier, 10*r.tier*r.tier, stats.MessageCount)
+}
+
+func (r messageTierRequirement) Describe() string {
+	return fmt.Sprintf("**Message tier:** %d+ (%d messages)", r.tier, 10*r.tier*r.tier)
+}
+
+type invitesRequirement struct{ count int }
+
+func (r invitesRequirement) Check(rc *RequirementContext) (bool, string) {
+	// Regular invites from invite tracking: fakes, leaves and rejoins don't count
+	stats, err := rc.DB.GetInviteStats(rc.GuildID, rc.UserID)
+	if err != nil {
+		// Fail safely if we can't check invites
+		log.Printf("Error checking invites: %v", err)
+		return true, ""
+	}
+	return stats.Regular >= r.count, fmt.Sprintf("You need at least %d invites (you have %d)", r.count, stats.Regular)
+}
+
+func (r invitesRequirement) Describe() string {
+	return fmt.Sprintf("**Invites:** %d+", r.count)
+}
+
+type balanceRequirement struct{ coins int }
+
+func (r balanceRequirement) Check(rc *RequirementContext) (bool, string) {
+	user, err := rc.DB.GetEconomyUser(rc.GuildID, rc.UserID)
+	if err != nil {
+		log.Printf("Error checking balance for %s: %v", rc.UserID, err)
+		return true, ""
+	}
+	return user.Balance >= int64(r.coins), fmt.Sprintf("You need at least %d coins (you have %d)", r.coins, user.Balance)
+}
+
+func (r balanceRequirement) Describe() string {
+	return fmt.Sprintf("**Balance:** %d+ coins", r.coins)
+}
+
+// captchaRequirement always passes here: the captcha is solved interactively
+// after every other requirement (see RequiresCaptcha)
+type captchaRequirement struct{}
+
+func (captchaRequirement) Check(*RequirementContext) (bool, string) { return true, "" }
+
+func (captchaRequirement) Describe() string { return "**Captcha Verification**" }
+
+// Evaluation
+
+// EffectiveRequirements returns the giveaway's full requirement tree: the
+// single-valued fields as an AND group, with the composable groups inside it
+func EffectiveRequirements(g *models.Giveaway) models.RequirementGroup {
+	root := models.RequirementGroup{Mode: models.RequirementModeAll}
+	add := func(kind string, value int) {
+		if value > 0 {
+			root.Requirements = append(root.Requirements, models.RequirementSpec{Type: kind, Value: int64(value)})
+		}
+	}
+
+	if g.RoleRequirement != "" {
+		root.Requirements = append(root.Requirements, models.RequirementSpec{Type: models.RequirementTypeRole, Roles: []string{g.RoleRequirement}})
+	}
+	add(models.RequirementTypeInvites, g.InviteRequirement)
+	add(models.RequirementTypeAccountAge, g.AccountAgeRequirement)
+	add(models.RequirementTypeServerAge, g.ServerAgeRequirement)
+	add(models.RequirementTypeMessages, g.MessageRequired)
+	add(models.RequirementTypeVoice, g.VoiceRequirement)
+	if g.CaptchaRequirement {
+		root.Requirements = append(root.Requirements, models.RequirementSpec{Type: models.RequirementTypeCaptcha})
+	}
+
+	if !g.Requirements.Empty() {
+		if g.Requirements.Mode == models.RequirementModeAll {
+			root.Requirements = append(root.Requirements, g.Requirements.Requirements...)
+			root.Groups = append(root.Groups, g.Requirements.Groups...)
+		} else {
+			root.Groups = append(root.Groups, *g.Requirements)
+		}
+	}
+	return root
+}
+
+// RequiresCaptcha reports whether entering takes a captcha
+func RequiresCaptcha(g *models.Giveaway) bool {
+	return g.CaptchaRequirement || groupHasType(g.Requirements, models.RequirementTypeCaptcha)
+}
+
+func groupHasType(group *models.RequirementGroup, kind string) bool {
+	if group == nil {
+		return false
+	}
+	for _, spec := range group.Requirements {
+		if spec.Type == kind {
+			return true
+		}
+	}
+	for i := range group.Groups {
+		if groupHasType(&group.Groups[i], kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckRequirements checks whether a member may enter the giveaway
+func CheckRequirements(s *discordgo.Session, db *database.Database, guildID, userID string, g *models.Giveaway) (*RequirementResult, error) {
 	member, err := s.GuildMember(guildID, userID)
 	if err != nil {
 		return nil, err
 	}
+	return checkRequirements(s, db, g, member, false)
+}
 
-	// Role Requirement
-	if g.RoleRequirement != "" {
-		hasRole := false
-		for _, roleID := range member.Roles {
-			if roleID == g.RoleRequirement {
-				hasRole = true
-				break
-			}
+// CheckRequirementsAtDraw re-checks a drawn winner, including the requirements
+// on activity during the giveaway that entry leaves for the draw
+func CheckRequirementsAtDraw(s *discordgo.Session, db *database.Database, g *models.Giveaway, member *discordgo.Member) (*RequirementResult, error) {
+	return checkRequirements(s, db, g, member, true)
+}
+
+func checkRequirements(s *discordgo.Session, db *database.Database, g *models.Giveaway, member *discordgo.Member, drawing bool) (*RequirementResult, error) {
+	rc := &RequirementContext{Session: s, DB: db, GuildID: g.GuildID, UserID: member.User.ID, Member: member, Giveaway: g, Drawing: drawing}
+	for _, roleID := range g.BypassRoles {
+		if rc.HasRole(roleID) {
+			return &RequirementResult{Passed: true}, nil
 		}
-		if !hasRole {
-			return &RequirementResult{
-				Passed: false,
-				Reason: fmt.Sprintf("You need the <@&%s> role to enter", g.RoleRequirement),
-			}, nil
+	}
+
+	root := EffectiveRequirements(g)
+	passed, reason, err := checkGroup(&root, rc)
+	if err != nil {
+		return nil, err
+	}
+	return &RequirementResult{Passed: passed, Reason: reason}, nil
+}
+
+func checkGroup(group *models.RequirementGroup, rc *RequirementContext) (bool, string, error) {
+	var reasons []string
+	check := func(passed bool, reason string) bool {
+		if !passed {
+			reasons = append(reasons, reason)
 		}
+		return passed
 	}
 
-	// Invite Requirement
-	if g.InviteRequirement > 0 {
-		invites, err := s.GuildInvites(guildID)
+	for _, spec := range group.Requirements {
+		req, err := NewRequirement(spec)
 		if err != nil {
-			// Fail safely if we can't check invites
-			fmt.Printf("Error checking invites: %v\n", err)
-		} else {
-			userInvites := 0
-			for _, inv := range invites {
-				if inv.Inviter != nil && inv.Inviter.ID == userID {
-					userInvites += inv.Uses
-				}
+			return false, "", err
+		}
+		passed, reason := req.Check(rc)
+		if check(passed, reason) == (group.Mode == models.RequirementModeAny) {
+			if passed {
+				return true, "", nil
 			}
-			if userInvites < g.InviteRequirement {
-				return &RequirementResult{
-					Passed: false,
-					Reason: fmt.Sprintf("You need at least %d invites (you have %d)", g.InviteRequirement, userInvites),
-				}, nil
+			return false, reason, nil
+		}
+	}
+	for i := range group.Groups {
+		passed, reason, err := checkGroup(&group.Groups[i], rc)
+		if err != nil {
+			return false, "", err
+		}
+		if check(passed, reason) == (group.Mode == models.RequirementModeAny) {
+			if passed {
+				return true, "", nil
 			}
+			return false, reason, nil
 		}
 	}
 
-	// Account Age Requirement
-	if g.AccountAgeRequirement > 0 {
-		// Discord IDs are snowflakes. We can extract creation time.
-		// But discordgo User struct doesn't have CreatedAt helper directly on Member.User?
-		// Actually we can calculate it from ID.
-		creationTime, err := discordgo.SnowflakeTimestamp(userID)
-		if err == nil {
-			age := time.Since(creationTime)
-			ageDays := int(age.Hours() / 24)
-			if ageDays < g.AccountAgeRequirement {
-				return &RequirementResult{
-					Passed: false,
-					Reason: fmt.Sprintf("Your account must be at least %d days old (yours is %d days)", g.AccountAgeRequirement, ageDays),
-				}, nil
-			}
+	if group.Mode == models.RequirementModeAny && len(reasons) > 0 {
+		return false, "Meet at least one of: " + strings.Join(reasons, " **or** "), nil
+	}
+	return true, "", nil
+}
+
+// Rendering
+
+// RequirementLines renders the giveaway's requirements for its embed, one bullet per line
+func RequirementLines(g *models.Giveaway) []string {
+	root := EffectiveRequirements(g)
+
+	var lines []string
+	for _, spec := range root.Requirements {
+		lines = append(lines, "• "+describeSpec(spec))
+	}
+	for i := range root.Groups {
+		lines = append(lines, "• "+describeGroup(&root.Groups[i], false))
+	}
+	if len(lines) > 0 && len(g.BypassRoles) > 0 {
+		lines = append(lines, "• **Bypass:** "+roleMentions(g.BypassRoles, ", ")+" skip all requirements")
+	}
+	return lines
+}
+
+func describeSpec(spec models.RequirementSpec) string {
+	req, err := NewRequirement(spec)
+	if err != nil {
+		return "Invalid requirement"
+	}
+	return req.Describe()
+}
+
+func describeGroup(group *models.RequirementGroup, nested bool) string {
+	var parts []string
+	for _, spec := range group.Requirements {
+		parts = append(parts, describeSpec(spec))
+	}
+	for i := range group.Groups {
+		parts = append(parts, "("+describeGroup(&group.Groups[i], true)+")")
+	}
+
+	if group.Mode == models.RequirementModeAny {
+		if nested {
+			return strings.Join(parts, " **or** ")
 		}
+		return "One of: " + strings.Join(parts, " **or** ")
 	}
+	return strings.Join(parts, " **and** ")
+}
+
+func roleMentions(roles []string, sep string) string {
+	mentions := make([]string, len(roles))
+	for i, id := range roles {
+		mentions[i] = fmt.Sprintf("<@&%s>", id)
+	}
+	return strings.Join(mentions, sep)
+}
+
+// Parsing
+//
+//	messages:100 and (voice:60@7d or message_tier:5) and not:<@&123456789012345678>
+//
+// AND binds tighter than OR; parentheses group. Terms are type:value, see
+// requirementAliases for the accepted names. Role lists are comma separated.
+// Activity terms take an optional window: @7d for the last 7 days, or
+// @giveaway for activity since the giveaway started. Windows count whole
+// hours from the first full hour inside them.
+
+// MaxRequirements caps how many requirements one giveaway can have
+const MaxRequirements = 25
+
+var requirementAliases = map[string]string{
+	"role":            models.RequirementTypeRole,
+	"any":             models.RequirementTypeAnyRoles,
+	"any_roles":       models.RequirementTypeAnyRoles,
+	"all":             models.RequirementTypeAllRoles,
+	"all_roles":       models.RequirementTypeAllRoles,
+	"not":             models.RequirementTypeNoRoles,
+	"blacklist":       models.RequirementTypeNoRoles,
+	"blacklist_roles": models.RequirementTypeNoRoles,
+	"account_age":     models.RequirementTypeAccountAge,
+	"server_age":      models.RequirementTypeServerAge,
+	"messages":        models.RequirementTypeMessages,
+	"voice":           models.RequirementTypeVoice,
+	"invites":         models.RequirementTypeInvites,
+	"balance":         models.RequirementTypeBalance,
+	"message_tier":    models.RequirementTypeMessageTier,
+	"tier":            models.RequirementTypeMessageTier,
+	"captcha":         models.RequirementTypeCaptcha,
+}
 
-	// Server Age Requirement
-	if g.ServerAgeRequirement > 0 {
-		age := time.Since(member.JoinedAt)
-		ageDays := int(age.Hours() / 24)
-		if ageDays < g.ServerAgeRequirement {
-			return &RequirementResult{
-				Passed: false,
-				Reason: fmt.Sprintf("You must be a member for at least %d days (you've been here %d days)", g.ServerAgeRequirement, ageDays),
-			}, nil
+var roleIDRegex = regexp.MustCompile(`^(?:<@&)?(\d{15,21})>?$`)
+
+// ParseRoleList parses role mentions or IDs separated by spaces or commas
+func ParseRoleList(input string) ([]string, error) {
+	var roles []string
+	for _, token := range strings.FieldsFunc(input, func(r rune) bool { return r == ' ' || r == ',' }) {
+		m := roleIDRegex.FindStringSubmatch(token)
+		if m == nil {
+			return nil, fmt.Errorf("invalid role `%s`", token)
 		}
+		roles = append(roles, m[1])
 	}
+	return roles, nil
+}
 
-	// Message Requirement
-	if g.MessageRequired > 0 {
-		stats, err := db.GetUserStats(guildID, userID)
-		if err == nil {
-			if stats.MessageCount < g.MessageRequired {
-				return &RequirementResult{
-					Passed: false,
-					Reason: fmt.Sprintf("You need at least %d messages (you have %d)", g.MessageRequired, stats.MessageCount),
-				}, nil
-			}
+// ParseRequirements parses a requirement expression into a group
+func ParseRequirements(input string) (*models.RequirementGroup, error) {
+	input = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(input)
+	p := &requirementParser{tokens: strings.Fields(input)}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("no requirements given")
+	}
+
+	group, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected `%s`", p.tokens[p.pos])
+	}
+	if p.count > MaxRequirements {
+		return nil, fmt.Errorf("a giveaway can have at most %d requirements", MaxRequirements)
+	}
+	if group.Mode == "" {
+		group.Mode = models.RequirementModeAll
+	}
+	if err := checkCaptchaPlacement(group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// checkCaptchaPlacement only allows captcha as a plain top-level AND term.
+// The captcha runs after everything else passed, so it can't be one option
+// of an OR or sit inside a group.
+func checkCaptchaPlacement(group *models.RequirementGroup) error {
+	if group.Mode == models.RequirementModeAny && groupHasType(group, models.RequirementTypeCaptcha) {
+		return fmt.Errorf("`captcha` can't be part of an `or`. Add it with `and` at the top level")
+	}
+	for i := range group.Groups {
+		if groupHasType(&group.Groups[i], models.RequirementTypeCaptcha) {
+			return fmt.Errorf("`captcha` can't be inside parentheses. Add it with `and` at the top level")
+		}
+	}
+	return nil
+}
+
+type requirementParser struct {
+	tokens []string
+	pos    int
+	count  int
+}
+
+func (p *requirementParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return strings.ToLower(p.tokens[p.pos])
+	}
+	return ""
+}
+
+// parseOr parses and-chains separated by "or"
+func (p *requirementParser) parseOr() (*models.RequirementGroup, error) {
+	var branches []*models.RequirementGroup
+	for {
+		branch, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		branches = append(branches, branch)
+		if p.peek() != "or" {
+			break
+		}
+		p.pos++
+	}
+	if len(branches) == 1 {
+		return branches[0], nil
+	}
+
+	group := &models.RequirementGroup{Mode: models.RequirementModeAny}
+	for _, b := range branches {
+		addToGroup(group, b)
+	}
+	return group, nil
+}
+
+// parseAnd parses terms separated by "and"
+func (p *requirementParser) parseAnd() (*models.RequirementGroup, error) {
+	group := &models.RequirementGroup{Mode: models.RequirementModeAll}
+	for {
+		term, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		addToGroup(group, term)
+		if p.peek() != "and" {
+			break
+		}
+		p.pos++
+	}
+	// A lone term keeps the mode of whatever it was (e.g. a parenthesized OR)
+	if len(group.Requirements) == 0 && len(group.Groups) == 1 {
+		return &group.Groups[0], nil
+	}
+	return group, nil
+}
+
+func (p *requirementParser) parseTerm() (*models.RequirementGroup, error) {
+	token := p.peek()
+	switch token {
+	case "":
+		return nil, fmt.Errorf("expected a requirement at the end")
+	case "(":
+		p.pos++
+		group, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing `)`")
 		}
+		p.pos++
+		return group, nil
+	case ")", "and", "or":
+		return nil, fmt.Errorf("unexpected `%s`", token)
 	}
 
-	// Voice Requirement
-	if g.VoiceRequirement > 0 {
-		stats, err := db.GetUserStats(guildID, userID)
-		if err == nil {
-			if stats.VoiceMinutes < g.VoiceRequirement {
-				return &RequirementResult{
-					Passed: false,
-					Reason: fmt.Sprintf("You need at least %d minutes in voice chat (you have %d)", g.VoiceRequirement, stats.VoiceMinutes),
-				}, nil
+	p.pos++
+	p.count++
+	spec, err := parseRequirementTerm(p.tokens[p.pos-1])
+	if err != nil {
+		return nil, err
+	}
+	return &models.RequirementGroup{Mode: models.RequirementModeAll, Requirements: []models.RequirementSpec{spec}}, nil
+}
+
+// addToGroup merges child into group, flattening single requirements and same-mode groups
+func addToGroup(group, child *models.RequirementGroup) {
+	if len(child.Groups) == 0 && len(child.Requirements) == 1 || child.Mode == group.Mode {
+		group.Requirements = append(group.Requirements, child.Requirements...)
+		group.Groups = append(group.Groups, child.Groups...)
+		return
+	}
+	group.Groups = append(group.Groups, *child)
+}
+
+func parseRequirementTerm(term string) (models.RequirementSpec, error) {
+	name, value, _ := strings.Cut(term, ":")
+	kind, ok := requirementAliases[strings.ToLower(name)]
+	if !ok {
+		return models.RequirementSpec{}, fmt.Errorf("unknown requirement `%s`", name)
+	}
+
+	spec := models.RequirementSpec{Type: kind}
+	if kind == models.RequirementTypeMessages || kind == models.RequirementTypeVoice {
+		var window string
+		var ok bool
+		if value, window, ok = strings.Cut(value, "@"); ok {
+			w, err := parseWindow(window)
+			if err != nil {
+				return spec, err
 			}
+			spec.Window = w
+		}
+	}
+
+	switch kind {
+	case models.RequirementTypeCaptcha:
+	case models.RequirementTypeRole, models.RequirementTypeAnyRoles, models.RequirementTypeAllRoles, models.RequirementTypeNoRoles:
+		roles, err := ParseRoleList(value)
+		if err != nil {
+			return spec, err
+		}
+		spec.Roles = roles
+	default:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return spec, fmt.Errorf("`%s` needs a number, e.g. `%s:10`", name, name)
 		}
+		spec.Value = n
+	}
+
+	// Validate through the factory so bad specs never get stored
+	if _, err := NewRequirement(spec); err != nil {
+		return spec, err
+	}
+	return spec, nil
+}
+
+// parseWindow parses "giveaway" or a number of days like "7d"
+func parseWindow(window string) (int, error) {
+	window = strings.ToLower(window)
+	if window == "giveaway" {
+		return models.RequirementWindowGiveaway, nil
+	}
+	days, err := strconv.Atoi(strings.TrimSuffix(window, "d"))
+	if err != nil || days < 1 {
+		return 0, fmt.Errorf("invalid window `@%s`. Use @giveaway or a number of days like @7d", window)
 	}
+	return days, nil
+}
+
+// FormatRequirements renders a group back into the expression syntax
+func FormatRequirements(group *models.RequirementGroup) string {
+	if group.Empty() {
+		return "none"
+	}
+	return formatGroup(group)
+}
 
-	return &RequirementResult{Passed: true}, nil
+func formatGroup(group *models.RequirementGroup) string {
+	var parts []string
+	for _, spec := range group.Requirements {
+		parts = append(parts, formatSpec(spec))
+	}
+	for i := range group.Groups {
+		parts = append(parts, "("+formatGroup(&group.Groups[i])+")")
+	}
+	sep := " and "
+	if group.Mode == models.RequirementModeAny {
+		sep = " or "
+	}
+	return strings.Join(parts, sep)
+}
+
+func formatSpec(spec models.RequirementSpec) string {
+	if spec.Type == models.RequirementTypeLevelLegacy {
+		spec.Type = models.RequirementTypeMessageTier
+	}
+	switch spec.Type {
+	case models.RequirementTypeCaptcha:
+		return spec.Type
+	case models.RequirementTypeRole, models.RequirementTypeAnyRoles, models.RequirementTypeAllRoles, models.RequirementTypeNoRoles:
+		return spec.Type + ":" + roleMentions(spec.Roles, ",")
+	}
+	switch {
+	case spec.Window == models.RequirementWindowGiveaway:
+		return fmt.Sprintf("%s:%d@giveaway", spec.Type, spec.Value)
+	case spec.Window > 0:
+		return fmt.Sprintf("%s:%d@%dd", spec.Type, spec.Value, spec.Window)
+	}
+	return fmt.Sprintf("%s:%d", spec.Type, spec.Value)
 }
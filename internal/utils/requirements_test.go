@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"discord-giveaway-bot/internal/models"
+	"reflect"
+	"testing"
+)
+
+const (
+	roleA = "123456789012345678"
+	roleB = "223456789012345678"
+)
+
+func TestParseRequirements(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  *models.RequirementGroup
+	}{
+		{
+			name:  "single term",
+			input: "messages:100",
+			want: &models.RequirementGroup{Mode: models.RequirementModeAll, Requirements: []models.RequirementSpec{
+				{Type: models.RequirementTypeMessages, Value: 100},
+			}},
+		},
+		{
+			name:  "and binds tighter than or",
+			input: "messages:10 and voice:5 or invites:2",
+			want: &models.RequirementGroup{Mode: models.RequirementModeAny,
+				Requirements: []models.RequirementSpec{{Type: models.RequirementTypeInvites, Value: 2}},
+				Groups: []models.RequirementGroup{{Mode: models.RequirementModeAll, Requirements: []models.RequirementSpec{
+					{Type: models.RequirementTypeMessages, Value: 10},
+					{Type: models.RequirementTypeVoice, Value: 5},
+				}}},
+			},
+		},
+		{
+			name:  "parentheses, windows and roles",
+			input: "any:<@&" + roleA + ">," + roleB + " AND (messages:50@giveaway OR voice:60@7d)",
+			want: &models.RequirementGroup{Mode: models.RequirementModeAll,
+				Requirements: []models.RequirementSpec{{Type: models.RequirementTypeAnyRoles, Roles: []string{roleA, roleB}}},
+				Groups: []models.RequirementGroup{{Mode: models.RequirementModeAny, Requirements: []models.RequirementSpec{
+					{Type: models.RequirementTypeMessages, Value: 50, Window: models.RequirementWindowGiveaway},
+					{Type: models.RequirementTypeVoice, Value: 60, Window: 7},
+				}}},
+			},
+		},
+		{
+			name:  "aliases and top-level captcha",
+			input: "blacklist:" + roleA + " and tier:3 and captcha",
+			want: &models.RequirementGroup{Mode: models.RequirementModeAll, Requirements: []models.RequirementSpec{
+				{Type: models.RequirementTypeNoRoles, Roles: []string{roleA}},
+				{Type: models.RequirementTypeMessageTier, Value: 3},
+				{Type: models.RequirementTypeCaptcha},
+			}},
+		},
+		{
+			name:  "redundant parentheses flatten",
+			input: "((messages:1)) and (voice:2 and invites:3)",
+			want: &models.RequirementGroup{Mode: models.RequirementModeAll, Requirements: []models.RequirementSpec{
+				{Type: models.RequirementTypeMessages, Value: 1},
+				{Type: models.RequirementTypeVoice, Value: 2},
+				{Type: models.RequirementTypeInvites, Value: 3},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRequirements(tt.input)
+			if err != nil {
+				t.Fatalf("ParseRequirements(%q) error = %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRequirements(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRequirementsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"empty", "   "},
+		{"unknown type", "karma:5"},
+		{"role name instead of mention", "not:@Muted"},
+		{"missing number", "messages:lots"},
+		{"bad window", "messages:5@week"},
+		{"zero day window", "voice:5@0d"},
+		{"unclosed parenthesis", "(messages:5 or voice:5"},
+		{"stray parenthesis", "messages:5)"},
+		{"dangling and", "messages:5 and"},
+		{"leading or", "or messages:5"},
+		{"captcha in or", "captcha or messages:5"},
+		{"captcha nested in or", "messages:5 or (voice:5 and captcha)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := ParseRequirements(tt.input); err == nil {
+				t.Errorf("ParseRequirements(%q) = %+v, want an error", tt.input, got)
+			}
+		})
+	}
+}
+
+func TestFormatRequirementsRoundTrip(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"messages:100", "messages:100"},
+		{"MESSAGES:100@7D", "messages:100@7d"},
+		{"voice:30@giveaway and captcha", "voice:30@giveaway and captcha"},
+		{"role:" + roleA + " or all:" + roleA + "," + roleB, "role:<@&" + roleA + "> or all_roles:<@&" + roleA + ">,<@&" + roleB + ">"},
+		{"message_tier:2 and (balance:500 or invites:3)", "message_tier:2 and (balance:500 or invites:3)"},
+		{"(messages:1 or voice:1) and (invites:1 or server_age:1)", "(messages:1 or voice:1) and (invites:1 or server_age:1)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			group, err := ParseRequirements(tt.input)
+			if err != nil {
+				t.Fatalf("ParseRequirements(%q) error = %v", tt.input, err)
+			}
+			formatted := FormatRequirements(group)
+			if formatted != tt.want {
+				t.Errorf("FormatRequirements() = %q, want %q", formatted, tt.want)
+			}
+
+			reparsed, err := ParseRequirements(formatted)
+			if err != nil {
+				t.Fatalf("formatted %q doesn't parse: %v", formatted, err)
+			}
+			if !reflect.DeepEqual(reparsed, group) {
+				t.Errorf("round trip changed the group: %+v, want %+v", reparsed, group)
+			}
+		})
+	}
+}
+
+func TestFormatRequirementsLegacyLevel(t *testing.T) {
+	group := &models.RequirementGroup{Mode: models.RequirementModeAll, Requirements: []models.RequirementSpec{
+		{Type: models.RequirementTypeLevelLegacy, Value: 2},
+	}}
+	if got := FormatRequirements(group); got != "message_tier:2" {
+		t.Errorf("FormatRequirements(level) = %q, want message_tier:2", got)
+	}
+}
+
+func TestFormatRequirementsEmpty(t *testing.T) {
+	if got := FormatRequirements(&models.RequirementGroup{}); got != "none" {
+		t.Errorf("FormatRequirements(empty) = %q, want none", got)
+	}
+}
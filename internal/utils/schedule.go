@@ -0,0 +1,243 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxScheduleAhead is how far in the future a giveaway can be scheduled
+const MaxScheduleAhead = 90 * 24 * time.Hour
+
+var discordTimestampRegex = regexp.MustCompile(`^<t:(\d+)(?::[tTdDfFR])?>$`)
+
+// ParseDays parses a duration like time.ParseDuration, also accepting a leading
+// day component ("3d", "1d12h")
+func ParseDays(input string) (time.Duration, error) {
+	input = strings.TrimSpace(input)
+	var days time.Duration
+	if i := strings.Index(input, "d"); i > 0 {
+		n, err := strconv.Atoi(input[:i])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", input)
+		}
+		days = time.Duration(n) * 24 * time.Hour
+		input = input[i+1:]
+		if input == "" {
+			return days, nil
+		}
+	}
+	d, err := time.ParseDuration(input)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", input)
+	}
+	return days + d, nil
+}
+
+// ParseStartTime parses when a scheduled giveaway should start (see ParseFutureTime),
+// at most MaxScheduleAhead from now
+func ParseStartTime(input string, now time.Time) (time.Time, error) {
+	start, err := ParseFutureTime(input, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if start.Sub(now) > MaxScheduleAhead {
+		return time.Time{}, fmt.Errorf("giveaways can be scheduled at most %d days ahead", int(MaxScheduleAhead.Hours()/24))
+	}
+	return start, nil
+}
+
+// ParseFutureTime parses a relative duration ("2h", "3d"), a Discord timestamp
+// (<t:1700000000:f>), a unix timestamp in seconds, or "YYYY-MM-DD HH:MM" in UTC
+func ParseFutureTime(input string, now time.Time) (time.Time, error) {
+	input = strings.TrimSpace(input)
+
+	var start time.Time
+	if m := discordTimestampRegex.FindStringSubmatch(input); m != nil {
+		sec, _ := strconv.ParseInt(m[1], 10, 64)
+		start = time.Unix(sec, 0)
+	} else if sec, err := strconv.ParseInt(input, 10, 64); err == nil {
+		start = time.Unix(sec, 0)
+	} else if t, err := time.Parse("2006-01-02 15:04", input); err == nil {
+		start = t
+	} else if d, err := ParseDays(input); err == nil {
+		start = now.Add(d)
+	} else {
+		return time.Time{}, fmt.Errorf("invalid time. Use 2h, 3d, a Discord timestamp or YYYY-MM-DD HH:MM (UTC)")
+	}
+
+	if !start.After(now) {
+		return time.Time{}, fmt.Errorf("the time must be in the future")
+	}
+	return start, nil
+}
+
+// MinRecurrenceInterval is the shortest interval a recurring giveaway may use
+const MinRecurrenceInterval = time.Hour
+
+// Recurrence computes the start times of a recurring giveaway
+type Recurrence interface {
+	// Next returns the first start at or after `after`; anchor is the series' first start
+	Next(anchor, after time.Time) time.Time
+}
+
+// ParseRecurrence parses "daily", "weekly", "every <duration>" (e.g. "every 12h",
+// "every 3d") or a 5-field cron expression ("0 18 * * FRI") evaluated in UTC
+func ParseRecurrence(spec string) (Recurrence, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	switch spec {
+	case "daily":
+		return intervalRecurrence(24 * time.Hour), nil
+	case "weekly":
+		return intervalRecurrence(7 * 24 * time.Hour), nil
+	}
+
+	if rest, ok := strings.CutPrefix(spec, "every "); ok {
+		d, err := ParseDays(rest)
+		if err != nil {
+			return nil, err
+		}
+		if d < MinRecurrenceInterval {
+			return nil, fmt.Errorf("recurring giveaways can repeat at most once per hour")
+		}
+		return intervalRecurrence(d), nil
+	}
+
+	return parseCron(spec)
+}
+
+// intervalRecurrence repeats at a fixed interval from the series anchor
+type intervalRecurrence time.Duration
+
+func (r intervalRecurrence) Next(anchor, after time.Time) time.Time {
+	if !after.After(anchor) {
+		return anchor
+	}
+	every := time.Duration(r)
+	steps := (after.Sub(anchor) + every - 1) / every
+	return anchor.Add(steps * every)
+}
+
+// cronRecurrence matches "minute hour day-of-month month day-of-week"
+type cronRecurrence struct {
+	minute, hour, dom, month, dow uint64 // Bitsets of allowed values
+	domAny, dowAny                bool
+}
+
+var cronNames = strings.NewReplacer(
+	"sun", "0", "mon", "1", "tue", "2", "wed", "3", "thu", "4", "fri", "5", "sat", "6",
+	"jan", "1", "feb", "2", "mar", "3", "apr", "4", "may", "5", "jun", "6",
+	"jul", "7", "aug", "8", "sep", "9", "oct", "10", "nov", "11", "dec", "12",
+)
+
+func parseCron(spec string) (Recurrence, error) {
+	fields := strings.Fields(cronNames.Replace(spec))
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule. Use daily, weekly, every 12h or a cron expression like `0 18 * * 5`")
+	}
+
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	var sets [5]uint64
+	for i, field := range fields {
+		set, err := parseCronField(field, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron field %q: %v", field, err)
+		}
+		sets[i] = set
+	}
+	// Both 0 and 7 mean Sunday
+	if sets[4]&(1<<7) != 0 {
+		sets[4] |= 1
+	}
+	// A single minute keeps the schedule to at most once per hour
+	if strings.ContainsAny(fields[0], ",-*/") {
+		return nil, fmt.Errorf("recurring giveaways can repeat at most once per hour, use a single minute")
+	}
+
+	return &cronRecurrence{
+		minute: sets[0], hour: sets[1], dom: sets[2], month: sets[3], dow: sets[4],
+		domAny: fields[2] == "*", dowAny: fields[4] == "*",
+	}, nil
+}
+
+// parseCronField parses "*", "5", "1-5", "*/15", "1-10/2" and comma lists into a bitset
+func parseCronField(field string, min, max int) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("bad step")
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		if rangePart != "*" {
+			from, to, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = strconv.Atoi(from); err != nil {
+				return 0, fmt.Errorf("bad value")
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(to); err != nil {
+					return 0, fmt.Errorf("bad range")
+				}
+			} else if hasStep {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("out of range")
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+func (r *cronRecurrence) dayMatches(t time.Time) bool {
+	dom := r.dom&(1<<uint(t.Day())) != 0
+	dow := r.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case r.domAny && r.dowAny:
+		return true
+	case r.domAny:
+		return dow
+	case r.dowAny:
+		return dom
+	}
+	// Like cron, restricting both fields matches either
+	return dom || dow
+}
+
+func (r *cronRecurrence) Next(_, after time.Time) time.Time {
+	t := after.UTC().Truncate(time.Minute)
+	if t.Before(after) {
+		t = t.Add(time.Minute)
+	}
+
+	// Give up after 5 years (e.g. "0 0 30 2 *" never matches)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case r.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !r.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case r.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, time.UTC)
+		case r.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDays(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"2h", 2 * time.Hour, false},
+		{"3d", 72 * time.Hour, false},
+		{"1d12h", 36 * time.Hour, false},
+		{" 90m ", 90 * time.Minute, false},
+		{"d", 0, true},
+		{"xd", 0, true},
+		{"1d2", 0, true},
+		{"soon", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDays(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDays(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDays(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFutureTime(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"relative", "2h", now.Add(2 * time.Hour), false},
+		{"relative days", "3d", now.AddDate(0, 0, 3), false},
+		{"discord timestamp", "<t:1710086400:f>", time.Unix(1710086400, 0), false},
+		{"bare discord timestamp", "<t:1710086400>", time.Unix(1710086400, 0), false},
+		{"unix seconds", "1710086400", time.Unix(1710086400, 0), false},
+		{"date and time", "2024-03-11 18:30", time.Date(2024, 3, 11, 18, 30, 0, 0, time.UTC), false},
+		{"past", "2024-03-09 18:30", time.Time{}, true},
+		{"now", "1710072000", time.Time{}, true},
+		{"garbage", "next friday", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFutureTime(tt.input, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFutureTime(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseFutureTime(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseStartTimeLimit(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	if _, err := ParseStartTime("90d", now); err != nil {
+		t.Errorf("ParseStartTime(90d) error = %v", err)
+	}
+	if _, err := ParseStartTime("91d", now); err == nil {
+		t.Error("ParseStartTime(91d) accepted a start past MaxScheduleAhead")
+	}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	// A Friday
+	anchor := time.Date(2024, 3, 8, 18, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		spec  string
+		after time.Time
+		want  time.Time
+	}{
+		{"daily before anchor", "daily", anchor.Add(-time.Hour), anchor},
+		{"daily", "daily", anchor.Add(time.Minute), anchor.AddDate(0, 0, 1)},
+		{"daily on the dot", "daily", anchor.AddDate(0, 0, 2), anchor.AddDate(0, 0, 2)},
+		{"weekly", "weekly", anchor.Add(time.Hour), anchor.AddDate(0, 0, 7)},
+		{"every 12h", "every 12h", anchor.Add(13 * time.Hour), anchor.Add(24 * time.Hour)},
+		{"every 3d", "every 3d", anchor.Add(time.Hour), anchor.AddDate(0, 0, 3)},
+		{"cron weekday name", "0 18 * * FRI", anchor.Add(time.Minute), anchor.AddDate(0, 0, 7)},
+		{"cron same minute", "0 18 * * 5", anchor, anchor},
+		{"cron rounds up seconds", "30 9 * * *", time.Date(2024, 3, 8, 9, 29, 30, 0, time.UTC), time.Date(2024, 3, 8, 9, 30, 0, 0, time.UTC)},
+		{"cron hour range", "15 9-17/4 * * *", time.Date(2024, 3, 8, 14, 0, 0, 0, time.UTC), time.Date(2024, 3, 8, 17, 15, 0, 0, time.UTC)},
+		{"cron day of month", "0 0 1 * *", anchor, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"cron leap day", "0 12 29 feb *", anchor, time.Date(2028, 2, 29, 12, 0, 0, 0, time.UTC)},
+		{"cron sunday as 7", "0 10 * * 7", anchor, time.Date(2024, 3, 10, 10, 0, 0, 0, time.UTC)},
+		{"cron dom or dow", "0 0 15 * 1", anchor, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)},
+		{"cron never", "0 0 30 2 *", anchor, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ParseRecurrence(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseRecurrence(%q) error = %v", tt.spec, err)
+			}
+			if got := r.Next(anchor, tt.after); !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.after, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRecurrenceErrors(t *testing.T) {
+	tests := []string{
+		"hourly",
+		"every 30m",
+		"every soon",
+		"0 18 * *",
+		"*/15 * * * *",
+		"0,30 * * * *",
+		"0 24 * * *",
+		"0 9 0 * *",
+		"0 9 * 13 *",
+		"0 9 * * 8",
+		"0 9 5-1 * *",
+		"0 9/0 * * *",
+	}
+	for _, spec := range tests {
+		t.Run(spec, func(t *testing.T) {
+			if _, err := ParseRecurrence(spec); err == nil {
+				t.Errorf("ParseRecurrence(%q) accepted an invalid schedule", spec)
+			}
+		})
+	}
+}
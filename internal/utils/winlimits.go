@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"discord-giveaway-bot/internal/models"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseWinLimits parses win limits, separated by spaces or commas:
+//
+//	3/30d          at most 3 wins per rolling 30 days
+//	cooldown:7d    no wins for 7 days after winning
+//	none           no limits
+func ParseWinLimits(input string) (*models.WinLimits, error) {
+	limits := &models.WinLimits{}
+	for _, token := range strings.FieldsFunc(strings.ToLower(input), func(r rune) bool { return r == ' ' || r == ',' }) {
+		if token == "none" {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(token, "cooldown:"); ok {
+			d, err := ParseDays(rest)
+			if err != nil || d <= 0 {
+				return nil, fmt.Errorf("invalid cooldown `%s` (use e.g. `cooldown:7d`)", token)
+			}
+			limits.Cooldown = d.Milliseconds()
+			continue
+		}
+		count, period, ok := strings.Cut(token, "/")
+		if !ok {
+			return nil, fmt.Errorf("invalid win limit `%s` (use `3/30d`, `cooldown:7d` or `none`)", token)
+		}
+		n, err := strconv.Atoi(count)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid win count in `%s`", token)
+		}
+		d, err := ParseDays(period)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid period in `%s`", token)
+		}
+		limits.MaxWins, limits.Period = n, d.Milliseconds()
+	}
+	return limits, nil
+}
+
+// DescribeWinLimits renders win limits for embeds and replies
+func DescribeWinLimits(l *models.WinLimits) string {
+	if l.Empty() {
+		return "no limits"
+	}
+	var parts []string
+	if l.MaxWins > 0 && l.Period > 0 {
+		parts = append(parts, fmt.Sprintf("%d win(s) per %s", l.MaxWins, FormatDays(l.Period)))
+	}
+	if l.Cooldown > 0 {
+		parts = append(parts, fmt.Sprintf("%s cooldown after winning", FormatDays(l.Cooldown)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatWinLimits renders win limits back into ParseWinLimits syntax
+func FormatWinLimits(l *models.WinLimits) string {
+	if l == nil {
+		return "server default"
+	}
+	if l.Empty() {
+		return "none"
+	}
+	var parts []string
+	if l.MaxWins > 0 && l.Period > 0 {
+		parts = append(parts, fmt.Sprintf("%d/%s", l.MaxWins, FormatDays(l.Period)))
+	}
+	if l.Cooldown > 0 {
+		parts = append(parts, "cooldown:"+FormatDays(l.Cooldown))
+	}
+	return strings.Join(parts, " ")
+}
@@ -22,9 +22,12 @@ import (
 )
 
 type Config struct {
-	Token    string                  `json:"token"`
-	Redis    redis.Config            `json:"redis"`
-	Postgres database.PostgresConfig `json:"postgres"`
+	Token       string                  `json:"token"`
+	Redis       redis.Config            `json:"redis"`
+	Postgres    database.PostgresConfig `json:"postgres"`
+	MetricsAddr string                  `json:"metrics_addr"` // Prometheus /metrics listener (default 127.0.0.1:9090; set 0.0.0.0:9090 to expose it)
+
+	AlertWebhookHosts []string `json:"alert_webhook_hosts"` // Non-Discord hosts antinuke alert webhooks may use
 }
 
 func main() {
@@ -156,11 +159,19 @@ func main() {
 
 	// Initialize logger with Discord session (channel mapping set below)
 	acl.InitLogger(b.Session)
+	acl.SetAlertWebhookHosts(config.AlertWebhookHosts)
 
 	// Initialize and start audit log monitor
 	auditor := auditor.New(b.Session, eventRing)
 	auditor.Start()
 
+	// Expose Prometheus metrics
+	metricsAddr := config.MetricsAddr
+	if metricsAddr == "" {
+		metricsAddr = "127.0.0.1:9090"
+	}
+	b.StartMetricsServer(metricsAddr, eventRing)
+
 	log.Println("✅ Engine initialization complete")
 	log.Println("   • ACL Workers: Running")
 	log.Println("   • CDE Workers:", numWorkers)
@@ -183,6 +194,7 @@ func main() {
 		if len(r.Guilds) == 0 {
 			log.Println("⚠️  WARNING: Bot is not in any guilds!")
 			log.Println("   Please invite the bot to a server to use antinuke features")
+			cde.MarkConfigsLoaded(0)
 			return
 		}
 
@@ -213,6 +225,16 @@ func main() {
 				continue
 			}
 
+			// Load webhook allowlist for the ACL webhook cleanup
+			if entries, err := db.GetWebhookAllowEntries(guildID); err == nil && len(entries) > 0 {
+				appIDs := make([]string, 0, len(entries))
+				for _, e := range entries {
+					appIDs = append(appIDs, e.ApplicationID)
+				}
+				acl.SetWebhookAllowlist(guildID, appIDs)
+				log.Printf("   🪝 Webhook Allowlist: %d integration(s)", len(appIDs))
+			}
+
 			// Get log channel and configure logger
 			config, err := db.GetAntiNukeConfig(guildID)
 			if err == nil {
@@ -227,6 +249,19 @@ func main() {
 				} else {
 					log.Printf("   💤 AntiNuke: DISABLED")
 				}
+
+				// Owner DMs and alert fallbacks
+				notifyCfg := acl.NotifyConfig{
+					NotifyOwner:     config.NotifyOwner,
+					FallbackChannel: config.FallbackChannel,
+					WebhookURL:      config.AlertWebhookURL,
+				}
+				if owners, err := db.GetExtraOwners(guildID); err == nil {
+					for _, o := range owners {
+						notifyCfg.ExtraOwners = append(notifyCfg.ExtraOwners, o.UserID)
+					}
+				}
+				acl.SetGuildNotifyConfig(guildID, notifyCfg)
 				successCount++
 			} else {
 				log.Printf("   ⚠️  Config not found, using defaults")
@@ -235,6 +270,8 @@ func main() {
 			log.Println("")
 		}
 
+		cde.MarkConfigsLoaded(successCount)
+
 		elapsed := time.Since(startTime)
 		log.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 		log.Printf("✅ Configuration Loading Complete:")